@@ -0,0 +1,74 @@
+package ydb
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Default ports used when dialing address without an explicit port.
+const (
+	DefaultGRPCPort  = 2135
+	DefaultGRPCSPort = 2136
+)
+
+// dialAddress is a parsed address passed to the Dialer.Dial().
+type dialAddress struct {
+	// hostPort is a "host:port" pair.
+	hostPort string
+
+	// database is a database name passed as the "database" query parameter
+	// of URL-like address.
+	database string
+
+	// secure reports whether TLS must be used; it is true for the "grpcs"
+	// scheme.
+	secure bool
+}
+
+// parseDialAddress parses addr which may be either "host[:port]" pair or
+// URL-like "scheme://host[:port][/?database=name]" string with "grpc" or
+// "grpcs" scheme.
+//
+// If addr contains no port then the port is inferred from the scheme: the
+// DefaultGRPCSPort is used for the "grpcs" scheme (or when secure is true)
+// and the DefaultGRPCPort otherwise.
+func parseDialAddress(addr string, secure bool) (a dialAddress, err error) {
+	a.secure = secure
+	host := addr
+	if strings.Contains(addr, "://") {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return a, fmt.Errorf("ydb: malformed address %q: %v", addr, err)
+		}
+		switch u.Scheme {
+		case "grpc":
+		case "grpcs":
+			a.secure = true
+		default:
+			return a, fmt.Errorf("ydb: unsupported address scheme: %q", u.Scheme)
+		}
+		if p := strings.Trim(u.Path, "/"); p != "" {
+			return a, fmt.Errorf("ydb: unexpected address path: %q", u.Path)
+		}
+		a.database = u.Query().Get("database")
+		host = u.Host
+	}
+	if host == "" {
+		return a, fmt.Errorf("ydb: empty host in address %q", addr)
+	}
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		a.hostPort = host
+		return a, nil
+	}
+	port := DefaultGRPCPort
+	if a.secure {
+		port = DefaultGRPCSPort
+	}
+	// Trim brackets of IPv6 address without port.
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	a.hostPort = net.JoinHostPort(host, strconv.Itoa(port))
+	return a, nil
+}
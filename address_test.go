@@ -0,0 +1,73 @@
+package ydb
+
+import "testing"
+
+func TestParseDialAddress(t *testing.T) {
+	for _, test := range []struct {
+		addr   string
+		secure bool
+		exp    dialAddress
+		err    bool
+	}{
+		{
+			addr: "localhost:2136",
+			exp:  dialAddress{hostPort: "localhost:2136"},
+		},
+		{
+			addr: "localhost",
+			exp:  dialAddress{hostPort: "localhost:2135"},
+		},
+		{
+			addr:   "localhost",
+			secure: true,
+			exp:    dialAddress{hostPort: "localhost:2136", secure: true},
+		},
+		{
+			addr: "::1",
+			exp:  dialAddress{hostPort: "[::1]:2135"},
+		},
+		{
+			addr: "grpcs://ydb.example.net/?database=/ru/home/db",
+			exp: dialAddress{
+				hostPort: "ydb.example.net:2136",
+				database: "/ru/home/db",
+				secure:   true,
+			},
+		},
+		{
+			addr: "grpc://[::1]:42?database=db",
+			exp: dialAddress{
+				hostPort: "[::1]:42",
+				database: "db",
+			},
+		},
+		{
+			addr: "http://localhost",
+			err:  true,
+		},
+		{
+			addr: "grpc://localhost/db",
+			err:  true,
+		},
+		{
+			addr: "grpc://",
+			err:  true,
+		},
+	} {
+		t.Run(test.addr, func(t *testing.T) {
+			act, err := parseDialAddress(test.addr, test.secure)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.exp {
+				t.Errorf("unexpected address: %+v; want %+v", act, test.exp)
+			}
+		})
+	}
+}
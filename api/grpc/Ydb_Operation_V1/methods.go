@@ -0,0 +1,9 @@
+// Package Ydb_Operation_V1 contains method names of the YDB Operation service.
+package Ydb_Operation_V1
+
+const (
+	GetOperation    = "/Ydb.Operation.V1.OperationService/GetOperation"
+	CancelOperation = "/Ydb.Operation.V1.OperationService/CancelOperation"
+	ForgetOperation = "/Ydb.Operation.V1.OperationService/ForgetOperation"
+	ListOperations  = "/Ydb.Operation.V1.OperationService/ListOperations"
+)
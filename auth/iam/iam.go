@@ -62,6 +62,9 @@ type Client struct {
 	token   string
 	expires time.Time
 
+	// deadline is the token expiration time received from the server.
+	deadline time.Time
+
 	// transport is a stub used for tests.
 	transport transport
 }
@@ -118,6 +121,7 @@ func (c *Client) Token(ctx context.Context) (token string, err error) {
 		}
 	}
 	c.token = token
+	c.deadline = expires
 	c.expires = now.Add(c.TokenTTL)
 	if expires.Before(c.expires) {
 		c.expires = expires
@@ -125,6 +129,28 @@ func (c *Client) Token(ctx context.Context) (token string, err error) {
 	return token, nil
 }
 
+// InvalidateToken drops cached token if it is equal to the given one. That
+// is, the next Token() call requests a new token.
+func (c *Client) InvalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+		c.expires = time.Time{}
+	}
+}
+
+// TokenExpiresAt returns expiration time of the cached token as reported by
+// the IAM service. It returns zero time if there is no cached token.
+func (c *Client) TokenExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.token == "" {
+		return time.Time{}
+	}
+	return c.deadline
+}
+
 func (c *Client) expired() bool {
 	return c.expires.Sub(timeutil.Now()) <= 0
 }
@@ -0,0 +1,62 @@
+package iam
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// DefaultEndpoint is the default address of the iam service.
+const DefaultEndpoint = "iam.api.cloud.yandex.net:443"
+
+// serviceAccountKey is a service account authorized key as it is stored in
+// JSON file created by the yc tool.
+type serviceAccountKey struct {
+	ID               string `json:"id"`
+	ServiceAccountID string `json:"service_account_id"`
+	PrivateKey       string `json:"private_key"`
+}
+
+// NewClientFromKeyFile returns Client which issues tokens of the service
+// account using authorized key from the JSON file with given name.
+//
+// Returned Client uses DefaultEndpoint and system certificate pool. Note that
+// exported fields of the Client could be changed before the first Token()
+// call.
+func NewClientFromKeyFile(name string) (*Client, error) {
+	p, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("iam: read key file: %v", err)
+	}
+	return NewClientFromKey(p)
+}
+
+// NewClientFromKey is like NewClientFromKeyFile but uses JSON representation
+// of the authorized key instead of the file.
+func NewClientFromKey(data []byte) (*Client, error) {
+	var k serviceAccountKey
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("iam: malformed key: %v", err)
+	}
+	if k.ID == "" || k.ServiceAccountID == "" {
+		return nil, fmt.Errorf("iam: malformed key: key and account ids required")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(k.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("iam: malformed key: %v", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("iam: system cert pool: %v", err)
+	}
+	return &Client{
+		Endpoint: DefaultEndpoint,
+		CertPool: pool,
+		Key:      key,
+		KeyID:    k.ID,
+		Issuer:   k.ServiceAccountID,
+	}, nil
+}
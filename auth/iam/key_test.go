@@ -0,0 +1,48 @@
+package iam
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+)
+
+func TestNewClientFromKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(serviceAccountKey{
+		ID:               "key-id",
+		ServiceAccountID: "account-id",
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClientFromKey(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.KeyID != "key-id" || c.Issuer != "account-id" || c.Endpoint != DefaultEndpoint {
+		t.Errorf("unexpected client: %+v", c)
+	}
+	if c.Key.N.Cmp(key.N) != 0 {
+		t.Errorf("unexpected private key")
+	}
+
+	for _, data := range []string{
+		`{`,
+		`{"id": "key-id", "private_key": "secret"}`,
+		`{"id": "key-id", "service_account_id": "account-id", "private_key": "secret"}`,
+	} {
+		if _, err := NewClientFromKey([]byte(data)); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net"
 	"net/http"
 	"sync"
@@ -17,6 +18,32 @@ import (
 var (
 	DefaultAddr = "localhost:6770"
 	DefaultPath = "/latest/meta-data/iam/security-credentials/default"
+
+	// GoogleAddr and GooglePath are the default address and path of the
+	// Google Compute Engine compatible metadata service, which is provided
+	// both on Google Cloud and Yandex Cloud virtual machines and serverless
+	// containers.
+	GoogleAddr = "169.254.169.254:80"
+	GooglePath = "/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// DefaultRefreshMargin is the default value of the Client's RefreshMargin
+// field.
+const DefaultRefreshMargin = 5 * time.Minute
+
+// Flavor is the format of the metadata service API.
+type Flavor string
+
+const (
+	// FlavorDefault is the format of the metadata service returning token in
+	// the "Token" field along with "Code" and "Expiration" fields.
+	FlavorDefault Flavor = ""
+
+	// FlavorGoogle is the format of the Google Compute Engine compatible
+	// metadata service. Requests are sent with the "Metadata-Flavor: Google"
+	// header; token is returned in the "access_token" field along with its
+	// lifetime in the "expires_in" field.
+	FlavorGoogle Flavor = "Google"
 )
 
 const (
@@ -24,9 +51,28 @@ const (
 )
 
 type Client struct {
+	// Addr and Path are the address and path of the metadata service token
+	// endpoint. If they are empty, then the DefaultAddr and DefaultPath are
+	// used, or the GoogleAddr and GooglePath if Flavor is FlavorGoogle.
 	Addr string
 	Path string
 
+	// Flavor is the format of the metadata service API.
+	Flavor Flavor
+
+	// RefreshMargin is the time before token expiration when the token is
+	// requested again in background. While the new token is requested the
+	// cached one is returned by Token() calls.
+	//
+	// The actual refresh time is randomly shifted by up to a half of
+	// RefreshMargin earlier, such that many clients started at the same time
+	// do not request tokens simultaneously.
+	//
+	// If RefreshMargin is zero, then the DefaultRefreshMargin is used.
+	// If RefreshMargin is negative, then tokens are requested only after
+	// they expire.
+	RefreshMargin time.Duration
+
 	Trace ClientTrace
 	Dial  func(ctx context.Context, network, addr string) (net.Conn, error)
 
@@ -35,19 +81,25 @@ type Client struct {
 	reqBytes []byte
 	req      *http.Request
 
-	mu      sync.RWMutex
-	expires time.Time
-	token   string
+	mu        sync.RWMutex
+	expires   time.Time
+	refreshAt time.Time
+	token     string
 
 	promise *tokenPromise
 }
 
 func (c *Client) Token(ctx context.Context) (token string, err error) {
+	var refresh bool
 	c.mu.RLock()
 	if !c.expired() {
 		token = c.token
+		refresh = c.promise == nil && !c.refreshAt.After(timeutil.Now())
 	}
 	c.mu.RUnlock()
+	if refresh {
+		c.refresh()
+	}
 	if token != "" {
 		return
 	}
@@ -80,11 +132,71 @@ func (c *Client) Token(ctx context.Context) (token string, err error) {
 	}
 }
 
+// InvalidateToken drops cached token if it is equal to the given one. That
+// is, the next Token() call requests a new token.
+func (c *Client) InvalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+		c.expires = time.Time{}
+	}
+}
+
+// TokenExpiresAt returns expiration time of the cached token as reported by
+// the metadata service. It returns zero time if there is no cached token.
+func (c *Client) TokenExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.token == "" {
+		return time.Time{}
+	}
+	return c.expires
+}
+
 // c.mu must be held (any type).
 func (c *Client) expired() bool {
 	return c.expires.Before(timeutil.Now())
 }
 
+// refresh starts background request of a new token if cached token is going
+// to expire soon.
+func (c *Client) refresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := timeutil.Now()
+	if c.promise != nil || c.refreshAt.After(now) {
+		return
+	}
+	// Postpone the next attempt in case of failure. It is overwritten if
+	// request succeeds.
+	c.refreshAt = now.Add(c.refreshMargin() / 4)
+	c.obtain()
+}
+
+// c.mu must be held.
+func (c *Client) setToken(token string, expires time.Time) {
+	c.token = token
+	c.expires = expires
+	c.refreshAt = expires
+	m := c.refreshMargin()
+	if m <= 0 {
+		return
+	}
+	c.refreshAt = expires.Add(-m - time.Duration(rand.Int63n(int64(m/2)+1)))
+	// Do not refresh short-living tokens too often.
+	if half := timeutil.Now().Add(timeutil.Until(expires) / 2); c.refreshAt.Before(half) {
+		c.refreshAt = half
+	}
+}
+
+func (c *Client) refreshMargin() time.Duration {
+	if c.RefreshMargin == 0 {
+		return DefaultRefreshMargin
+	}
+	return c.RefreshMargin
+}
+
 type tokenPromise struct {
 	done    chan struct{}
 	token   string
@@ -137,8 +249,7 @@ func (c *Client) obtain() *tokenPromise {
 		}
 		c.promise = nil
 		if p.err == nil {
-			c.token = p.token
-			c.expires = p.expires
+			c.setToken(p.token, p.expires)
 		}
 	}()
 
@@ -151,6 +262,12 @@ type response struct {
 	Token      string
 }
 
+type googleResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
 func (c *Client) request(ctx context.Context) (
 	token string, expires time.Time, err error,
 ) {
@@ -185,6 +302,11 @@ func (c *Client) request(ctx context.Context) (
 	}
 	defer resp.Body.Close()
 
+	if c.Flavor == FlavorGoogle {
+		res.Code = resp.Status
+		return decodeGoogle(resp)
+	}
+
 	dec := json.NewDecoder(resp.Body)
 	err = dec.Decode(&res)
 	if err != nil {
@@ -202,6 +324,24 @@ func (c *Client) request(ctx context.Context) (
 	return res.Token, expires, nil
 }
 
+func decodeGoogle(resp *http.Response) (token string, expires time.Time, err error) {
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("metadata: unexpected status: %q", resp.Status)
+		return
+	}
+	now := timeutil.Now()
+	var res googleResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return
+	}
+	if res.AccessToken == "" {
+		err = fmt.Errorf("metadata: empty access token")
+		return
+	}
+	expires = now.Add(time.Duration(res.ExpiresIn) * time.Second)
+	return res.AccessToken, expires, nil
+}
+
 var zeroDialer net.Dialer
 
 func (c *Client) dial(ctx context.Context) (net.Conn, error) {
@@ -219,19 +359,24 @@ func (c *Client) dial(ctx context.Context) (net.Conn, error) {
 
 func (c *Client) init() {
 	c.once.Do(func() {
+		addr, path := DefaultAddr, DefaultPath
+		if c.Flavor == FlavorGoogle {
+			addr, path = GoogleAddr, GooglePath
+		}
 		c.addr = c.Addr
 		if c.addr == "" {
-			c.addr = DefaultAddr
+			c.addr = addr
 		}
-
-		path := c.Path
-		if path == "" {
-			path = DefaultPath
+		if c.Path != "" {
+			path = c.Path
 		}
 		var buf bytes.Buffer
 		req, err := http.NewRequest(http.MethodGet, path, nil)
 		if err == nil {
 			req.Host = c.addr
+			if c.Flavor != FlavorDefault {
+				req.Header.Set("Metadata-Flavor", string(c.Flavor))
+			}
 			err = req.Write(&buf)
 		}
 		if err != nil {
@@ -5,10 +5,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -238,6 +240,75 @@ func TestClientRequestChaining(t *testing.T) {
 	}
 }
 
+func TestClientGoogleRefresh(t *testing.T) {
+	shiftTime, cleanup := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanup()
+
+	requests := make(chan int, 1)
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if act, exp := r.URL.Path, GooglePath; act != exp {
+			t.Errorf("unexpected path: %q; want %q", act, exp)
+		}
+		if act, exp := r.Header.Get("Metadata-Flavor"), "Google"; act != exp {
+			t.Errorf("unexpected flavor header: %q; want %q", act, exp)
+		}
+		n++
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600,"token_type":"Bearer"}`, n)
+		requests <- n
+	}))
+	defer srv.Close()
+
+	c := Client{
+		Addr:   srv.Listener.Addr().String(),
+		Flavor: FlavorGoogle,
+	}
+	ctx := context.Background()
+	assertToken := func(exp string) {
+		t.Helper()
+		act, err := c.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Fatalf("unexpected token: %q; want %q", act, exp)
+		}
+	}
+
+	assertToken("token-1")
+	<-requests
+	if act, exp := c.TokenExpiresAt(), time.Unix(3600, 0); !act.Equal(exp) {
+		t.Fatalf("unexpected expiration time: %s; want %s", act, exp)
+	}
+
+	shiftTime(time.Hour - DefaultRefreshMargin*3/2 - time.Second)
+	assertToken("token-1")
+	select {
+	case <-requests:
+		t.Fatalf("unexpected token refresh")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Cached token must be returned while it is refreshed in background.
+	shiftTime(DefaultRefreshMargin)
+	assertToken("token-1")
+	select {
+	case <-requests:
+	case <-time.After(time.Second):
+		t.Fatalf("no token refresh")
+	}
+	for i := 0; ; i++ {
+		if c.TokenExpiresAt().Equal(time.Unix(3600, 0).Add(time.Hour - DefaultRefreshMargin/2 - time.Second)) {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("refreshed token was not stored")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertToken("token-2")
+}
+
 type tokenAndError struct {
 	token string
 	err   error
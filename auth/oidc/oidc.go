@@ -0,0 +1,265 @@
+/*
+Package oidc provides credentials obtaining access tokens by the OAuth 2.0
+token exchange (RFC 8693) of the OIDC tokens issued by third party identity
+providers.
+
+It is intended for keyless authentication of workloads such as Kubernetes
+pods, which are able to obtain service account token projected into the
+file:
+
+	c := &oidc.Client{
+		TokenFile: "/var/run/secrets/tokens/ydb-token",
+		Audience:  "ajeexampleserviceaccount",
+	}
+	db, err := ydb.Open(ctx, addr, ydb.WithCredentials(c))
+*/
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// Default Client parameters.
+const (
+	DefaultEndpoint         = "https://auth.yandex.cloud/oauth/token"
+	DefaultSubjectTokenType = "urn:ietf:params:oauth:token-type:id_token"
+	DefaultExpirationMargin = time.Minute
+)
+
+const (
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// ExchangeError contains reason of token exchange failure.
+type ExchangeError struct {
+	// StatusCode is the HTTP status code of the response. It is zero if
+	// request was not made or response was not received.
+	StatusCode int
+
+	// Code and Description are error code and its description returned by
+	// the server, if any.
+	Code        string
+	Description string
+
+	Reason error
+}
+
+// Error implements error interface.
+func (e *ExchangeError) Error() string {
+	switch {
+	case e.Reason != nil:
+		return fmt.Sprintf("oidc: token exchange error: %v", e.Reason)
+	case e.Code != "":
+		return fmt.Sprintf(
+			"oidc: token exchange error: %d %s: %s",
+			e.StatusCode, e.Code, e.Description,
+		)
+	default:
+		return fmt.Sprintf(
+			"oidc: token exchange error: unexpected status code %d",
+			e.StatusCode,
+		)
+	}
+}
+
+// Client exchanges subject token read from the file for the access token.
+// It implements ydb.Credentials interface.
+type Client struct {
+	// Endpoint is the URL of the token exchange endpoint.
+	// If Endpoint is empty, then the DefaultEndpoint is used.
+	Endpoint string
+
+	// TokenFile is the path to the file containing the subject token. The
+	// file is read before each exchange, such that rotated tokens are picked
+	// up.
+	TokenFile string
+
+	// SubjectTokenType is the type of the subject token.
+	// If SubjectTokenType is empty, then the DefaultSubjectTokenType is used.
+	SubjectTokenType string
+
+	// Audience is the logical name of the target service, such as service
+	// account id for the Yandex Cloud workload identity federation.
+	Audience string
+
+	// Scope is an optional space separated list of requested scopes.
+	Scope string
+
+	// ExpirationMargin is the time before access token expiration when the
+	// token is exchanged again. It is limited by a half of the token lifetime
+	// such that short-lived tokens are not exchanged on every Token() call.
+	// If ExpirationMargin is zero, then the DefaultExpirationMargin is used.
+	ExpirationMargin time.Duration
+
+	// HTTPClient is a client used to make requests.
+	// If HTTPClient is nil, then the http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	mu       sync.RWMutex
+	token    string
+	expires  time.Time
+	deadline time.Time
+}
+
+type response struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Token returns cached access token if it is not going to expire soon.
+// Otherwise, it exchanges the subject token for a new one.
+func (c *Client) Token(ctx context.Context) (token string, err error) {
+	c.mu.RLock()
+	if !c.expired() {
+		token = c.token
+	}
+	c.mu.RUnlock()
+	if token != "" {
+		return token, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.expired() {
+		return c.token, nil
+	}
+	now := timeutil.Now()
+	token, ttl, err := c.exchange(ctx)
+	if err != nil {
+		return "", err
+	}
+	margin := c.ExpirationMargin
+	if margin == 0 {
+		margin = DefaultExpirationMargin
+	}
+	c.token = token
+	if ttl > 0 {
+		if margin > ttl/2 {
+			margin = ttl / 2
+		}
+		c.deadline = now.Add(ttl)
+		c.expires = c.deadline.Add(-margin)
+	} else {
+		// Expiration time is unknown; keep the token for a short while.
+		c.deadline = time.Time{}
+		c.expires = now.Add(margin)
+	}
+	return token, nil
+}
+
+// InvalidateToken drops cached token if it is equal to the given one. That
+// is, the next Token() call exchanges token again.
+func (c *Client) InvalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+		c.expires = time.Time{}
+	}
+}
+
+// TokenExpiresAt returns expiration time of the cached access token. It
+// returns zero time if there is no cached token.
+func (c *Client) TokenExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.token == "" {
+		return time.Time{}
+	}
+	return c.deadline
+}
+
+// c.mu must be held (any type).
+func (c *Client) expired() bool {
+	return c.token == "" || !c.expires.After(timeutil.Now())
+}
+
+func (c *Client) exchange(ctx context.Context) (token string, ttl time.Duration, err error) {
+	subject, err := ioutil.ReadFile(c.TokenFile)
+	if err != nil {
+		return "", 0, &ExchangeError{Reason: err}
+	}
+	form := url.Values{
+		"grant_type":           {grantTypeTokenExchange},
+		"requested_token_type": {tokenTypeAccessToken},
+		"subject_token":        {strings.TrimSpace(string(subject))},
+		"subject_token_type":   {c.subjectTokenType()},
+	}
+	if c.Audience != "" {
+		form.Set("audience", c.Audience)
+	}
+	if c.Scope != "" {
+		form.Set("scope", c.Scope)
+	}
+	req, err := http.NewRequest(
+		http.MethodPost, c.endpoint(),
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", 0, &ExchangeError{Reason: err}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return "", 0, &ExchangeError{Reason: err}
+	}
+	defer resp.Body.Close()
+
+	var res response
+	decodeErr := json.NewDecoder(resp.Body).Decode(&res)
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, &ExchangeError{
+			StatusCode:  resp.StatusCode,
+			Code:        res.Error,
+			Description: res.ErrorDescription,
+		}
+	}
+	if decodeErr != nil {
+		return "", 0, &ExchangeError{
+			StatusCode: resp.StatusCode,
+			Reason:     decodeErr,
+		}
+	}
+	if res.AccessToken == "" {
+		return "", 0, &ExchangeError{
+			StatusCode: resp.StatusCode,
+			Reason:     fmt.Errorf("empty access token"),
+		}
+	}
+	return res.AccessToken, time.Duration(res.ExpiresIn) * time.Second, nil
+}
+
+func (c *Client) endpoint() string {
+	if c.Endpoint == "" {
+		return DefaultEndpoint
+	}
+	return c.Endpoint
+}
+
+func (c *Client) subjectTokenType() string {
+	if c.SubjectTokenType == "" {
+		return DefaultSubjectTokenType
+	}
+	return c.SubjectTokenType
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
@@ -0,0 +1,154 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+func TestClientToken(t *testing.T) {
+	shiftTime, cleanup := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "oidc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(file, []byte("subject-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		requests int
+		subjects []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if act, exp := r.PostForm.Get("grant_type"), grantTypeTokenExchange; act != exp {
+			t.Errorf("unexpected grant type: %q; want %q", act, exp)
+		}
+		if act, exp := r.PostForm.Get("audience"), "account"; act != exp {
+			t.Errorf("unexpected audience: %q; want %q", act, exp)
+		}
+		subject := r.PostForm.Get("subject_token")
+		subjects = append(subjects, subject)
+		if subject == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"bad token"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, requests)
+	}))
+	defer srv.Close()
+
+	c := &Client{
+		Endpoint:  srv.URL,
+		TokenFile: file,
+		Audience:  "account",
+	}
+	ctx := context.Background()
+	assertToken := func(exp string) {
+		t.Helper()
+		act, err := c.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Fatalf("unexpected token: %q; want %q", act, exp)
+		}
+	}
+
+	assertToken("token-1")
+	if act, exp := c.TokenExpiresAt(), time.Unix(3600, 0); !act.Equal(exp) {
+		t.Fatalf("unexpected expiration time: %s; want %s", act, exp)
+	}
+	shiftTime(time.Hour - DefaultExpirationMargin - time.Second)
+	assertToken("token-1")
+
+	// Token must be exchanged again when it is going to expire, reading the
+	// rotated subject token.
+	if err := ioutil.WriteFile(file, []byte("subject-2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	shiftTime(time.Second)
+	assertToken("token-2")
+
+	c.InvalidateToken("token-2")
+	assertToken("token-3")
+
+	if act, exp := fmt.Sprint(subjects), "[subject-1 subject-2 subject-2]"; act != exp {
+		t.Fatalf("unexpected subject tokens: %s; want %s", act, exp)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("bad"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c.InvalidateToken("token-3")
+	_, err = c.Token(ctx)
+	e, ok := err.(*ExchangeError)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.StatusCode != http.StatusBadRequest || e.Code != "invalid_grant" {
+		t.Fatalf("unexpected error: %+v", e)
+	}
+}
+
+func TestClientTokenShortLived(t *testing.T) {
+	shiftTime, cleanup := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanup()
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":60}`, requests)
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "oidc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(file, []byte("subject"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{
+		Endpoint:  srv.URL,
+		TokenFile: file,
+	}
+	ctx := context.Background()
+	assertToken := func(exp string) {
+		t.Helper()
+		act, err := c.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Fatalf("unexpected token: %q; want %q", act, exp)
+		}
+	}
+
+	// Token lives less than DefaultExpirationMargin, but still must be
+	// cached for a half of its lifetime.
+	assertToken("token-1")
+	shiftTime(29 * time.Second)
+	assertToken("token-1")
+	shiftTime(time.Second)
+	assertToken("token-2")
+}
@@ -89,3 +89,74 @@ func (m *multiBalancer) Remove(x balancerElement) {
 		}
 	}
 }
+
+// Balancer is an interface of load-balancing algorithm which may be
+// implemented outside of this package. See DriverConfig's Balancer field.
+//
+// Balancer methods called synchronized. That is, implementations must not
+// provide additional goroutine safety.
+type Balancer interface {
+	// Next returns next connection for request. It must return only
+	// connections previously inserted and not removed.
+	// Next MUST not return nil if it has at least one connection.
+	Next() BalancerConn
+
+	// Insert inserts new connection. It must return non-nil element which
+	// is then passed to Update() and Remove().
+	Insert(BalancerConn, BalancerConnInfo) BalancerElement
+
+	// Update updates previously inserted connection.
+	Update(BalancerElement, BalancerConnInfo)
+
+	// Remove removes previously inserted connection.
+	Remove(BalancerElement)
+}
+
+// BalancerElement is an empty interface that holds some Balancer specific
+// data.
+type BalancerElement interface{}
+
+// BalancerConn is a connection to the endpoint managed by the Balancer.
+type BalancerConn interface {
+	// Addr returns endpoint address in the "host:port" form.
+	Addr() string
+
+	// Stats returns connection runtime stats.
+	Stats() ConnStats
+}
+
+// BalancerConnInfo contains connection "static" stats obtained from the
+// discovery routine.
+type BalancerConnInfo struct {
+	LoadFactor float32
+	Local      bool
+}
+
+// customBalancer adapts user defined Balancer to the balancer interface.
+type customBalancer struct {
+	b Balancer
+}
+
+func (c customBalancer) Next() *conn {
+	x := c.b.Next()
+	if x == nil {
+		return nil
+	}
+	conn, ok := x.(*conn)
+	if !ok {
+		panic("ydb: custom balancer has returned unknown conn")
+	}
+	return conn
+}
+
+func (c customBalancer) Insert(conn *conn, info connInfo) balancerElement {
+	return c.b.Insert(conn, info.export())
+}
+
+func (c customBalancer) Update(x balancerElement, info connInfo) {
+	c.b.Update(x, info.export())
+}
+
+func (c customBalancer) Remove(x balancerElement) {
+	c.b.Remove(x)
+}
@@ -107,3 +107,66 @@ func TestMultiBalancer(t *testing.T) {
 		t.Fatalf("Next() returned unexpected non-nil connection")
 	}
 }
+
+type firstConnBalancer struct {
+	conns []BalancerConn
+	info  map[BalancerConn]BalancerConnInfo
+}
+
+func (b *firstConnBalancer) Next() BalancerConn {
+	if len(b.conns) == 0 {
+		return nil
+	}
+	return b.conns[0]
+}
+
+func (b *firstConnBalancer) Insert(c BalancerConn, info BalancerConnInfo) BalancerElement {
+	b.conns = append(b.conns, c)
+	b.info[c] = info
+	return c
+}
+
+func (b *firstConnBalancer) Update(x BalancerElement, info BalancerConnInfo) {
+	b.info[x.(BalancerConn)] = info
+}
+
+func (b *firstConnBalancer) Remove(x BalancerElement) {
+	for i, c := range b.conns {
+		if c == x {
+			b.conns = append(b.conns[:i], b.conns[i+1:]...)
+			break
+		}
+	}
+	delete(b.info, x.(BalancerConn))
+}
+
+func TestCustomBalancer(t *testing.T) {
+	u := &firstConnBalancer{
+		info: make(map[BalancerConn]BalancerConnInfo),
+	}
+	b := newBalancer(DriverConfig{
+		Balancer:             u,
+		PreferLocalEndpoints: true,
+	})
+	if c := b.Next(); c != nil {
+		t.Fatalf("unexpected conn from empty balancer: %v", c)
+	}
+	c1 := &conn{addr: connAddr{"1", 2135}}
+	c2 := &conn{addr: connAddr{"2", 2135}}
+	e1 := b.Insert(c1, connInfo{local: true})
+	b.Insert(c2, connInfo{})
+	if c := b.Next(); c != c1 {
+		t.Fatalf("unexpected conn: %v", c.Addr())
+	}
+	b.Update(e1, connInfo{loadFactor: 0.5})
+	if act, exp := u.info[c1], (BalancerConnInfo{LoadFactor: 0.5}); act != exp {
+		t.Fatalf("unexpected conn info: %+v; want %+v", act, exp)
+	}
+	b.Remove(e1)
+	if c := b.Next(); c != c2 {
+		t.Fatalf("unexpected conn: %v", c.Addr())
+	}
+	if act, exp := c2.Addr(), "2:2135"; act != exp {
+		t.Fatalf("unexpected addr: %q; want %q", act, exp)
+	}
+}
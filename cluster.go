@@ -20,12 +20,22 @@ type connInfo struct {
 	local      bool
 }
 
+func (c connInfo) export() BalancerConnInfo {
+	return BalancerConnInfo{
+		LoadFactor: c.loadFactor,
+		Local:      c.local,
+	}
+}
+
 // connEntry represents inserted into the cluster connection.
 type connEntry struct {
 	conn           *conn
 	handle         balancerElement
 	trackerQueueEl *list.Element
 
+	// banUntil is the time until which the tracked connection is banned.
+	banUntil time.Time
+
 	info connInfo
 }
 
@@ -54,6 +64,9 @@ type cluster struct {
 	dial     func(context.Context, string, int) (*conn, error)
 	balancer balancer
 	trace    DriverTrace
+	clock    timeutil.Clock
+	banTTL   time.Duration
+	grace    time.Duration
 
 	mu    sync.RWMutex
 	once  sync.Once
@@ -67,6 +80,9 @@ type cluster struct {
 	trackerDone   chan struct{}
 	trackerQueue  *list.List // list of *conn.
 
+	draining  map[*conn]connInfo
+	drainWait sync.WaitGroup
+
 	closed bool
 
 	testHookTrackerQueue func([]*list.Element)
@@ -75,6 +91,7 @@ type cluster struct {
 func (c *cluster) init() {
 	c.once.Do(func() {
 		c.index = make(map[connAddr]connEntry)
+		c.clock = timeutil.ClockOrDefault(c.clock)
 
 		c.trackerCtx, c.trackerCancel = context.WithCancel(context.Background())
 		c.trackerWake = make(chan struct{}, 1)
@@ -123,6 +140,7 @@ func (c *cluster) Close() (err error) {
 	}
 
 	<-c.trackerDone
+	c.drainWait.Wait()
 
 	return
 }
@@ -182,11 +200,50 @@ func (c *cluster) Get(ctx context.Context) (conn *conn, err error) {
 	}
 }
 
+// Ban removes conn from the balancer for the c.banTTL duration. After that
+// conn is returned back in the ConnProbation state. The last ready conn is
+// never banned.
+func (c *cluster) Ban(conn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.ready <= 1 {
+		return
+	}
+	entry, has := c.index[conn.addr]
+	if !has || entry.conn != conn || entry.handle == nil {
+		// Conn is already removed from the balancer.
+		return
+	}
+	conn.runtime.setState(ConnBanned)
+	conn.runtime.unavailableDone(false)
+
+	// NOTE: see Get() for details about passing conn to the tracker.
+	entry.removeFrom(c.balancer)
+	entry.conn = nil
+	entry.banUntil = c.clock.Now().Add(c.banTTL)
+	entry.trackerQueueEl = c.track(conn)
+
+	c.index[conn.addr] = entry
+	c.ready--
+}
+
+// banned reports whether tracked conn referenced by el is banned at the
+// moment now.
+func (c *cluster) banned(el *list.Element, now time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	conn := el.Value.(*conn)
+	entry, has := c.index[conn.addr]
+	return has && entry.trackerQueueEl == el && now.Before(entry.banUntil)
+}
+
 func isReady(conn *conn) bool {
 	return conn.conn != nil && conn.conn.GetState() == connectivity.Ready
 }
 
 // Insert inserts new connection into the cluster.
+// If endpoint could not be dialed, it is still inserted in the ConnConnecting
+// state and is re-dialed with backoff in background.
 func (c *cluster) Insert(ctx context.Context, e Endpoint) {
 	c.init()
 
@@ -197,7 +254,7 @@ func (c *cluster) Insert(ctx context.Context, e Endpoint) {
 	}
 	conn, err := c.dial(ctx, e.Addr, e.Port)
 	if err != nil {
-		conn = newConn(nil, addr)
+		conn = newConn(nil, addr, c.clock)
 		err = nil
 	}
 	cc := conn.conn
@@ -232,7 +289,7 @@ func (c *cluster) Insert(ctx context.Context, e Endpoint) {
 		wait = c.wait
 		c.wait = nil
 	} else {
-		conn.runtime.setState(ConnOffline)
+		conn.runtime.setState(ConnConnecting)
 		entry.trackerQueueEl = c.track(conn)
 	}
 	c.index[addr] = entry
@@ -281,23 +338,63 @@ func (c *cluster) Remove(_ context.Context, e Endpoint) {
 		c.mu.Unlock()
 		panic("ydb: can't remove not-existing endpoint")
 	}
+	x := entry.conn
 	if el := entry.trackerQueueEl; el != nil {
 		// Connection is being tracked.
 		c.trackerQueue.Remove(el)
+		if !entry.banUntil.IsZero() {
+			// Banned conn is connected and may have operations in flight,
+			// thus it is closed here as a not tracked one. Other tracked
+			// conns are closed by the tracker.
+			x = el.Value.(*conn)
+		}
 	} else {
 		entry.removeFrom(c.balancer)
 		c.ready--
 	}
 	delete(c.index, addr)
+	drain := x != nil && c.grace > 0
+	if drain {
+		if c.draining == nil {
+			c.draining = make(map[*conn]connInfo)
+		}
+		c.draining[x] = entry.info
+		c.drainWait.Add(1)
+		x.runtime.setState(ConnDraining)
+	}
 	c.mu.Unlock()
 
-	if entry.conn != nil {
-		// entry.conn may be nil when connection is being tracked after
+	switch {
+	case drain:
+		go c.drain(x)
+	case x != nil:
+		// x may be nil when connection is being tracked after
 		// unsuccessful dial().
-		_ = entry.conn.conn.Close()
+		_ = x.conn.Close()
 	}
 }
 
+// drain waits for operations in flight on conn to be completed during the
+// grace period and then closes conn.
+func (c *cluster) drain(conn *conn) {
+	defer c.drainWait.Done()
+
+	timer := c.clock.NewTimer(c.grace)
+	defer timer.Stop()
+	select {
+	case <-conn.runtime.drained():
+	case <-timer.C():
+	case <-c.trackerCtx.Done():
+	}
+
+	c.mu.Lock()
+	delete(c.draining, conn)
+	c.mu.Unlock()
+
+	conn.runtime.setState(ConnOffline)
+	_ = conn.conn.Close()
+}
+
 func (c *cluster) Stats(it func(Endpoint, ConnStats)) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -324,6 +421,9 @@ func (c *cluster) Stats(it func(Endpoint, ConnStats)) {
 			call(entry.conn, entry.info)
 		}
 	}
+	for conn, info := range c.draining {
+		call(conn, info)
+	}
 }
 
 // c.mu must be held.
@@ -345,7 +445,7 @@ func (c *cluster) tracker() {
 	defer close(c.trackerDone)
 
 	var active bool
-	timer := timeutil.NewTimer(time.Duration(1<<63 - 1))
+	timer := c.clock.NewTimer(time.Duration(1<<63 - 1))
 	if !timer.Stop() {
 		panic("ydb: can't stop timer")
 	}
@@ -382,14 +482,20 @@ func (c *cluster) tracker() {
 			}
 
 			ctx, cancel := context.WithTimeout(c.trackerCtx, time.Second)
+			now := c.clock.Now()
 			for _, el := range queue {
+				if c.banned(el, now) {
+					continue
+				}
 				conn := el.Value.(*conn)
 				addr := conn.addr
 				if conn.conn == nil {
 					x, err := c.dial(ctx, addr.addr, addr.port)
-					if err == nil {
-						conn.conn = x.conn
+					if err != nil {
+						continue
 					}
+					conn.conn = x.conn
+					conn.runtime.setState(ConnOffline)
 				}
 				if !isReady(conn) {
 					continue
@@ -409,9 +515,14 @@ func (c *cluster) tracker() {
 					c.trackerQueue.Remove(el)
 					active = c.trackerQueue.Len() > 0
 
-					conn.runtime.setState(ConnOnline)
+					if entry.banUntil.IsZero() {
+						conn.runtime.setState(ConnOnline)
+					} else {
+						conn.runtime.setState(ConnProbation)
+					}
 					c.trace.trackConnDone(conn)
 					entry.conn = conn
+					entry.banUntil = time.Time{}
 					entry.insertInto(c.balancer)
 					c.index[addr] = entry
 					c.ready++
@@ -420,7 +531,8 @@ func (c *cluster) tracker() {
 					c.wait = nil
 				}
 				c.mu.Unlock()
-				if !actual {
+				if !actual && conn.runtime.getState() != ConnDraining {
+					// Draining conn is closed by the drain().
 					_ = conn.conn.Close()
 				}
 				if wait != nil {
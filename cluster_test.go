@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
@@ -166,7 +168,7 @@ func TestClusterRemoveAndInsert(t *testing.T) {
 				return nil, fmt.Errorf("refused")
 			}
 			cc, err := ln.Dial(ctx)
-			ret := newConn(cc, connAddr{s, p})
+			ret := newConn(cc, connAddr{s, p}, nil)
 			// Used to distinguish connections.
 			ret.runtime.opStarted = id
 			return ret, err
@@ -220,6 +222,315 @@ func TestClusterRemoveAndInsert(t *testing.T) {
 	}
 }
 
+func TestClusterInsertConnecting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln := newStubListener()
+	srv := grpc.NewServer()
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	_, balancer := simpleBalancer()
+
+	timer := timetest.StubSingleTimer(t)
+	defer timer.Cleanup()
+
+	var refuse int32 = 1
+	c := &cluster{
+		dial: func(ctx context.Context, s string, p int) (*conn, error) {
+			if atomic.LoadInt32(&refuse) == 1 {
+				return nil, fmt.Errorf("refused")
+			}
+			cc, err := ln.Dial(ctx)
+			return &conn{
+				addr: connAddr{s, p},
+				conn: cc,
+			}, err
+		},
+		balancer: balancer,
+	}
+	defer c.Close()
+
+	assertState := func(exp ConnState) {
+		t.Helper()
+		var n int
+		c.Stats(func(_ Endpoint, s ConnStats) {
+			n++
+			if act := s.State; act != exp {
+				t.Fatalf("unexpected conn state: %v; want %v", act, exp)
+			}
+		})
+		if n != 1 {
+			t.Fatalf("unexpected number of endpoints: %d", n)
+		}
+	}
+
+	c.Insert(ctx, Endpoint{Addr: "foo"})
+	<-timer.Reset
+	assertState(ConnConnecting)
+
+	// Endpoint must be re-dialed in background.
+	timer.C <- timeutil.Now()
+	<-timer.Reset
+	assertState(ConnConnecting)
+
+	atomic.StoreInt32(&refuse, 0)
+	timer.C <- timeutil.Now()
+	if _, err := c.Get(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertState(ConnOnline)
+}
+
+func TestClusterBan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shiftTime, cleanupNow := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanupNow()
+
+	ln := newStubListener()
+	srv := grpc.NewServer()
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	_, balancer := simpleBalancer()
+
+	timer := timetest.StubSingleTimer(t)
+	defer timer.Cleanup()
+
+	c := &cluster{
+		dial: func(ctx context.Context, s string, p int) (*conn, error) {
+			cc, err := ln.Dial(ctx)
+			return &conn{
+				addr: connAddr{s, p},
+				conn: cc,
+			}, err
+		},
+		balancer: balancer,
+		banTTL:   time.Minute,
+	}
+	defer c.Close()
+
+	c.Insert(ctx, Endpoint{Addr: "a"})
+	c.Insert(ctx, Endpoint{Addr: "b"})
+	var (
+		a = c.index[connAddr{addr: "a"}].conn
+		b = c.index[connAddr{addr: "b"}].conn
+	)
+	assertState := func(conn *conn, exp ConnState) {
+		t.Helper()
+		if act := conn.runtime.getState(); act != exp {
+			t.Fatalf("unexpected %s state: %v; want %v", conn.addr, act, exp)
+		}
+	}
+	d := &driver{
+		cluster:          c,
+		connBanThreshold: 2,
+	}
+	unavailable := &TransportError{Reason: TransportErrorUnavailable}
+
+	d.checkConn(a, unavailable)
+	d.checkConn(a, nil)
+	d.checkConn(a, unavailable)
+	assertState(a, ConnOnline)
+	d.checkConn(a, unavailable)
+	<-timer.Reset
+	assertState(a, ConnBanned)
+
+	// The last ready conn must not be banned.
+	d.checkConn(b, unavailable)
+	d.checkConn(b, unavailable)
+	assertState(b, ConnOnline)
+	for i := 0; i < 10; i++ {
+		if conn, _ := c.Get(ctx); conn != b {
+			t.Fatalf("unexpected conn: %s", conn.addr)
+		}
+	}
+
+	timer.C <- timeutil.Now()
+	<-timer.Reset
+	assertState(a, ConnBanned)
+
+	shiftTime(time.Minute)
+	timer.C <- timeutil.Now()
+	for inserted := false; !inserted; {
+		<-time.After(time.Millisecond)
+		c.mu.RLock()
+		inserted = c.index[connAddr{addr: "a"}].handle != nil
+		c.mu.RUnlock()
+	}
+	assertState(a, ConnProbation)
+
+	// The first failure on probation must ban conn again.
+	d.checkConn(a, unavailable)
+	<-timer.Reset
+	assertState(a, ConnBanned)
+}
+
+func TestClusterRemoveDrain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln := newStubListener()
+	srv := grpc.NewServer()
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	_, balancer := simpleBalancer()
+
+	clock := timetest.NewClock(time.Unix(0, 0))
+	c := &cluster{
+		dial: func(ctx context.Context, s string, p int) (*conn, error) {
+			cc, err := ln.Dial(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return newConn(cc, connAddr{s, p}, clock), nil
+		},
+		balancer: balancer,
+		clock:    clock,
+		grace:    time.Minute,
+	}
+	defer c.Close()
+
+	// awaitState shifts time by d until conn gets exp state.
+	awaitState := func(conn *conn, d time.Duration, exp ConnState) {
+		t.Helper()
+		for i := 0; conn.runtime.getState() != exp; i++ {
+			if i == 1000 {
+				t.Fatalf("unexpected state: %v; want %v", conn.runtime.getState(), exp)
+			}
+			clock.Shift(d)
+			<-time.After(time.Millisecond)
+		}
+	}
+
+	for _, test := range []struct {
+		addr     string
+		complete bool
+	}{
+		{addr: "a", complete: true},
+		{addr: "b", complete: false},
+	} {
+		e := Endpoint{Addr: test.addr}
+		c.Insert(ctx, e)
+		conn, err := c.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := clock.Now()
+		conn.runtime.operationStart(start)
+
+		c.Remove(ctx, e)
+		var stats []ConnStats
+		c.Stats(func(_ Endpoint, s ConnStats) {
+			stats = append(stats, s)
+		})
+		if len(stats) != 1 || stats[0].State != ConnDraining {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+		if _, err := c.Get(canceledContext()); err == nil {
+			t.Fatalf("draining conn must not be used")
+		}
+		if !test.complete {
+			// Conn must be closed after the grace period.
+			awaitState(conn, time.Second, ConnOffline)
+			if !clock.Now().After(start.Add(c.grace)) {
+				t.Fatalf("conn is closed before the grace period is passed")
+			}
+			continue
+		}
+		for i := 0; i < 10; i++ {
+			clock.Shift(100 * time.Millisecond)
+			<-time.After(time.Millisecond)
+		}
+		if s := conn.runtime.getState(); s != ConnDraining {
+			t.Fatalf("unexpected state: %v", s)
+		}
+		// Conn must be closed once operation is done, without waiting for
+		// the grace period.
+		conn.runtime.operationDone(start, clock.Now(), nil)
+		awaitState(conn, 0, ConnOffline)
+	}
+}
+
+func TestClusterRemoveBanned(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln := newStubListener()
+	srv := grpc.NewServer()
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	_, balancer := simpleBalancer()
+
+	clock := timetest.NewClock(time.Unix(0, 0))
+	c := &cluster{
+		dial: func(ctx context.Context, s string, p int) (*conn, error) {
+			cc, err := ln.Dial(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return newConn(cc, connAddr{s, p}, clock), nil
+		},
+		balancer: balancer,
+		clock:    clock,
+		banTTL:   time.Minute,
+		grace:    time.Minute,
+	}
+	defer c.Close()
+
+	for _, test := range []struct {
+		addr     string
+		inflight bool
+	}{
+		{addr: "a", inflight: false},
+		{addr: "b", inflight: true},
+	} {
+		// Keep another conn ready, so banned one is not the last.
+		c.Insert(ctx, Endpoint{Addr: test.addr + "x"})
+
+		e := Endpoint{Addr: test.addr}
+		c.Insert(ctx, e)
+		conn := c.index[connAddr{addr: test.addr}].conn
+		start := clock.Now()
+		if test.inflight {
+			conn.runtime.operationStart(start)
+		}
+		c.Ban(conn)
+		if s := conn.runtime.getState(); s != ConnBanned {
+			t.Fatalf("unexpected state: %v", s)
+		}
+
+		c.Remove(ctx, e)
+		if test.inflight {
+			if s := conn.runtime.getState(); s != ConnDraining {
+				t.Fatalf("unexpected state: %v; want %v", s, ConnDraining)
+			}
+			conn.runtime.operationDone(start, clock.Now(), nil)
+		}
+		for i := 0; conn.conn.GetState() != connectivity.Shutdown; i++ {
+			if i == 1000 {
+				t.Fatalf("banned conn is not closed after remove")
+			}
+			<-time.After(time.Millisecond)
+		}
+	}
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
 func TestClusterAwait(t *testing.T) {
 	const timeout = 100 * time.Millisecond
 
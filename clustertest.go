@@ -0,0 +1,86 @@
+package ydb
+
+import (
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal/clustertest"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+func init() {
+	clustertest.New = func(config interface{}, clock timeutil.Clock) clustertest.Cluster {
+		c, _ := config.(*DriverConfig)
+		return newTestCluster(c, clock)
+	}
+}
+
+// testCluster implements clustertest.Cluster on top of the driver's
+// balancer and connections which are never dialed.
+type testCluster struct {
+	clock    timeutil.Clock
+	balancer balancer
+	conns    []*testClusterConn
+	index    map[*conn]int
+}
+
+type testClusterConn struct {
+	conn   *conn
+	info   connInfo
+	handle balancerElement
+}
+
+func newTestCluster(config *DriverConfig, clock timeutil.Clock) *testCluster {
+	return &testCluster{
+		clock:    clock,
+		balancer: newBalancer(config.withDefaults()),
+		index:    make(map[*conn]int),
+	}
+}
+
+func (c *testCluster) Add(addr string, port int, loadFactor float32, local bool) int {
+	x := &testClusterConn{
+		conn: newConn(nil, connAddr{addr, port}, c.clock),
+		info: connInfo{
+			loadFactor: loadFactor,
+			local:      local,
+		},
+	}
+	i := len(c.conns)
+	c.conns = append(c.conns, x)
+	c.index[x.conn] = i
+	c.SetOnline(i, true)
+	return i
+}
+
+func (c *testCluster) SetOnline(i int, online bool) {
+	x := c.conns[i]
+	switch {
+	case online && x.handle == nil:
+		x.handle = c.balancer.Insert(x.conn, x.info)
+		x.conn.runtime.setState(ConnOnline)
+	case !online && x.handle != nil:
+		c.balancer.Remove(x.handle)
+		x.handle = nil
+		x.conn.runtime.setState(ConnOffline)
+	}
+}
+
+func (c *testCluster) Next() int {
+	conn := c.balancer.Next()
+	if conn == nil {
+		return -1
+	}
+	return c.index[conn]
+}
+
+func (c *testCluster) OperationStart(i int, start time.Time) {
+	c.conns[i].conn.runtime.operationStart(start)
+}
+
+func (c *testCluster) OperationDone(i int, start, end time.Time, err error) {
+	c.conns[i].conn.runtime.operationDone(start, end, err)
+}
+
+func (c *testCluster) Stats(i int) interface{} {
+	return c.conns[i].conn.runtime.stats()
+}
@@ -0,0 +1,349 @@
+/*
+Package config provides loading of ydb driver and table client configuration
+from JSON or YAML documents, so the driver knobs could be managed without
+recompiling the application.
+
+Example of the JSON configuration:
+
+    {
+        "endpoint": "grpcs://ydb.example.net:2135",
+        "database": "/ru/home/mydb",
+        "auth": {"mode": "token", "token_file": "/etc/ydb/token"},
+        "request_timeout": "5s",
+        "balancing": "p2c",
+        "session_pool": {"size_limit": 100, "idle_threshold": "10s"}
+    }
+
+YAML documents use the same field names. Note that this package does not
+depend on any YAML library; to load YAML files, set YAMLUnmarshal function:
+
+    config.YAMLUnmarshal = yaml.Unmarshal
+*/
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/auth/metadata"
+	"github.com/yandex-cloud/ydb-go-sdk/table"
+)
+
+// YAMLUnmarshal is a function used to unmarshal YAML documents such as
+// yaml.Unmarshal() from gopkg.in/yaml.v2 package.
+// If YAMLUnmarshal is nil then YAML documents could not be loaded.
+var YAMLUnmarshal func([]byte, interface{}) error
+
+// Authentication modes.
+const (
+	AuthNone     = "none"
+	AuthToken    = "token"
+	AuthMetadata = "metadata"
+)
+
+// Config describes driver, dialer and table client configuration.
+//
+// Zero values mean defaults of appropriate ydb or table package options.
+type Config struct {
+	// Endpoint is an address to dial. It may be in either "host:port" or
+	// "grpcs://host:port/?database=name" form (see ydb.Dialer.Dial()).
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Database is a database name.
+	Database string `json:"database" yaml:"database"`
+
+	Auth AuthConfig `json:"auth" yaml:"auth"`
+	TLS  *TLSConfig `json:"tls" yaml:"tls"`
+
+	DialTimeout Duration `json:"dial_timeout" yaml:"dial_timeout"`
+	Keepalive   Duration `json:"keepalive" yaml:"keepalive"`
+
+	RequestTimeout       Duration `json:"request_timeout" yaml:"request_timeout"`
+	StreamTimeout        Duration `json:"stream_timeout" yaml:"stream_timeout"`
+	OperationTimeout     Duration `json:"operation_timeout" yaml:"operation_timeout"`
+	OperationCancelAfter Duration `json:"operation_cancel_after" yaml:"operation_cancel_after"`
+	DiscoveryInterval    Duration `json:"discovery_interval" yaml:"discovery_interval"`
+
+	// Balancing is a name of the balancing method: one of "round_robin",
+	// "p2c", "random_choice", "least_pending" or "load_factor".
+	Balancing            string `json:"balancing" yaml:"balancing"`
+	PreferLocalEndpoints bool   `json:"prefer_local_endpoints" yaml:"prefer_local_endpoints"`
+
+	SessionPool SessionPoolConfig `json:"session_pool" yaml:"session_pool"`
+	Table       TableConfig       `json:"table" yaml:"table"`
+}
+
+// AuthConfig describes credentials used by the driver.
+type AuthConfig struct {
+	// Mode is a one of AuthNone, AuthToken or AuthMetadata.
+	// Empty Mode means AuthNone.
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Token is a static token used in AuthToken mode.
+	Token string `json:"token" yaml:"token"`
+
+	// TokenFile is a path to the file containing token used in AuthToken
+	// mode. It is read once at Credentials() call.
+	TokenFile string `json:"token_file" yaml:"token_file"`
+
+	// MetadataAddr is the address of metadata service used in AuthMetadata
+	// mode. If MetadataAddr is empty then the metadata.DefaultAddr is used.
+	MetadataAddr string `json:"metadata_addr" yaml:"metadata_addr"`
+}
+
+// TLSConfig describes TLS configuration of the dialer.
+type TLSConfig struct {
+	// CAFile is a path to the PEM file with root certificates. If CAFile is
+	// empty then the system certificate pool is used.
+	CAFile string `json:"ca_file" yaml:"ca_file"`
+
+	ServerName         string `json:"server_name" yaml:"server_name"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+}
+
+// SessionPoolConfig describes table.SessionPool options.
+type SessionPoolConfig struct {
+	SizeLimit          int      `json:"size_limit" yaml:"size_limit"`
+	MinSize            int      `json:"min_size" yaml:"min_size"`
+	IdleThreshold      Duration `json:"idle_threshold" yaml:"idle_threshold"`
+	IdleTTL            Duration `json:"idle_ttl" yaml:"idle_ttl"`
+	BusyCheckInterval  Duration `json:"busy_check_interval" yaml:"busy_check_interval"`
+	KeepAliveBatchSize int      `json:"keep_alive_batch_size" yaml:"keep_alive_batch_size"`
+	KeepAliveTimeout   Duration `json:"keep_alive_timeout" yaml:"keep_alive_timeout"`
+	DeleteTimeout      Duration `json:"delete_timeout" yaml:"delete_timeout"`
+}
+
+// TableConfig describes table.Client options.
+type TableConfig struct {
+	MaxQueryCacheSize int `json:"max_query_cache_size" yaml:"max_query_cache_size"`
+}
+
+// Parse parses configuration document data using given unmarshal function.
+// If unmarshal is nil then data is parsed as JSON document; unknown fields
+// are reported as errors in that case.
+func Parse(data []byte, unmarshal func([]byte, interface{}) error) (*Config, error) {
+	c := new(Config)
+	var err error
+	if unmarshal != nil {
+		err = unmarshal(data, c)
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ydb: config: %v", err)
+	}
+	return c, nil
+}
+
+// LoadFile loads configuration from the file with given name. The format of
+// the file is detected by its extension: ".json" files are parsed as JSON;
+// ".yaml" and ".yml" files are parsed using YAMLUnmarshal function.
+func LoadFile(name string) (*Config, error) {
+	var unmarshal func([]byte, interface{}) error
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+	case ".yaml", ".yml":
+		if YAMLUnmarshal == nil {
+			return nil, fmt.Errorf(
+				"ydb: config: could not load %q: YAMLUnmarshal is not set",
+				name,
+			)
+		}
+		unmarshal = YAMLUnmarshal
+	default:
+		return nil, fmt.Errorf("ydb: config: unsupported file extension: %q", ext)
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("ydb: config: %v", err)
+	}
+	return Parse(data, unmarshal)
+}
+
+// Credentials returns credentials described by the auth configuration.
+// It returns nil credentials for AuthNone mode.
+func (c *Config) Credentials() (ydb.Credentials, error) {
+	a := c.Auth
+	switch a.Mode {
+	case "", AuthNone:
+		return nil, nil
+
+	case AuthToken:
+		token := a.Token
+		if a.TokenFile != "" {
+			p, err := ioutil.ReadFile(a.TokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("ydb: config: %v", err)
+			}
+			token = strings.TrimSpace(string(p))
+		}
+		if token == "" {
+			return nil, fmt.Errorf("ydb: config: empty token")
+		}
+		return ydb.AuthTokenCredentials{
+			AuthToken: token,
+		}, nil
+
+	case AuthMetadata:
+		return &metadata.Client{
+			Addr: a.MetadataAddr,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("ydb: config: unknown auth mode: %q", a.Mode)
+	}
+}
+
+// DriverConfig returns driver configuration.
+func (c *Config) DriverConfig() (*ydb.DriverConfig, error) {
+	creds, err := c.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	var balancing ydb.BalancingMethod
+	switch c.Balancing {
+	case "":
+	case "round_robin":
+		balancing = ydb.BalancingRoundRobin
+	case "p2c":
+		balancing = ydb.BalancingP2C
+	case "random_choice":
+		balancing = ydb.BalancingRandomChoice
+	case "least_pending":
+		balancing = ydb.BalancingLeastPending
+	case "load_factor":
+		balancing = ydb.BalancingLoadFactor
+	default:
+		return nil, fmt.Errorf("ydb: config: unknown balancing method: %q", c.Balancing)
+	}
+	return &ydb.DriverConfig{
+		Database:             c.Database,
+		Credentials:          creds,
+		RequestTimeout:       time.Duration(c.RequestTimeout),
+		StreamTimeout:        time.Duration(c.StreamTimeout),
+		OperationTimeout:     time.Duration(c.OperationTimeout),
+		OperationCancelAfter: time.Duration(c.OperationCancelAfter),
+		DiscoveryInterval:    time.Duration(c.DiscoveryInterval),
+		BalancingMethod:      balancing,
+		PreferLocalEndpoints: c.PreferLocalEndpoints,
+	}, nil
+}
+
+// Dialer returns dialer with driver configuration.
+func (c *Config) Dialer() (*ydb.Dialer, error) {
+	config, err := c.DriverConfig()
+	if err != nil {
+		return nil, err
+	}
+	d := &ydb.Dialer{
+		DriverConfig: config,
+		Timeout:      time.Duration(c.DialTimeout),
+		Keepalive:    time.Duration(c.Keepalive),
+	}
+	if t := c.TLS; t != nil {
+		d.TLSConfig = &tls.Config{
+			ServerName:         t.ServerName,
+			InsecureSkipVerify: t.InsecureSkipVerify,
+		}
+		if t.CAFile != "" {
+			p, err := ioutil.ReadFile(t.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("ydb: config: %v", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(p) {
+				return nil, fmt.Errorf("ydb: config: no certificates in %q", t.CAFile)
+			}
+			d.TLSConfig.RootCAs = pool
+		}
+	}
+	return d, nil
+}
+
+// Dial dials configured endpoint.
+func (c *Config) Dial(ctx context.Context) (ydb.Driver, error) {
+	d, err := c.Dialer()
+	if err != nil {
+		return nil, err
+	}
+	return d.Dial(ctx, c.Endpoint)
+}
+
+// NewTableClient returns table client using driver d.
+func (c *Config) NewTableClient(d ydb.Driver) *table.Client {
+	return &table.Client{
+		Driver:            d,
+		MaxQueryCacheSize: c.Table.MaxQueryCacheSize,
+	}
+}
+
+// NewSessionPool returns session pool using given session builder (such as
+// table client returned by NewTableClient()).
+func (c *Config) NewSessionPool(b table.SessionBuilder) *table.SessionPool {
+	p := c.SessionPool
+	return &table.SessionPool{
+		Builder:            b,
+		SizeLimit:          p.SizeLimit,
+		MinSize:            p.MinSize,
+		IdleThreshold:      time.Duration(p.IdleThreshold),
+		IdleTTL:            time.Duration(p.IdleTTL),
+		BusyCheckInterval:  time.Duration(p.BusyCheckInterval),
+		KeepAliveBatchSize: p.KeepAliveBatchSize,
+		KeepAliveTimeout:   time.Duration(p.KeepAliveTimeout),
+		DeleteTimeout:      time.Duration(p.DeleteTimeout),
+	}
+}
+
+// Duration is a time.Duration which could be unmarshaled from strings such
+// as "1.5s" (see time.ParseDuration()) or from numbers of nanoseconds.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(text []byte) error {
+	s := string(text)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*d = Duration(n)
+		return nil
+	}
+	x, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(x)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (d *Duration) UnmarshalJSON(p []byte) error {
+	if s, err := strconv.Unquote(string(p)); err == nil {
+		p = []byte(s)
+	}
+	return d.UnmarshalText(p)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface of gopkg.in/yaml.v2
+// package.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
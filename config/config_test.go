@@ -0,0 +1,162 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/auth/metadata"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(`{
+		"endpoint": "grpcs://ydb.example.net",
+		"database": "/local",
+		"auth": {"mode": "token", "token": "secret"},
+		"dial_timeout": "1.5s",
+		"request_timeout": 1000,
+		"balancing": "round_robin",
+		"session_pool": {"size_limit": 10, "idle_threshold": "1m"},
+		"table": {"max_query_cache_size": 5}
+	}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := time.Duration(c.DialTimeout), 1500*time.Millisecond; act != exp {
+		t.Errorf("unexpected dial timeout: %v; want %v", act, exp)
+	}
+
+	d, err := c.Dialer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := d.Timeout, 1500*time.Millisecond; act != exp {
+		t.Errorf("unexpected dialer timeout: %v; want %v", act, exp)
+	}
+	if act, exp := d.DriverConfig.RequestTimeout, time.Microsecond; act != exp {
+		t.Errorf("unexpected request timeout: %v; want %v", act, exp)
+	}
+	if act, exp := d.DriverConfig.BalancingMethod, ydb.BalancingRoundRobin; act != exp {
+		t.Errorf("unexpected balancing method: %v; want %v", act, exp)
+	}
+	if act, exp := d.DriverConfig.Database, "/local"; act != exp {
+		t.Errorf("unexpected database: %q; want %q", act, exp)
+	}
+	if act, exp := d.DriverConfig.Credentials, (ydb.AuthTokenCredentials{AuthToken: "secret"}); act != exp {
+		t.Errorf("unexpected credentials: %+v; want %+v", act, exp)
+	}
+
+	p := c.NewSessionPool(c.NewTableClient(nil))
+	if act, exp := p.SizeLimit, 10; act != exp {
+		t.Errorf("unexpected pool size limit: %v; want %v", act, exp)
+	}
+	if act, exp := p.IdleThreshold, time.Minute; act != exp {
+		t.Errorf("unexpected pool idle threshold: %v; want %v", act, exp)
+	}
+	if act, exp := c.NewTableClient(nil).MaxQueryCacheSize, 5; act != exp {
+		t.Errorf("unexpected max query cache size: %v; want %v", act, exp)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+	}{
+		{"unknown field", `{"endpont": "localhost"}`},
+		{"bad duration", `{"request_timeout": "5 seconds"}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse([]byte(test.data), nil); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+	for _, test := range []struct {
+		name string
+		conf Config
+	}{
+		{"unknown auth", Config{Auth: AuthConfig{Mode: "kerberos"}}},
+		{"empty token", Config{Auth: AuthConfig{Mode: AuthToken}}},
+		{"unknown balancing", Config{Balancing: "random"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.conf.DriverConfig(); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ydb-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(name, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := Config{Auth: AuthConfig{Mode: AuthToken, Token: "static", TokenFile: name}}
+	creds, err := c.Credentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := creds, (ydb.AuthTokenCredentials{AuthToken: "from-file"}); act != exp {
+		t.Errorf("unexpected credentials: %+v; want %+v", act, exp)
+	}
+
+	c = Config{Auth: AuthConfig{Mode: AuthMetadata, MetadataAddr: "localhost:1"}}
+	creds, err = c.Credentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := creds.(*metadata.Client); !ok || m.Addr != "localhost:1" {
+		t.Errorf("unexpected credentials: %#v", creds)
+	}
+
+	c = Config{}
+	if creds, err = c.Credentials(); err != nil || creds != nil {
+		t.Errorf("unexpected credentials: %v, %v; want nil", creds, err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ydb-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	yaml := filepath.Join(dir, "ydb.yaml")
+	if err := ioutil.WriteFile(yaml, []byte(`{"endpoint": "localhost"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(yaml); err == nil || !strings.Contains(err.Error(), "YAMLUnmarshal") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Emulate YAML unmarshaler with JSON one since YAML is a superset of
+	// JSON.
+	YAMLUnmarshal = json.Unmarshal
+	defer func() { YAMLUnmarshal = nil }()
+
+	c, err := LoadFile(yaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := c.Endpoint, "localhost"; act != exp {
+		t.Errorf("unexpected endpoint: %q; want %q", act, exp)
+	}
+
+	if _, err := LoadFile(filepath.Join(dir, "ydb.toml")); err == nil {
+		t.Errorf("expected error for unsupported extension")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/duration"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
 	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
@@ -16,6 +17,7 @@ type (
 	ctxOpTimeoutKey     struct{}
 	ctxOpCancelAfterKey struct{}
 	ctxOpModeKey        struct{}
+	ctxDeadlineMapKey   struct{}
 )
 
 // ContextDeadlineMapping describes how context.Context's deadline value is
@@ -35,6 +37,34 @@ const (
 	ContextDeadlineOperationCancelAfter
 )
 
+func (m ContextDeadlineMapping) String() string {
+	switch m {
+	case ContextDeadlineNoMapping:
+		return "none"
+	case ContextDeadlineOperationTimeout:
+		return "operation_timeout"
+	case ContextDeadlineOperationCancelAfter:
+		return "operation_cancel_after"
+	default:
+		return "unknown"
+	}
+}
+
+// WithContextDeadlineMapping returns a copy of parent in which context's
+// deadline value is mapped to YDB operation options according to m. It
+// overrides DriverConfig's ContextDeadlineMapping for calls made within the
+// returned context.
+func WithContextDeadlineMapping(parent context.Context, m ContextDeadlineMapping) context.Context {
+	return context.WithValue(parent, ctxDeadlineMapKey{}, m)
+}
+
+// ContextDeadlineMappingFrom returns the context's deadline mapping set by
+// WithContextDeadlineMapping() within given context.
+func ContextDeadlineMappingFrom(ctx context.Context) (m ContextDeadlineMapping, ok bool) {
+	m, ok = ctx.Value(ctxDeadlineMapKey{}).(ContextDeadlineMapping)
+	return
+}
+
 // WithOperationTimeout returns a copy of parent in which YDB operation timeout
 // parameter is set to d. If parent timeout is smaller than d, parent context
 // is returned.
@@ -74,8 +104,14 @@ func ContextOperationCancelAfter(ctx context.Context) (d time.Duration, ok bool)
 }
 
 // WithOperationMode returns a copy of parent in which YDB operation mode
-// parameter is set to m. If parent mode is set and is not equal to m,
-// WithOperationMode will panic.
+// parameter is set to m. It overrides DriverConfig's OperationMode for calls
+// made within the returned context. If parent mode is set and is not equal to
+// m, WithOperationMode will panic.
+//
+// Context's deadline is not mapped to the options of async operations (see
+// ContextDeadlineMapping): in async mode the deadline limits only the call
+// which starts the operation. Operation timeouts set explicitly are sent
+// regardless of the mode.
 func WithOperationMode(parent context.Context, m OperationMode) context.Context {
 	if cur, ok := ContextOperationMode(parent); ok {
 		if cur != m {
@@ -95,6 +131,33 @@ func ContextOperationMode(ctx context.Context) (m OperationMode, ok bool) {
 	return
 }
 
+// WithCallMetadata returns a copy of parent in which given gRPC metadata is
+// added to the metadata of every Call() and StreamRead() made within the
+// returned context. Values are appended to the values of the same keys set
+// before.
+func WithCallMetadata(parent context.Context, md metadata.MD) context.Context {
+	return outgoingContext(parent, md)
+}
+
+// WithTraceID returns a copy of parent in which given id is sent as the
+// request trace id with every Call() and StreamRead() made within the
+// returned context. It replaces trace id set before.
+func WithTraceID(parent context.Context, id string) context.Context {
+	md, _ := metadata.FromOutgoingContext(parent)
+	md = md.Copy()
+	md.Set(metaTraceID, id)
+	return metadata.NewOutgoingContext(parent, md)
+}
+
+// ContextTraceID returns the request trace id within given context.
+func ContextTraceID(ctx context.Context) (id string, ok bool) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if vs := md.Get(metaTraceID); len(vs) > 0 {
+		return vs[0], true
+	}
+	return "", false
+}
+
 type OperationMode uint
 
 const (
@@ -146,6 +209,13 @@ func (p OperationParams) toYDB() *Ydb_Operations.OperationParams {
 }
 
 func operationParams(ctx context.Context, dm ContextDeadlineMapping) (p OperationParams, ok bool) {
+	if m, ok := ContextDeadlineMappingFrom(ctx); ok {
+		dm = m
+	}
+	p.Mode, _ = ContextOperationMode(ctx)
+	if p.Mode == OperationModeAsync {
+		dm = ContextDeadlineNoMapping
+	}
 	d, hasDeadline := contextUntilDeadline(ctx)
 	var has bool
 	{
@@ -160,9 +230,6 @@ func operationParams(ctx context.Context, dm ContextDeadlineMapping) (p Operatio
 			p.CancelAfter = d
 		}
 	}
-	{
-		p.Mode, _ = ContextOperationMode(ctx)
-	}
 	return p, !p.Empty()
 }
 
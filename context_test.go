@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc/metadata"
+
 	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
 )
 
@@ -18,6 +20,9 @@ func TestOperationParams(t *testing.T) {
 		opMode     OperationMode
 		ctxMapping ContextDeadlineMapping
 
+		// callMapping is an optional per-call mapping override.
+		callMapping *ContextDeadlineMapping
+
 		exp OperationParams
 	}{
 		{
@@ -57,6 +62,21 @@ func TestOperationParams(t *testing.T) {
 				CancelAfter: time.Hour,
 			},
 		},
+		{
+			name:        "per call mapping",
+			ctxMapping:  ContextDeadlineOperationTimeout,
+			callMapping: mappingPtr(ContextDeadlineOperationCancelAfter),
+			ctxTimeout:  time.Second,
+			exp: OperationParams{
+				CancelAfter: time.Second,
+			},
+		},
+		{
+			name:        "per call no mapping",
+			ctxMapping:  ContextDeadlineOperationTimeout,
+			callMapping: mappingPtr(ContextDeadlineNoMapping),
+			ctxTimeout:  time.Second,
+		},
 		{
 			name:   "mode",
 			opMode: OperationModeAsync,
@@ -64,6 +84,36 @@ func TestOperationParams(t *testing.T) {
 				Mode: OperationModeAsync,
 			},
 		},
+		{
+			name:       "async mode no mapping",
+			ctxMapping: ContextDeadlineOperationTimeout,
+			ctxTimeout: time.Second,
+			opMode:     OperationModeAsync,
+			exp: OperationParams{
+				Mode: OperationModeAsync,
+			},
+		},
+		{
+			name:       "async mode explicit timeout",
+			ctxMapping: ContextDeadlineOperationTimeout,
+			ctxTimeout: time.Second,
+			opTimeout:  time.Hour,
+			opMode:     OperationModeAsync,
+			exp: OperationParams{
+				Timeout: time.Hour,
+				Mode:    OperationModeAsync,
+			},
+		},
+		{
+			name:       "sync mode mapping",
+			ctxMapping: ContextDeadlineOperationTimeout,
+			ctxTimeout: time.Second,
+			opMode:     OperationModeSync,
+			exp: OperationParams{
+				Timeout: time.Second,
+				Mode:    OperationModeSync,
+			},
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			_, cleanupNow := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
@@ -79,6 +129,9 @@ func TestOperationParams(t *testing.T) {
 			if m := test.opMode; m != 0 {
 				ctx = WithOperationMode(ctx, m)
 			}
+			if m := test.callMapping; m != nil {
+				ctx = WithContextDeadlineMapping(ctx, *m)
+			}
 			if t := test.ctxTimeout; t > 0 {
 				var cancel context.CancelFunc
 				ctx, cancel = context.WithDeadline(ctx, timeutil.Now().Add(t))
@@ -96,3 +149,31 @@ func TestOperationParams(t *testing.T) {
 		})
 	}
 }
+
+func mappingPtr(m ContextDeadlineMapping) *ContextDeadlineMapping {
+	return &m
+}
+
+func TestCallMetadata(t *testing.T) {
+	ctx := WithCallMetadata(context.Background(), metadata.Pairs("x-request-id", "1"))
+	ctx = WithTraceID(ctx, "a")
+	ctx = WithTraceID(ctx, "b")
+	ctx = WithCallMetadata(ctx, metadata.Pairs("x-request-id", "2"))
+
+	if id, ok := ContextTraceID(ctx); !ok || id != "b" {
+		t.Errorf("unexpected trace id: %q (%t)", id, ok)
+	}
+	md, _ := metadata.FromOutgoingContext(outgoingContext(ctx, metadata.Pairs(metaDatabase, "/local")))
+	for key, exp := range map[string][]string{
+		"x-request-id": {"1", "2"},
+		metaTraceID:    {"b"},
+		metaDatabase:   {"/local"},
+	} {
+		if act := md.Get(key); !reflect.DeepEqual(act, exp) {
+			t.Errorf("unexpected %q metadata: %v; want %v", key, act, exp)
+		}
+	}
+	if _, ok := ContextTraceID(context.Background()); ok {
+		t.Errorf("unexpected trace id")
+	}
+}
@@ -3,6 +3,7 @@ package ydb
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
@@ -23,6 +24,25 @@ type Credentials interface {
 	Token(context.Context) (string, error)
 }
 
+// TokenInvalidator is an optional interface of Credentials which may be
+// implemented to let driver force token refresh. Driver calls
+// InvalidateToken() when the server rejects a call as unauthenticated and
+// then retries the call once with the token obtained by the next Token()
+// call.
+type TokenInvalidator interface {
+	// InvalidateToken drops cached token if it is equal to the given one.
+	InvalidateToken(token string)
+}
+
+// TokenExpirer is an optional interface of Credentials which may be
+// implemented to let driver report remaining lifetime of the token in
+// GetCredentialsDoneInfo.
+type TokenExpirer interface {
+	// TokenExpiresAt returns expiration time of the token returned by the
+	// last Token() call. It returns zero time if expiration time is unknown.
+	TokenExpiresAt() time.Time
+}
+
 // CredentialsFunc is an adapter to allow the use of ordinary functions as
 // Credentials.
 type CredentialsFunc func(context.Context) (string, error)
@@ -33,7 +53,8 @@ func (f CredentialsFunc) Token(ctx context.Context) (string, error) {
 }
 
 // AuthTokenCredentials implements Credentials interface with static
-// authorization parameters.
+// authorization parameters. That is, it provides access token (e.g. OAuth
+// token) credentials.
 type AuthTokenCredentials struct {
 	AuthToken string
 }
@@ -45,7 +66,7 @@ func (a AuthTokenCredentials) Token(_ context.Context) (string, error) {
 
 // DropTokenCredentials implements Credentials interface. Its Token() method
 // always returns ErrCredentialsDropToken which in turn leads driver to not use
-// token at all.
+// token at all. That is, it provides anonymous access.
 type DropTokenCredentials struct{}
 
 // Token implements Credentials.
@@ -67,24 +88,50 @@ type multiCredentials struct {
 	cs []Credentials
 }
 
+// sourceCredentials is implemented by Credentials which delegate token
+// obtaining to other Credentials.
+type sourceCredentials interface {
+	// sourceToken is like Token() but also returns the Credentials which
+	// actually returned the token.
+	sourceToken(context.Context) (string, Credentials, error)
+}
+
 func (m *multiCredentials) Token(ctx context.Context) (token string, err error) {
+	token, _, err = m.sourceToken(ctx)
+	return
+}
+
+func (m *multiCredentials) sourceToken(ctx context.Context) (token string, src Credentials, err error) {
 	for _, c := range m.cs {
 		token, err = c.Token(ctx)
 		if err == nil {
-			return
+			return token, c, nil
 		}
 	}
 	if err == nil {
 		err = ErrCredentialsDropToken
 	}
-	return
+	return "", nil, err
+}
+
+// InvalidateToken implements TokenInvalidator.
+func (m *multiCredentials) InvalidateToken(token string) {
+	for _, c := range m.cs {
+		if x, ok := c.(TokenInvalidator); ok {
+			x.InvalidateToken(token)
+		}
+	}
 }
 
 // MultiCredentials creates Credentials which represents multiple ways of
-// obtaining token.
+// obtaining token. That is, it provides chain credentials; see
+// DefaultCredentialsChain() for the chain of the environment credentials.
 // Its Token() method proxies call to the underlying credentials in order.
 // When first successful call met, it returns. If there are no successful
 // calls, it returns last error.
+//
+// The underlying credentials which returned the token is reported by the
+// DriverTrace's GetCredentialsDone callback.
 func MultiCredentials(cs ...Credentials) Credentials {
 	all := make([]Credentials, 0, len(cs))
 	for _, c := range cs {
@@ -0,0 +1,293 @@
+package ydb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// Default parameters used by RefreshableCredentials.
+const (
+	DefaultCredentialsRefreshMargin   = time.Minute
+	DefaultCredentialsRefreshInterval = 5 * time.Minute
+)
+
+// minCredentialsRefreshDelay is the minimum delay between successful
+// refreshes. It prevents refreshing in a tight loop when the underlying
+// credentials return tokens which expire within the RefreshMargin.
+const minCredentialsRefreshDelay = time.Second
+
+// RefreshableCredentialsTrace contains options for tracing
+// RefreshableCredentials activity.
+type RefreshableCredentialsTrace struct {
+	RefreshStart func(CredentialsRefreshStartInfo)
+	RefreshDone  func(CredentialsRefreshDoneInfo)
+}
+
+type (
+	CredentialsRefreshStartInfo struct {
+		// Attempt is the number of consecutive failed refreshes made before.
+		Attempt int
+	}
+	CredentialsRefreshDoneInfo struct {
+		Attempt   int
+		Latency   time.Duration
+		ExpiresAt time.Time
+		Error     error
+	}
+)
+
+// RefreshableCredentialsStats contains RefreshableCredentials state.
+type RefreshableCredentialsStats struct {
+	// LastRefresh is the time of the last successful refresh.
+	LastRefresh time.Time
+
+	// ExpiresAt is the expiration time of the current token. It is zero if
+	// expiration time is unknown.
+	ExpiresAt time.Time
+
+	// Failures is the number of consecutive failed refreshes.
+	Failures int
+
+	// LastError is the error of the last failed refresh. It is nil if the
+	// last refresh succeeded.
+	LastError error
+}
+
+// RefreshableCredentials wraps Credentials and refreshes their token in
+// background before it expires. That is, Token() calls return cached token
+// without waiting for the underlying credentials unless there is no valid
+// token yet.
+//
+// Token is refreshed RefreshMargin before the expiration time if underlying
+// credentials implement TokenExpirer, or every RefreshInterval otherwise.
+// Failed refreshes are retried with Backoff while cached token is still
+// valid.
+//
+// Background refreshing starts after the first successful Token() call and
+// stops on Close() call.
+type RefreshableCredentials struct {
+	// Credentials are the underlying credentials. They must not be nil.
+	Credentials Credentials
+
+	// RefreshMargin is the time before token expiration when the token is
+	// refreshed. Tokens which are obtained already within the margin are
+	// refreshed in the middle of their remaining lifetime.
+	// If RefreshMargin is zero then the DefaultCredentialsRefreshMargin is
+	// used.
+	RefreshMargin time.Duration
+
+	// RefreshInterval is the frequency of refreshing tokens which expiration
+	// time is unknown.
+	// If RefreshInterval is zero then the DefaultCredentialsRefreshInterval
+	// is used.
+	RefreshInterval time.Duration
+
+	// Backoff is the backoff policy used to retry failed refreshes.
+	// If Backoff is nil then the DefaultBackoff is used.
+	Backoff Backoff
+
+	// Trace contains refresh tracing options.
+	Trace RefreshableCredentialsTrace
+
+	// Clock is a source of time used to plan refreshes.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	// refreshMu serializes calls to the underlying credentials.
+	refreshMu sync.Mutex
+
+	mu    sync.RWMutex
+	token string
+	stats RefreshableCredentialsStats
+
+	start sync.Once
+	stop  chan struct{}
+	done  chan struct{}
+
+	closeOnce sync.Once
+}
+
+// Token implements Credentials. It returns cached token if it is valid.
+// Otherwise it obtains token from the underlying credentials.
+func (r *RefreshableCredentials) Token(ctx context.Context) (string, error) {
+	if token := r.cached(); token != "" {
+		return token, nil
+	}
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+	if token := r.cached(); token != "" {
+		return token, nil
+	}
+	token, err := r.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.start.Do(func() {
+		r.stop = make(chan struct{})
+		r.done = make(chan struct{})
+		go r.loop()
+	})
+	return token, nil
+}
+
+// InvalidateToken implements TokenInvalidator. It drops cached token if it
+// is equal to the given one and passes the call to the underlying
+// credentials if they implement TokenInvalidator.
+func (r *RefreshableCredentials) InvalidateToken(token string) {
+	if x, ok := r.Credentials.(TokenInvalidator); ok {
+		x.InvalidateToken(token)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token == token {
+		r.token = ""
+	}
+}
+
+// TokenExpiresAt implements TokenExpirer.
+func (r *RefreshableCredentials) TokenExpiresAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stats.ExpiresAt
+}
+
+// Stats returns current state of the credentials.
+func (r *RefreshableCredentials) Stats() RefreshableCredentialsStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stats
+}
+
+// Close stops background refreshing.
+func (r *RefreshableCredentials) Close() {
+	r.closeOnce.Do(func() {
+		// Prevent background refreshing from start.
+		r.start.Do(func() {})
+		if r.stop != nil {
+			close(r.stop)
+			<-r.done
+		}
+	})
+}
+
+func (r *RefreshableCredentials) cached() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if exp := r.stats.ExpiresAt; !exp.IsZero() && !exp.After(r.clock().Now()) {
+		return ""
+	}
+	return r.token
+}
+
+// refresh obtains token from the underlying credentials and updates the
+// state.
+// r.refreshMu must be held.
+func (r *RefreshableCredentials) refresh(ctx context.Context) (token string, err error) {
+	r.mu.RLock()
+	attempt := r.stats.Failures
+	r.mu.RUnlock()
+
+	if f := r.Trace.RefreshStart; f != nil {
+		f(CredentialsRefreshStartInfo{
+			Attempt: attempt,
+		})
+	}
+	start := r.clock().Now()
+	token, err = r.Credentials.Token(ctx)
+	var expires time.Time
+	if e, ok := r.Credentials.(TokenExpirer); ok && err == nil {
+		expires = e.TokenExpiresAt()
+	}
+	if f := r.Trace.RefreshDone; f != nil {
+		f(CredentialsRefreshDoneInfo{
+			Attempt:   attempt,
+			Latency:   r.clock().Now().Sub(start),
+			ExpiresAt: expires,
+			Error:     err,
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.stats.Failures++
+		r.stats.LastError = err
+		return "", err
+	}
+	r.token = token
+	r.stats = RefreshableCredentialsStats{
+		LastRefresh: r.clock().Now(),
+		ExpiresAt:   expires,
+	}
+	return token, nil
+}
+
+func (r *RefreshableCredentials) loop() {
+	defer close(r.done)
+	for {
+		var (
+			wait  <-chan time.Time
+			timer timeutil.Timer
+		)
+		if n := r.Stats().Failures; n > 0 {
+			wait = r.backoff().Wait(n - 1)
+		} else {
+			timer = r.clock().NewTimer(r.untilRefresh())
+			wait = timer.C()
+		}
+		select {
+		case <-wait:
+			r.refreshMu.Lock()
+			_, _ = r.refresh(context.Background())
+			r.refreshMu.Unlock()
+
+		case <-r.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+	}
+}
+
+// untilRefresh returns duration until the next planned refresh.
+func (r *RefreshableCredentials) untilRefresh() time.Duration {
+	s := r.Stats()
+	if !s.ExpiresAt.IsZero() {
+		m := r.RefreshMargin
+		if m == 0 {
+			m = DefaultCredentialsRefreshMargin
+		}
+		at := s.ExpiresAt.Add(-m)
+		if !at.After(s.LastRefresh) {
+			// Token is already within the margin when obtained, thus
+			// refreshing it right away will likely return the same token.
+			// Refresh in the middle of its remaining lifetime instead.
+			d := s.ExpiresAt.Sub(s.LastRefresh) / 2
+			if d < minCredentialsRefreshDelay {
+				d = minCredentialsRefreshDelay
+			}
+			at = s.LastRefresh.Add(d)
+		}
+		return at.Sub(r.clock().Now())
+	}
+	i := r.RefreshInterval
+	if i == 0 {
+		i = DefaultCredentialsRefreshInterval
+	}
+	return s.LastRefresh.Add(i).Sub(r.clock().Now())
+}
+
+func (r *RefreshableCredentials) clock() timeutil.Clock {
+	return timeutil.ClockOrDefault(r.Clock)
+}
+
+func (r *RefreshableCredentials) backoff() Backoff {
+	if r.Backoff == nil {
+		return DefaultBackoff
+	}
+	return r.Backoff
+}
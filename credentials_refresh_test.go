@@ -0,0 +1,163 @@
+package ydb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+type countingCredentials struct {
+	mu      sync.Mutex
+	n       int
+	err     error
+	expires time.Time
+}
+
+func (c *countingCredentials) Token(context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return "", c.err
+	}
+	c.n++
+	c.expires = timeutil.Now().Add(time.Hour)
+	return "token-" + strconv.Itoa(c.n), nil
+}
+
+func (c *countingCredentials) TokenExpiresAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.expires
+}
+
+func (c *countingCredentials) setError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+func TestRefreshableCredentials(t *testing.T) {
+	_, cleanupNow := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanupNow()
+
+	var (
+		timerC  = make(chan time.Time)
+		created = make(chan time.Duration, 1)
+		backoff = make(chan time.Time)
+		done    = make(chan CredentialsRefreshDoneInfo, 1)
+	)
+	cleanupTimer := timeutil.StubTestHookNewTimer(func(d time.Duration) timeutil.Timer {
+		created <- d
+		return timetest.Timer{Ch: timerC}
+	})
+	defer cleanupTimer()
+
+	c := new(countingCredentials)
+	r := &RefreshableCredentials{
+		Credentials:   c,
+		RefreshMargin: time.Minute,
+		Backoff: BackoffFunc(func(int) <-chan time.Time {
+			return backoff
+		}),
+		Trace: RefreshableCredentialsTrace{
+			RefreshDone: func(x CredentialsRefreshDoneInfo) {
+				select {
+				case done <- x:
+				default:
+				}
+			},
+		},
+	}
+	defer r.Close()
+
+	assertToken := func(exp string) {
+		t.Helper()
+		act, err := r.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != exp {
+			t.Fatalf("unexpected token: %q; want %q", act, exp)
+		}
+	}
+
+	assertToken("token-1")
+	<-done
+	assertToken("token-1")
+	if d := <-created; d != time.Hour-time.Minute {
+		t.Fatalf("unexpected refresh delay: %v", d)
+	}
+
+	timerC <- timeutil.Now()
+	if x := <-done; x.Error != nil {
+		t.Fatal(x.Error)
+	}
+	<-created
+	assertToken("token-2")
+
+	testErr := errors.New("test error")
+	c.setError(testErr)
+	timerC <- timeutil.Now()
+	if x := <-done; x.Error != testErr {
+		t.Fatalf("unexpected error: %v", x.Error)
+	}
+	// Cached token must be used while refresh is failing.
+	assertToken("token-2")
+
+	c.setError(nil)
+	backoff <- timeutil.Now()
+	if x := <-done; x.Error != nil || x.Attempt != 1 {
+		t.Fatalf("unexpected refresh result: %+v", x)
+	}
+	<-created
+	assertToken("token-3")
+	if s := r.Stats(); s.Failures != 0 || s.LastError != nil {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+}
+
+func TestRefreshableCredentialsUntilRefresh(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	for _, test := range []struct {
+		name    string
+		expires time.Duration
+		exp     time.Duration
+	}{
+		{
+			name:    "long living",
+			expires: time.Hour,
+			exp:     time.Hour - time.Minute,
+		},
+		{
+			name:    "within margin",
+			expires: 30 * time.Second,
+			exp:     15 * time.Second,
+		},
+		{
+			name:    "expired",
+			expires: 0,
+			exp:     minCredentialsRefreshDelay,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := &RefreshableCredentials{
+				RefreshMargin: time.Minute,
+				Clock:         timetest.NewClock(now),
+			}
+			r.stats = RefreshableCredentialsStats{
+				LastRefresh: now,
+				ExpiresAt:   now.Add(test.expires),
+			}
+			if act := r.untilRefresh(); act != test.exp {
+				t.Fatalf("unexpected refresh delay: %v; want %v", act, test.exp)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ package ydb
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc/metadata"
 
 	discovery "github.com/yandex-cloud/ydb-go-sdk/api/grpc/Ydb_Discovery_V1"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Discovery"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
 )
 
 type Endpoint struct {
@@ -15,6 +18,9 @@ type Endpoint struct {
 	Port       int
 	LoadFactor float32
 	Local      bool
+
+	// SSL reports whether endpoint requires TLS connection.
+	SSL bool
 }
 
 type discoveryClient struct {
@@ -48,10 +54,56 @@ func (d *discoveryClient) Discover(ctx context.Context, database string) ([]Endp
 	es := make([]Endpoint, len(res.Endpoints))
 	for i, e := range res.Endpoints {
 		es[i] = Endpoint{
-			Addr:  e.Address,
-			Port:  int(e.Port),
-			Local: e.Location == res.SelfLocation,
+			Addr:       e.Address,
+			Port:       int(e.Port),
+			LoadFactor: e.LoadFactor,
+			Local:      e.Location == res.SelfLocation,
+			SSL:        e.Ssl,
 		}
 	}
 	return es, nil
 }
+
+// fastDiscovery contains logic of forcing discovery out of schedule when
+// calls are failing. See DriverConfig's FastDiscoveryThreshold for details.
+type fastDiscovery struct {
+	threshold   int
+	minInterval time.Duration
+	clock       timeutil.Clock
+	force       func()
+
+	mu       sync.Mutex
+	failures int
+	last     time.Time
+}
+
+// report accounts result of the call. Nil err means that the endpoint has
+// responded. It is safe to call report() on nil fastDiscovery.
+func (f *fastDiscovery) report(err error) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	switch {
+	case err == nil:
+		f.failures = 0
+	case IsTransportError(err, TransportErrorUnavailable):
+		f.failures = f.threshold
+	default:
+		f.failures++
+	}
+	if f.failures < f.threshold {
+		f.mu.Unlock()
+		return
+	}
+	now := f.clock.Now()
+	if !f.last.IsZero() && now.Sub(f.last) < f.minInterval {
+		f.mu.Unlock()
+		return
+	}
+	f.failures = 0
+	f.last = now
+	f.mu.Unlock()
+
+	f.force()
+}
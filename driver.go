@@ -4,11 +4,14 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"path"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -36,11 +39,23 @@ var (
 	// DefaultContextDeadlineMapping contains driver's default behavior of how
 	// to use context's deadline value.
 	DefaultContextDeadlineMapping = ContextDeadlineOperationTimeout
+
+	// DefaultConnBanTTL contains default duration of endpoint ban.
+	DefaultConnBanTTL = 10 * time.Second
+
+	// DefaultFastDiscoveryMinInterval contains default minimum interval
+	// between discoveries forced by failures.
+	DefaultFastDiscoveryMinInterval = 5 * time.Second
 )
 
 // ErrClosed is returned when operation requested on a closed driver.
 var ErrClosed = errors.New("driver closed")
 
+// ErrStreamIdle is passed to the stream processing function when stream was
+// canceled because no messages were received from the server for the
+// DriverConfig's StreamIdleTimeout.
+var ErrStreamIdle = errors.New("ydb: stream idle timeout exceeded")
+
 // Driver is an interface of YDB driver.
 type Driver interface {
 	Call(context.Context, internal.Operation) error
@@ -55,6 +70,18 @@ const (
 	BalancingUnknown BalancingMethod = iota
 	BalancingRoundRobin
 	BalancingP2C
+
+	// BalancingRandomChoice picks endpoint uniformly at random.
+	BalancingRandomChoice
+
+	// BalancingLeastPending picks endpoint with the least number of
+	// operations in flight. It may be configured with *LeastPendingConfig.
+	BalancingLeastPending
+
+	// BalancingLoadFactor picks endpoint at random with probability
+	// proportional to its spare capacity reported by discovery. It may be
+	// configured with *LoadFactorConfig.
+	BalancingLoadFactor
 )
 
 var balancers = map[BalancingMethod]func(interface{}) balancer{
@@ -73,6 +100,27 @@ var balancers = map[BalancingMethod]func(interface{}) balancer{
 			},
 		}
 	},
+	BalancingRandomChoice: func(_ interface{}) balancer {
+		return new(randomChoice)
+	},
+	BalancingLeastPending: func(c interface{}) balancer {
+		if c == nil {
+			return new(leastPending)
+		}
+		config := c.(*LeastPendingConfig)
+		return &leastPending{
+			PreferLocal: config.PreferLocal,
+		}
+	},
+	BalancingLoadFactor: func(c interface{}) balancer {
+		if c == nil {
+			return new(loadFactorBalancer)
+		}
+		config := c.(*LoadFactorConfig)
+		return &loadFactorBalancer{
+			MinWeight: config.MinWeight,
+		}
+	},
 }
 
 // DriverConfig contains driver configuration options.
@@ -97,6 +145,20 @@ type DriverConfig struct {
 	// If StreamTimeout is zero then no timeout is used.
 	StreamTimeout time.Duration
 
+	// StreamIdleTimeout is the maximum amount of time a StreamRead() will
+	// wait for the next message from the server. If timeout exceeds the
+	// stream is canceled and ErrStreamIdle is passed to the processing
+	// function.
+	//
+	// Unlike StreamTimeout it limits only the pauses between messages, such
+	// that long streams which are continuously sending data are not
+	// interrupted. Note that it does not distinguish slow server from the
+	// dead connection; the latter is detected by the Dialer's Keepalive
+	// pings, which are sent regardless of the streams activity.
+	//
+	// If StreamIdleTimeout is zero then no timeout is used.
+	StreamIdleTimeout time.Duration
+
 	// OperationTimeout is the maximum amount of time a YDB server will process
 	// an operation. After timeout exceeds YDB will try to cancel operation and
 	// regardless of the cancelation appropriate error will be returned to
@@ -119,12 +181,30 @@ type DriverConfig struct {
 	// value is used.
 	ContextDeadlineMapping ContextDeadlineMapping
 
+	// OperationMode is the default mode of YDB operations. It is used for the
+	// calls made within contexts without the mode set by WithOperationMode().
+	//
+	// Note that context's deadline (including the one set by RequestTimeout)
+	// is not mapped to the options of async operations: it limits only the
+	// call which starts the operation, not the operation itself.
+	//
+	// If OperationMode is zero then the mode is not sent and the server
+	// processes operations synchronously.
+	OperationMode OperationMode
+
 	// DiscoveryInterval is the frequency of background tasks of ydb endpoints
 	// discovery.
 	// If DiscoveryInterval is zero then the DefaultDiscoveryInterval is used.
 	// If DiscoveryInterval is negative, then no background discovery prepared.
 	DiscoveryInterval time.Duration
 
+	// EndpointsCache is an optional storage of the discovered endpoints.
+	// If EndpointsCache is not nil, driver stores there endpoints received
+	// by each successful discovery and uses stored endpoints when the initial
+	// discovery fails.
+	// It has no effect if DiscoveryInterval is negative.
+	EndpointsCache EndpointsCache
+
 	// BalancingMethod is an algorithm used by the driver for endpoint
 	// selection.
 	// If BalancingMethod is zero then the DefaultBalancingMethod is used.
@@ -134,6 +214,11 @@ type DriverConfig struct {
 	// BalancingMethod. That is, some balancing methods allow to be configured.
 	BalancingConfig interface{}
 
+	// Balancer is an optional user defined balancing algorithm.
+	// If Balancer is not nil then BalancingMethod, BalancingConfig and
+	// PreferLocalEndpoints are ignored.
+	Balancer Balancer
+
 	// PreferLocalEndpoints adds endpoint selection logic when local endpoints
 	// are always used first.
 	// When no alive local endpoints left other endpoints will be used.
@@ -151,6 +236,75 @@ type DriverConfig struct {
 	// is, currently this option may be called as experimental.
 	// You have been warned.
 	PreferLocalEndpoints bool
+
+	// FastDiscoveryThreshold is a number of consecutive failed calls (that
+	// is, calls failed to get connection or failed with transport error)
+	// after which discovery is performed immediately instead of waiting for
+	// the DiscoveryInterval. Discovery is also performed immediately when
+	// endpoint responds with "unavailable" transport error.
+	//
+	// If FastDiscoveryThreshold is zero then discovery is performed only
+	// every DiscoveryInterval.
+	FastDiscoveryThreshold int
+
+	// FastDiscoveryMinInterval is a minimum interval between discoveries
+	// forced by failures. It prevents discovery storms when the cluster is
+	// unavailable.
+	// If FastDiscoveryMinInterval is zero then the
+	// DefaultFastDiscoveryMinInterval is used.
+	FastDiscoveryMinInterval time.Duration
+
+	// ConnBanThreshold is a number of consecutive "unavailable" transport
+	// errors after which endpoint is banned. That is, it is removed from the
+	// balancer for ConnBanTTL and then is returned back on probation: the
+	// first failure of the endpoint on probation bans it again, while the
+	// first success makes it online.
+	//
+	// The last online endpoint is never banned.
+	//
+	// If ConnBanThreshold is zero then endpoints are never banned.
+	ConnBanThreshold int
+
+	// ConnBanTTL is a duration of endpoint ban.
+	// If ConnBanTTL is zero then the DefaultConnBanTTL is used.
+	ConnBanTTL time.Duration
+
+	// ConnGracePeriod is a maximum duration of endpoint draining after it was
+	// removed by discovery. Draining endpoint is not used for new operations,
+	// but its connection is kept until all operations and streams in flight
+	// are completed or ConnGracePeriod is passed.
+	//
+	// If ConnGracePeriod is zero then connection is closed immediately.
+	ConnGracePeriod time.Duration
+
+	// Clock is a source of time used by the driver for connection stats,
+	// background discovery and connection tracking.
+	// It is useful mostly for testing purposes.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	// CallMiddlewares is an ordered list of middlewares applied to each
+	// Call() of the driver. The first middleware is the outermost one, that
+	// is, it is called first.
+	//
+	// Middlewares are called before request timeouts and credentials are
+	// applied. Outgoing gRPC metadata set by middlewares is merged with the
+	// driver's one.
+	CallMiddlewares []CallMiddleware
+
+	// StreamReadMiddlewares is an ordered list of middlewares applied to each
+	// StreamRead() of the driver.
+	// See CallMiddlewares for details.
+	StreamReadMiddlewares []StreamReadMiddleware
+
+	// UsageSummary is an optional callback called on driver Close() with
+	// summary of the driver usage: number of calls, errors by status, top
+	// methods by latency and number of discovery rounds. It is helpful for
+	// batch jobs and command line tools which want to report driver usage at
+	// the end of the run.
+	//
+	// See UsageStats for details.
+	UsageSummary func(UsageSummary)
 }
 
 func (d *DriverConfig) withDefaults() (c DriverConfig) {
@@ -166,9 +320,38 @@ func (d *DriverConfig) withDefaults() (c DriverConfig) {
 	if c.ContextDeadlineMapping == 0 {
 		c.ContextDeadlineMapping = DefaultContextDeadlineMapping
 	}
+	if c.ConnBanTTL == 0 {
+		c.ConnBanTTL = DefaultConnBanTTL
+	}
+	if c.FastDiscoveryMinInterval == 0 {
+		c.FastDiscoveryMinInterval = DefaultFastDiscoveryMinInterval
+	}
+	if c.Clock == nil {
+		c.Clock = timeutil.DefaultClock
+	}
 	return c
 }
 
+// EndpointTLS describes how the Endpoint's SSL flag reported by discovery is
+// used when dialing to the endpoint.
+type EndpointTLS uint
+
+const (
+	// EndpointTLSIgnore means that SSL flag is ignored and Dialer's TLSConfig
+	// is used for all endpoints.
+	EndpointTLSIgnore EndpointTLS = iota
+
+	// EndpointTLSUpgrade means that TLS is used for endpoints with SSL flag
+	// set even if Dialer's TLSConfig is nil. Other endpoints are dialed
+	// according to TLSConfig. That is, connections are never downgraded to
+	// plaintext.
+	EndpointTLSUpgrade
+
+	// EndpointTLSFollow means that TLS is used for endpoints with SSL flag set
+	// and plaintext is used for others, regardless of Dialer's TLSConfig.
+	EndpointTLSFollow
+)
+
 // Dialer contains options of dialing and initialization of particular ydb
 // driver.
 type Dialer struct {
@@ -179,54 +362,177 @@ type Dialer struct {
 	// function such as net.Dial("tcp").
 	NetDial func(context.Context, string) (net.Conn, error)
 
+	// PreferIPFamily is a family of IP addresses which are tried first when
+	// endpoint host resolves to both IPv4 and IPv6 addresses.
+	// If PreferIPFamily is IPFamilyAny then the family of the first resolved
+	// address is preferred.
+	//
+	// Addresses are dialed in a Happy Eyeballs manner only if PreferIPFamily
+	// or FallbackDelay is set. Otherwise the default gRPC dialer is used.
+	//
+	// Note that PreferIPFamily and FallbackDelay are not used when NetDial is
+	// set.
+	PreferIPFamily IPFamily
+
+	// FallbackDelay is the amount of time to wait for connection attempt to
+	// the one endpoint address before trying the next one (possibly of the
+	// other IP family) in parallel.
+	// If FallbackDelay is zero then the DefaultFallbackDelay is used.
+	FallbackDelay time.Duration
+
 	// TLSConfig specifies the TLS configuration to use for tls client.
 	// If TLSConfig is zero then connections are insecure.
 	TLSConfig *tls.Config
 
+	// EndpointTLS is a policy of using the SSL flag of discovered endpoints.
+	// Note that the address passed to Dial() is always dialed according to
+	// TLSConfig and address scheme.
+	// If EndpointTLS is zero then the SSL flag is ignored.
+	EndpointTLS EndpointTLS
+
 	// Timeout is the maximum amount of time a dial will wait for a connect to
 	// complete.
 	// If Timeout is zero then no timeout is used.
 	Timeout time.Duration
 
+	// RewriteAddress is an optional function which returns the actual
+	// "host:port" address to dial instead of the given endpoint's one. It is
+	// useful when endpoints are accessible only through address translation
+	// such as port forwarding or service mesh.
+	//
+	// When address is rewritten and TLS is used, the original endpoint's host
+	// is used to verify server certificate (unless TLSServerName is set).
+	RewriteAddress func(addr string) string
+
+	// TLSServerName is an optional function which returns the server name
+	// used to verify certificate of the endpoint with given "host:port"
+	// address. Empty result means default verification. Non-empty result
+	// takes precedence over the TLSConfig's ServerName field.
+	TLSServerName func(addr string) string
+
 	// Keepalive is the interval used to check whether inner connections are
 	// still valid.
 	// If Keepalive is zero then there will be no keepalive checks.
 	//
 	// Dialer could increase keepalive interval if given value is too small.
 	Keepalive time.Duration
+
+	// GRPCDialOptions contains additional options used to dial every
+	// connection of the driver, such as grpc.WithChainUnaryInterceptor() or
+	// grpc.WithChainStreamInterceptor(). They are applied after options
+	// configured by other Dialer fields and thus may override them.
+	GRPCDialOptions []grpc.DialOption
 }
 
 // Dial dials given addr and initializes driver instance on success.
+//
+// The addr may be either "host[:port]" pair or URL-like string such as
+// "grpcs://host[:port]/?database=name". The "grpcs" scheme enables TLS even if
+// TLSConfig is nil. The "database" query parameter is used when
+// DriverConfig's Database field is empty. If addr contains no port, it is
+// inferred from the scheme (see DefaultGRPCPort and DefaultGRPCSPort).
 func (d *Dialer) Dial(ctx context.Context, addr string) (Driver, error) {
 	config := d.DriverConfig.withDefaults()
+	a, err := parseDialAddress(addr, d.TLSConfig != nil)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case a.database == "":
+	case config.Database == "":
+		config.Database = a.database
+	case config.Database != a.database:
+		return nil, fmt.Errorf(
+			"ydb: database %q from address conflicts with configured %q",
+			a.database, config.Database,
+		)
+	}
+	tlsConfig := d.TLSConfig
+	if a.secure && tlsConfig == nil {
+		tlsConfig = new(tls.Config)
+	}
+	events := &eventBus{
+		clock: config.Clock,
+	}
+	config.Trace = composeDriverTrace(config.Trace, events.trace())
+	var usage *UsageStats
+	if config.UsageSummary != nil {
+		usage = &UsageStats{
+			Clock: config.Clock,
+		}
+		usage.init()
+		config.Trace = composeDriverTrace(config.Trace, usage.DriverTrace())
+		config.CallMiddlewares = append(
+			[]CallMiddleware{usage.CallMiddleware()},
+			config.CallMiddlewares...,
+		)
+		config.StreamReadMiddlewares = append(
+			[]StreamReadMiddleware{usage.StreamReadMiddleware()},
+			config.StreamReadMiddlewares...,
+		)
+	}
+	netDial := d.NetDial
+	if netDial == nil && (d.PreferIPFamily != IPFamilyAny || d.FallbackDelay != 0) {
+		netDial = (&netDialer{
+			prefer:        d.PreferIPFamily,
+			fallbackDelay: d.FallbackDelay,
+		}).DialContext
+	}
 	return (&dialer{
-		netDial:   d.NetDial,
-		tlsConfig: d.TLSConfig,
+		netDial:   netDial,
+		tlsConfig: tlsConfig,
+		tlsPolicy: d.EndpointTLS,
+		ssl:       new(sslIndex),
+		rewrite:   d.RewriteAddress,
+		tlsName:   d.TLSServerName,
 		keepalive: d.Keepalive,
+		grpcOpts:  d.GRPCDialOptions,
 		timeout:   d.Timeout,
 		config:    config,
+		discovery: new(discoveryState),
+		events:    events,
+		usage:     usage,
 		meta: &meta{
 			trace:       config.Trace,
+			events:      events,
 			database:    config.Database,
 			credentials: config.Credentials,
+			clock:       config.Clock,
 		},
-	}).dial(ctx, addr)
+	}).dial(ctx, a.hostPort)
 }
 
 // dialer is an instance holding single Dialer.Dial() configuration parameters.
 type dialer struct {
 	netDial   func(context.Context, string) (net.Conn, error)
 	tlsConfig *tls.Config
+	tlsPolicy EndpointTLS
+	rewrite   func(string) string
+	tlsName   func(string) string
 	keepalive time.Duration
+	grpcOpts  []grpc.DialOption
 	timeout   time.Duration
 	config    DriverConfig
 	meta      *meta
+	discovery *discoveryState
+	events    *eventBus
+	usage     *UsageStats
+	ssl       *sslIndex
+}
+
+// sslIndex holds SSL flags of discovered endpoints.
+type sslIndex struct {
+	mu sync.RWMutex
+	m  map[connAddr]bool
 }
 
 func (d *dialer) dial(ctx context.Context, addr string) (_ Driver, err error) {
 	cluster := cluster{
-		dial:  d.dialHostPort,
-		trace: d.config.Trace,
+		dial:   d.dialHostPort,
+		trace:  d.config.Trace,
+		clock:  d.config.Clock,
+		banTTL: d.config.ConnBanTTL,
+		grace:  d.config.ConnGracePeriod,
 	}
 	defer func() {
 		if err != nil {
@@ -234,25 +540,14 @@ func (d *dialer) dial(ctx context.Context, addr string) (_ Driver, err error) {
 		}
 	}()
 	var explorer *repeater
+	d.discovery.interval = d.config.DiscoveryInterval
 	if d.config.DiscoveryInterval > 0 {
-		if d.config.PreferLocalEndpoints {
-			cluster.balancer = newMultiBalancer(
-				withBalancer(
-					d.newBalancer(), func(_ *conn, info connInfo) bool {
-						return info.local
-					},
-				),
-				withBalancer(
-					d.newBalancer(), func(_ *conn, info connInfo) bool {
-						return !info.local
-					},
-				),
-			)
-		} else {
-			cluster.balancer = d.newBalancer()
-		}
+		cluster.balancer = newBalancer(d.config)
 
 		curr, err := d.discover(ctx, addr)
+		if err != nil {
+			curr, err = d.cachedEndpoints(ctx, err)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -262,8 +557,10 @@ func (d *dialer) dial(ctx context.Context, addr string) (_ Driver, err error) {
 		for _, e := range curr {
 			cluster.Insert(ctx, e)
 		}
+		d.config.Trace.discoveryDiff(ctx, curr, nil, nil)
 		explorer = &repeater{
 			Interval: d.config.DiscoveryInterval,
+			Clock:    d.config.Clock,
 			Task: func(ctx context.Context) {
 				next, err := d.discover(ctx, addr)
 				if err != nil {
@@ -271,19 +568,26 @@ func (d *dialer) dial(ctx context.Context, addr string) (_ Driver, err error) {
 				}
 				// NOTE: curr endpoints must be sorted here.
 				sortEndpoints(next)
+				var added, removed, updated []Endpoint
 				diffEndpoints(curr, next,
 					func(i, j int) {
 						// Endpoints are equal but we still need to update meta
 						// data such that load factor and others.
 						cluster.Update(ctx, next[j])
+						if curr[i] != next[j] {
+							updated = append(updated, next[j])
+						}
 					},
 					func(i, j int) {
 						cluster.Insert(ctx, next[j])
+						added = append(added, next[j])
 					},
 					func(i, j int) {
 						cluster.Remove(ctx, curr[i])
+						removed = append(removed, curr[i])
 					},
 				)
+				d.config.Trace.discoveryDiff(ctx, added, removed, updated)
 				curr = next
 			},
 		}
@@ -307,17 +611,36 @@ func (d *dialer) dial(ctx context.Context, addr string) (_ Driver, err error) {
 			return nil, err
 		}
 	}
-	return &driver{
+	driver := &driver{
 		cluster:                &cluster,
 		explorer:               explorer,
+		discovery:              d.discovery,
+		events:                 d.events,
 		meta:                   d.meta,
 		trace:                  d.config.Trace,
 		requestTimeout:         d.config.RequestTimeout,
 		streamTimeout:          d.config.StreamTimeout,
+		streamIdleTimeout:      d.config.StreamIdleTimeout,
 		operationTimeout:       d.config.OperationTimeout,
 		operationCancelAfter:   d.config.OperationCancelAfter,
 		contextDeadlineMapping: d.config.ContextDeadlineMapping,
-	}, nil
+		operationMode:          d.config.OperationMode,
+		connBanThreshold:       d.config.ConnBanThreshold,
+		clock:                  d.config.Clock,
+		usage:                  d.usage,
+		usageSummary:           d.config.UsageSummary,
+	}
+	if explorer != nil && d.config.FastDiscoveryThreshold > 0 {
+		driver.fastDiscovery = &fastDiscovery{
+			threshold:   d.config.FastDiscoveryThreshold,
+			minInterval: d.config.FastDiscoveryMinInterval,
+			clock:       d.config.Clock,
+			force:       explorer.Force,
+		}
+	}
+	driver.call = chainCall(driver.doCall, d.config.CallMiddlewares)
+	driver.streamRead = chainStreamRead(driver.doStreamRead, d.config.StreamReadMiddlewares)
+	return driver, nil
 }
 
 func (d *dialer) dialHostPort(ctx context.Context, host string, port int) (*conn, error) {
@@ -334,14 +657,16 @@ func (d *dialer) dialHostPort(ctx context.Context, host string, port int) (*conn
 	s := addr.String()
 	d.config.Trace.dialStart(rawctx, s)
 
-	cc, err := grpc.DialContext(ctx, s, d.grpcDialOptions()...)
+	tlsConfig := d.endpointTLSConfig(addr)
+	target, serverName := d.target(s, host, tlsConfig)
+	cc, err := grpc.DialContext(ctx, target, d.grpcDialOptions(tlsConfig, serverName)...)
 
 	d.config.Trace.dialDone(rawctx, s, err)
 	if err != nil {
 		return nil, err
 	}
 
-	return newConn(cc, addr), nil
+	return newConn(cc, addr, d.config.Clock), nil
 }
 
 func (d *dialer) dialAddr(ctx context.Context, addr string) (*conn, error) {
@@ -355,6 +680,7 @@ func (d *dialer) dialAddr(ctx context.Context, addr string) (*conn, error) {
 func (d *dialer) discover(ctx context.Context, addr string) (endpoints []Endpoint, err error) {
 	d.config.Trace.discoveryStart(ctx)
 	defer func() {
+		d.discovery.done(d.config.Clock.Now(), len(endpoints), err)
 		d.config.Trace.discoveryDone(ctx, endpoints, err)
 	}()
 
@@ -371,18 +697,101 @@ func (d *dialer) discover(ctx context.Context, addr string) (endpoints []Endpoin
 		defer cancel()
 	}
 
-	return (&discoveryClient{
+	endpoints, err = (&discoveryClient{
 		conn: conn,
 		meta: d.meta,
 	}).Discover(subctx, d.config.Database)
+	if err != nil {
+		return nil, err
+	}
+	d.indexSSL(endpoints)
+	if c := d.config.EndpointsCache; c != nil {
+		_ = c.StoreEndpoints(ctx, endpoints)
+	}
+	return endpoints, nil
+}
+
+// cachedEndpoints returns endpoints from the EndpointsCache if it is set and
+// contains any endpoints. Otherwise it returns given discovery error.
+func (d *dialer) cachedEndpoints(ctx context.Context, discoveryErr error) ([]Endpoint, error) {
+	c := d.config.EndpointsCache
+	if c == nil {
+		return nil, discoveryErr
+	}
+	endpoints, err := c.LoadEndpoints(ctx)
+	if err != nil || len(endpoints) == 0 {
+		return nil, discoveryErr
+	}
+	d.indexSSL(endpoints)
+	return endpoints, nil
 }
 
-func (d *dialer) grpcDialOptions() (opts []grpc.DialOption) {
+// indexSSL remembers endpoints' SSL flags used by endpointTLSConfig().
+func (d *dialer) indexSSL(endpoints []Endpoint) {
+	if d.tlsPolicy == EndpointTLSIgnore || d.ssl == nil {
+		return
+	}
+	m := make(map[connAddr]bool, len(endpoints))
+	for _, e := range endpoints {
+		m[connAddr{e.Addr, e.Port}] = e.SSL
+	}
+	d.ssl.mu.Lock()
+	d.ssl.m = m
+	d.ssl.mu.Unlock()
+}
+
+// endpointTLSConfig returns TLS configuration used to dial the endpoint with
+// given address with respect to the EndpointTLS policy. It returns nil if
+// connection must be insecure.
+func (d *dialer) endpointTLSConfig(addr connAddr) *tls.Config {
+	if d.tlsPolicy == EndpointTLSIgnore || d.ssl == nil {
+		return d.tlsConfig
+	}
+	d.ssl.mu.RLock()
+	ssl, has := d.ssl.m[addr]
+	d.ssl.mu.RUnlock()
+	switch {
+	case !has:
+		return d.tlsConfig
+	case ssl && d.tlsConfig == nil:
+		return new(tls.Config)
+	case !ssl && d.tlsPolicy == EndpointTLSFollow:
+		return nil
+	default:
+		return d.tlsConfig
+	}
+}
+
+// target returns address to dial for the endpoint address addr with given
+// host and the TLS server name to verify (if any) when dialing with tlsConfig.
+func (d *dialer) target(addr, host string, tlsConfig *tls.Config) (target, serverName string) {
+	target = addr
+	if f := d.rewrite; f != nil {
+		if t := f(addr); t != "" && t != addr {
+			target = t
+			if c := tlsConfig; c != nil && c.ServerName == "" {
+				serverName = host
+			}
+		}
+	}
+	if f := d.tlsName; f != nil {
+		if n := f(addr); n != "" {
+			serverName = n
+		}
+	}
+	return target, serverName
+}
+
+func (d *dialer) grpcDialOptions(tlsConfig *tls.Config, serverName string) (opts []grpc.DialOption) {
 	if d.netDial != nil {
 		//nolint:SA1019
 		opts = append(opts, grpc.WithDialer(withContextDialer(d.netDial)))
 	}
-	if c := d.tlsConfig; c != nil {
+	if c := tlsConfig; c != nil {
+		if serverName != "" {
+			c = c.Clone()
+			c.ServerName = serverName
+		}
 		opts = append(opts, grpc.WithTransportCredentials(
 			credentials.NewTLS(c),
 		))
@@ -398,11 +807,33 @@ func (d *dialer) grpcDialOptions() (opts []grpc.DialOption) {
 			}),
 		)
 	}
+	opts = append(opts, d.grpcOpts...)
 	return append(opts, grpc.WithBlock())
 }
 
-func (d *dialer) newBalancer() balancer {
-	return balancers[d.config.BalancingMethod](d.config.BalancingConfig)
+// newBalancer creates balancer described by given config.
+func newBalancer(config DriverConfig) balancer {
+	if config.Balancer != nil {
+		return customBalancer{config.Balancer}
+	}
+	create := func() balancer {
+		return balancers[config.BalancingMethod](config.BalancingConfig)
+	}
+	if !config.PreferLocalEndpoints {
+		return create()
+	}
+	return newMultiBalancer(
+		withBalancer(
+			create(), func(_ *conn, info connInfo) bool {
+				return info.local
+			},
+		),
+		withBalancer(
+			create(), func(_ *conn, info connInfo) bool {
+				return !info.local
+			},
+		),
+	)
 }
 
 type driver struct {
@@ -411,36 +842,82 @@ type driver struct {
 	trace    DriverTrace
 	explorer *repeater
 
+	discovery *discoveryState
+	events    *eventBus
+
 	requestTimeout       time.Duration
 	streamTimeout        time.Duration
+	streamIdleTimeout    time.Duration
 	operationTimeout     time.Duration
 	operationCancelAfter time.Duration
 
 	contextDeadlineMapping ContextDeadlineMapping
+	operationMode          OperationMode
+
+	connBanThreshold int
+	fastDiscovery    *fastDiscovery
+
+	clock timeutil.Clock
+
+	call       CallFunc
+	streamRead StreamReadFunc
+
+	usage        *UsageStats
+	usageSummary func(UsageSummary)
+}
+
+// Database returns the database which driver is dialed to.
+func (d *driver) Database() string {
+	return d.meta.database
 }
 
 func (d *driver) Close() error {
 	if d.explorer != nil {
 		d.explorer.Stop()
 	}
-	return d.cluster.Close()
+	err := d.cluster.Close()
+	if d.usage != nil {
+		d.usageSummary(d.usage.Summary())
+	}
+	return err
 }
 
 func (d *driver) Call(ctx context.Context, op internal.Operation) error {
+	return d.call(ctx, op)
+}
+
+func (d *driver) doCall(ctx context.Context, op internal.Operation) error {
 	// Remember raw context to pass it for the tracing functions.
 	rawctx := ctx
 
+	// Request timeout limits the call as a whole, including the retry below.
 	if t := d.requestTimeout; t > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, t)
 		defer cancel()
 	}
+	err := d.callOnce(rawctx, ctx, op)
+	if isUnauthenticated(err) && d.meta.invalidate() {
+		// Token could be expired or revoked right before the call. Retry the
+		// call once with the refreshed token to avoid failures at token
+		// rotation boundaries.
+		err = d.callOnce(rawctx, ctx, op)
+	}
+	return err
+}
+
+func (d *driver) callOnce(rawctx, ctx context.Context, op internal.Operation) error {
 	if t := d.operationTimeout; t > 0 {
 		ctx = WithOperationTimeout(ctx, t)
 	}
 	if t := d.operationCancelAfter; t > 0 {
 		ctx = WithOperationCancelAfter(ctx, t)
 	}
+	if m := d.operationMode; m != OperationModeUnknown {
+		if _, ok := ContextOperationMode(ctx); !ok {
+			ctx = WithOperationMode(ctx, m)
+		}
+	}
 
 	// Get credentials (token actually) for the request.
 	md, err := d.meta.md(ctx)
@@ -448,13 +925,16 @@ func (d *driver) Call(ctx context.Context, op internal.Operation) error {
 		return err
 	}
 	if len(md) > 0 {
-		ctx = metadata.NewOutgoingContext(ctx, md)
+		ctx = outgoingContext(ctx, md)
 	}
 
 	d.trace.getConnStart(rawctx)
 	conn, err := d.cluster.Get(ctx)
 	d.trace.getConnDone(rawctx, conn, err)
 	if err != nil {
+		if err != ErrClosed {
+			d.fastDiscovery.report(err)
+		}
 		return err
 	}
 
@@ -466,21 +946,73 @@ func (d *driver) Call(ctx context.Context, op internal.Operation) error {
 		setOperationParams(req, params)
 	}
 
-	start := timeutil.Now()
+	start := d.clock.Now()
 	conn.runtime.operationStart(start)
 	d.trace.operationStart(rawctx, conn, method, params)
 
-	err = invoke(ctx, conn.conn, &resp, method, req, res)
+	if isOperationServiceMethod(method) {
+		err = invokeOperationService(ctx, conn.conn, method, req, res)
+	} else {
+		err = invoke(ctx, conn.conn, &resp, method, req, res)
+	}
 
-	conn.runtime.operationDone(
-		start, timeutil.Now(),
-		errIf(isTimeoutError(err), err),
-	)
+	if err != nil {
+		details := OperationDetails{
+			Method:  method,
+			Params:  params,
+			Elapsed: d.clock.Now().Sub(start),
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			details.Deadline = deadline.Sub(start)
+			details.HasDeadline = true
+		}
+		withOperationDetails(err, details)
+	}
+	if isClientCancel(rawctx, err) {
+		conn.runtime.operationCanceled()
+	} else {
+		conn.runtime.operationDone(
+			start, d.clock.Now(),
+			errIf(isTimeoutError(err), err),
+		)
+		d.checkConn(conn, err)
+		if _, ok := err.(*TransportError); ok {
+			d.fastDiscovery.report(err)
+		} else {
+			d.fastDiscovery.report(nil)
+		}
+	}
 	d.trace.operationDone(rawctx, conn, method, params, resp, err)
 
 	return err
 }
 
+// checkConn accounts result of the operation made on conn and bans conn if
+// it is failing. See DriverConfig's ConnBanThreshold for details.
+func (d *driver) checkConn(conn *conn, err error) {
+	if d.connBanThreshold <= 0 {
+		return
+	}
+	n := conn.runtime.unavailableDone(IsTransportError(err, TransportErrorUnavailable))
+	probation := conn.runtime.getState() == ConnProbation
+	switch {
+	case n == 0 && probation:
+		conn.runtime.setState(ConnOnline)
+	case n > 0 && (probation || n >= d.connBanThreshold):
+		d.cluster.Ban(conn)
+	}
+}
+
+func isUnauthenticated(err error) bool {
+	if IsOpError(err, StatusUnauthorized) {
+		return true
+	}
+	if e, ok := err.(*TransportError); ok {
+		return e.Reason == TransportErrorUnauthenticated
+	}
+	return false
+}
+
 func isTimeoutError(err error) bool {
 	if IsOpError(err, StatusTimeout) ||
 		IsOpError(err, StatusCancelled) {
@@ -498,6 +1030,13 @@ func isTimeoutError(err error) bool {
 	return false
 }
 
+// isClientCancel reports whether err is caused by the caller's context
+// cancelation or deadline. Such errors are not the endpoint's fault and thus
+// must not pessimize it.
+func isClientCancel(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() != nil
+}
+
 func errIf(cond bool, err error) error {
 	if cond {
 		return err
@@ -505,7 +1044,11 @@ func errIf(cond bool, err error) error {
 	return nil
 }
 
-func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) (err error) {
+func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) error {
+	return d.streamRead(ctx, op)
+}
+
+func (d *driver) doStreamRead(ctx context.Context, op internal.StreamOperation) (err error) {
 	// Remember raw context to pass it for the tracing functions.
 	rawctx := ctx
 
@@ -518,6 +1061,14 @@ func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) (e
 			}
 		}()
 	}
+	if d.streamIdleTimeout > 0 && cancel == nil {
+		ctx, cancel = context.WithCancel(ctx)
+		defer func() {
+			if err != nil {
+				cancel()
+			}
+		}()
+	}
 
 	// Get credentials (token actually) for the request.
 	md, err := d.meta.md(ctx)
@@ -525,7 +1076,7 @@ func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) (e
 		return err
 	}
 	if len(md) > 0 {
-		ctx = metadata.NewOutgoingContext(ctx, md)
+		ctx = outgoingContext(ctx, md)
 	}
 
 	d.trace.getConnStart(rawctx)
@@ -541,11 +1092,11 @@ func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) (e
 		ServerStreams: true,
 	}
 
-	conn.runtime.streamStart(timeutil.Now())
+	conn.runtime.streamStart(d.clock.Now())
 	d.trace.streamStart(rawctx, conn, method)
 	defer func() {
 		if err != nil {
-			conn.runtime.streamDone(timeutil.Now(), err)
+			conn.runtime.streamDone(d.clock.Now(), errIf(!isClientCancel(rawctx, err), err))
 			d.trace.streamDone(rawctx, conn, method, err)
 		}
 	}()
@@ -559,29 +1110,39 @@ func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) (e
 	if err := s.SendMsg(req); err != nil {
 		return mapGRPCError(err)
 	}
+	conn.runtime.streamSend(proto.Size(req))
 	if err := s.CloseSend(); err != nil {
 		return mapGRPCError(err)
 	}
 
+	idle := d.watchStreamIdle(cancel)
+
 	go func() {
 		var err error
 		defer func() {
-			conn.runtime.streamDone(timeutil.Now(), hideEOF(err))
+			conn.runtime.streamDone(d.clock.Now(), errIf(!isClientCancel(rawctx, err), hideEOF(err)))
 			d.trace.streamDone(rawctx, conn, method, hideEOF(err))
 			if cancel != nil {
 				cancel()
 			}
+			idle.stop()
 		}()
 		for err == nil {
-			conn.runtime.streamRecv(timeutil.Now())
 			d.trace.streamRecvStart(rawctx, conn, method)
 
+			idle.reset()
 			err = s.RecvMsg(resp)
+			idle.pause()
 
 			d.trace.streamRecvDone(rawctx, conn, method, resp, hideEOF(err))
-			if err != nil {
+			if err != nil && idle.exceeded() {
+				err = ErrStreamIdle
+			} else if err != nil {
 				err = mapGRPCError(err)
 			} else {
+				if m, ok := resp.(proto.Message); ok {
+					conn.runtime.streamRecv(proto.Size(m))
+				}
 				if s := resp.GetStatus(); s != Ydb.StatusIds_SUCCESS {
 					err = &OpError{
 						Reason: statusCode(s),
@@ -597,6 +1158,66 @@ func (d *driver) StreamRead(ctx context.Context, op internal.StreamOperation) (e
 	return nil
 }
 
+// streamIdleWatcher cancels the stream when it waits for the next message for
+// too long. Nil *streamIdleWatcher is a valid no-op watcher.
+type streamIdleWatcher struct {
+	timeout time.Duration
+	timer   timeutil.Timer
+	cancel  context.CancelFunc
+	done    chan struct{}
+	fired   uint32
+}
+
+func (d *driver) watchStreamIdle(cancel context.CancelFunc) *streamIdleWatcher {
+	t := d.streamIdleTimeout
+	if t <= 0 {
+		return nil
+	}
+	w := &streamIdleWatcher{
+		timeout: t,
+		timer:   d.clock.NewTimer(t),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	w.timer.Stop()
+	go w.watch()
+	return w
+}
+
+func (w *streamIdleWatcher) watch() {
+	select {
+	case <-w.timer.C():
+		atomic.StoreUint32(&w.fired, 1)
+		w.cancel()
+	case <-w.done:
+	}
+}
+
+// reset starts measuring time of waiting for the next message.
+func (w *streamIdleWatcher) reset() {
+	if w != nil {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// pause stops measuring time, e.g. while received message is processed.
+func (w *streamIdleWatcher) pause() {
+	if w != nil {
+		w.timer.Stop()
+	}
+}
+
+func (w *streamIdleWatcher) stop() {
+	if w != nil {
+		w.timer.Stop()
+		close(w.done)
+	}
+}
+
+func (w *streamIdleWatcher) exceeded() bool {
+	return w != nil && atomic.LoadUint32(&w.fired) == 1
+}
+
 func invoke(
 	ctx context.Context, conn *grpc.ClientConn,
 	resp *Ydb_Operations.GetOperationResponse,
@@ -633,6 +1254,37 @@ func invoke(
 	return proto.Unmarshal(op.Result.Value, res)
 }
 
+// operationServicePrefix is the prefix of methods of the YDB Operation
+// service. Unlike the other services, its responses are not wrapped into the
+// Operation message.
+const operationServicePrefix = "/Ydb.Operation.V1.OperationService/"
+
+func isOperationServiceMethod(method string) bool {
+	return strings.HasPrefix(method, operationServicePrefix)
+}
+
+// invokeOperationService is the same as invoke() but for the Operation service
+// methods. That is, res is the whole response message. Status of the response
+// is checked if it is present.
+func invokeOperationService(
+	ctx context.Context, conn *grpc.ClientConn,
+	method string, req, res proto.Message,
+	opts ...grpc.CallOption,
+) error {
+	err := grpc.Invoke(ctx, method, req, res, conn, opts...)
+	if err != nil {
+		return mapGRPCError(err)
+	}
+	r, ok := res.(internal.StreamOperationResponse)
+	if ok && r.GetStatus() != Ydb.StatusIds_SUCCESS {
+		return &OpError{
+			Reason: statusCode(r.GetStatus()),
+			issues: r.GetIssues(),
+		}
+	}
+	return nil
+}
+
 func Dial(ctx context.Context, addr string, c *DriverConfig) (Driver, error) {
 	d := Dialer{
 		DriverConfig: c,
@@ -649,8 +1301,9 @@ func mapGRPCError(err error) error {
 	}
 	s := grpcErr.GRPCStatus()
 	return &TransportError{
-		Reason:  transportErrorCode(s.Code()),
-		message: s.Message(),
+		Reason:        transportErrorCode(s.Code()),
+		message:       s.Message(),
+		statusDetails: statusDetails(s),
 	}
 }
 
@@ -664,13 +1317,25 @@ func (c connAddr) String() string {
 }
 
 type conn struct {
+	// runtime must be the first field to guarantee 64-bit alignment of its
+	// atomically accessed counters.
+	runtime connRuntime
+
 	conn *grpc.ClientConn
 	addr connAddr
+}
 
-	runtime connRuntime
+// Addr implements BalancerConn.
+func (c *conn) Addr() string {
+	return c.addr.String()
+}
+
+// Stats implements BalancerConn.
+func (c *conn) Stats() ConnStats {
+	return c.runtime.stats()
 }
 
-func newConn(cc *grpc.ClientConn, addr connAddr) *conn {
+func newConn(cc *grpc.ClientConn, addr connAddr, clock timeutil.Clock) *conn {
 	const (
 		statsDuration = time.Minute
 		statsBuckets  = 12
@@ -679,6 +1344,7 @@ func newConn(cc *grpc.ClientConn, addr connAddr) *conn {
 		conn: cc,
 		addr: addr,
 		runtime: connRuntime{
+			clock:   timeutil.ClockOrDefault(clock),
 			opTime:  stats.NewSeries(statsDuration, statsBuckets),
 			opRate:  stats.NewSeries(statsDuration, statsBuckets),
 			errRate: stats.NewSeries(statsDuration, statsBuckets),
@@ -686,25 +1352,88 @@ func newConn(cc *grpc.ClientConn, addr connAddr) *conn {
 	}
 }
 
+// connRuntime contains connection runtime stats. It does not use locks such
+// that stats updates are cheap on the hot path of each operation.
+//
+// Counters must be accessed atomically.
 type connRuntime struct {
-	mu        sync.Mutex
-	state     ConnState
-	opStarted uint64
-	opSucceed uint64
-	opFailed  uint64
-	opTime    *stats.Series
-	opRate    *stats.Series
-	errRate   *stats.Series
+	opStarted  uint64
+	opSucceed  uint64
+	opFailed   uint64
+	opCanceled uint64
+	state      uint32
+
+	// unavailable is a number of consecutive operations failed due to
+	// endpoint unavailability.
+	unavailable uint32
+
+	streamStarted      uint64
+	streamFinished     uint64
+	streamMessagesSent uint64
+	streamMessagesRecv uint64
+	streamBytesSent    uint64
+	streamBytesRecv    uint64
+
+	clock   timeutil.Clock
+	opTime  *stats.Series
+	opRate  *stats.Series
+	errRate *stats.Series
+
+	// draining is set to 1 by drained(). It makes completion of operations
+	// and streams to check whether the drainCh must be closed.
+	draining  uint32
+	drainMu   sync.Mutex
+	drainCh   chan struct{}
+	drainDone bool
 }
 
 type ConnStats struct {
-	State        ConnState
+	State     ConnState
+	OpStarted uint64
+	OpFailed  uint64
+	OpSucceed uint64
+
+	// OpCanceled is a number of operations failed due to the caller's
+	// context cancelation or deadline. Such operations are counted neither as
+	// failed nor as succeed and do not affect the ErrPerMinute or AvgOpTime.
+	OpCanceled uint64
+
+	OpPerMinute  float64
+	ErrPerMinute float64
+	AvgOpTime    time.Duration
+
+	// StreamStarted is a number of started streams. Each stream is also
+	// counted as a single operation in OpPerMinute regardless of the number
+	// of its messages.
+	StreamStarted uint64
+
+	// StreamActive is a number of streams not completed yet.
+	StreamActive uint64
+
+	StreamMessagesSent uint64
+	StreamMessagesRecv uint64
+	StreamBytesSent    uint64
+	StreamBytesRecv    uint64
+
+	// Time is the moment when stats were read.
+	Time time.Time
+}
+
+// ConnStatsDelta contains changes of connection stats during some interval.
+type ConnStatsDelta struct {
+	Interval     time.Duration
 	OpStarted    uint64
 	OpFailed     uint64
 	OpSucceed    uint64
+	OpCanceled   uint64
 	OpPerMinute  float64
 	ErrPerMinute float64
-	AvgOpTime    time.Duration
+
+	StreamStarted      uint64
+	StreamMessagesSent uint64
+	StreamMessagesRecv uint64
+	StreamBytesSent    uint64
+	StreamBytesRecv    uint64
 }
 
 type ConnState uint
@@ -713,6 +1442,23 @@ const (
 	ConnStateUnknown ConnState = iota
 	ConnOnline
 	ConnOffline
+
+	// ConnBanned is a state of the endpoint which is temporarily excluded
+	// from balancing due to consecutive transport errors.
+	ConnBanned
+
+	// ConnProbation is a state of the endpoint which is returned to
+	// balancing after ban. Its first failure bans it again.
+	ConnProbation
+
+	// ConnDraining is a state of the endpoint removed by discovery, which
+	// connection is kept until operations in flight are completed.
+	ConnDraining
+
+	// ConnConnecting is a state of the endpoint which could not be dialed.
+	// Such endpoint stays registered and is re-dialed with backoff in
+	// background until it is removed by discovery.
+	ConnConnecting
 )
 
 func (s ConnState) String() string {
@@ -721,6 +1467,14 @@ func (s ConnState) String() string {
 		return "online"
 	case ConnOffline:
 		return "offline"
+	case ConnBanned:
+		return "banned"
+	case ConnProbation:
+		return "probation"
+	case ConnDraining:
+		return "draining"
+	case ConnConnecting:
+		return "connecting"
 	default:
 		return "unknown"
 	}
@@ -734,23 +1488,141 @@ func ReadConnStats(d Driver, f func(Endpoint, ConnStats)) {
 	x.cluster.Stats(f)
 }
 
+// DiscoveryStats contains information about endpoints discovery of the
+// driver.
+type DiscoveryStats struct {
+	// Enabled reports whether driver discovers endpoints periodically (that
+	// is, DriverConfig's DiscoveryInterval is greater than zero).
+	Enabled bool
+
+	// Interval is the interval of endpoints discovery.
+	Interval time.Duration
+
+	// LastAttempt is the time when the last discovery round was finished.
+	LastAttempt time.Time
+
+	// LastSuccess is the time when the last successful discovery round was
+	// finished.
+	LastSuccess time.Time
+
+	// LastError is the error of the last discovery round (if any).
+	LastError error
+
+	// Endpoints is the number of endpoints found by last successful discovery
+	// round.
+	Endpoints int
+}
+
+// ReadDiscoveryStats returns discovery stats of the driver d. It returns
+// false if d is not created by Dialer.
+func ReadDiscoveryStats(d Driver) (stats DiscoveryStats, ok bool) {
+	x, ok := d.(*driver)
+	if !ok {
+		return stats, false
+	}
+	return x.discovery.stats(), true
+}
+
+type discoveryState struct {
+	mu          sync.Mutex
+	interval    time.Duration
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastError   error
+	endpoints   int
+}
+
+func (s *discoveryState) done(now time.Time, endpoints int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAttempt = now
+	s.lastError = err
+	if err == nil {
+		s.lastSuccess = now
+		s.endpoints = endpoints
+	}
+}
+
+func (s *discoveryState) stats() DiscoveryStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return DiscoveryStats{
+		Enabled:     s.interval > 0,
+		Interval:    s.interval,
+		LastAttempt: s.lastAttempt,
+		LastSuccess: s.lastSuccess,
+		LastError:   s.lastError,
+		Endpoints:   s.endpoints,
+	}
+}
+
 func (c ConnStats) OpPending() uint64 {
-	return c.OpStarted - (c.OpFailed + c.OpSucceed)
+	return c.OpStarted - (c.OpFailed + c.OpSucceed + c.OpCanceled)
 }
 
-func (c *connRuntime) stats() ConnStats {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// StatsSince returns changes of counters since prev stats of the same
+// endpoint were read. It is intended for polling exporters which need
+// per-interval values instead of absolute counters.
+//
+// If prev is zero or counters were reset since then (that is, connection was
+// recreated), then changes are counted from zero.
+func (c ConnStats) StatsSince(prev ConnStats) (d ConnStatsDelta) {
+	if c.OpStarted < prev.OpStarted ||
+		c.OpFailed < prev.OpFailed ||
+		c.OpSucceed < prev.OpSucceed ||
+		c.OpCanceled < prev.OpCanceled ||
+		c.StreamStarted < prev.StreamStarted ||
+		c.StreamMessagesSent < prev.StreamMessagesSent ||
+		c.StreamMessagesRecv < prev.StreamMessagesRecv ||
+		c.StreamBytesSent < prev.StreamBytesSent ||
+		c.StreamBytesRecv < prev.StreamBytesRecv {
+		prev = ConnStats{Time: prev.Time}
+	}
+	d = ConnStatsDelta{
+		OpStarted:  c.OpStarted - prev.OpStarted,
+		OpFailed:   c.OpFailed - prev.OpFailed,
+		OpSucceed:  c.OpSucceed - prev.OpSucceed,
+		OpCanceled: c.OpCanceled - prev.OpCanceled,
+
+		StreamStarted:      c.StreamStarted - prev.StreamStarted,
+		StreamMessagesSent: c.StreamMessagesSent - prev.StreamMessagesSent,
+		StreamMessagesRecv: c.StreamMessagesRecv - prev.StreamMessagesRecv,
+		StreamBytesSent:    c.StreamBytesSent - prev.StreamBytesSent,
+		StreamBytesRecv:    c.StreamBytesRecv - prev.StreamBytesRecv,
+	}
+	if !prev.Time.IsZero() && c.Time.After(prev.Time) {
+		d.Interval = c.Time.Sub(prev.Time)
+		m := float64(time.Minute) / float64(d.Interval)
+		d.OpPerMinute = float64(d.OpStarted) * m
+		d.ErrPerMinute = float64(d.OpFailed) * m
+	}
+	return d
+}
 
-	now := timeutil.Now()
+func (c *connRuntime) stats() ConnStats {
+	now := c.clock.Now()
 
 	r := ConnStats{
-		State:        c.state,
-		OpStarted:    c.opStarted,
-		OpSucceed:    c.opSucceed,
-		OpFailed:     c.opFailed,
+		State:        ConnState(atomic.LoadUint32(&c.state)),
+		OpStarted:    atomic.LoadUint64(&c.opStarted),
+		OpSucceed:    atomic.LoadUint64(&c.opSucceed),
+		OpFailed:     atomic.LoadUint64(&c.opFailed),
+		OpCanceled:   atomic.LoadUint64(&c.opCanceled),
 		OpPerMinute:  c.opRate.SumPer(now, time.Minute),
 		ErrPerMinute: c.errRate.SumPer(now, time.Minute),
+		Time:         now,
+
+		StreamStarted:      atomic.LoadUint64(&c.streamStarted),
+		StreamActive:       c.streamActive(),
+		StreamMessagesSent: atomic.LoadUint64(&c.streamMessagesSent),
+		StreamMessagesRecv: atomic.LoadUint64(&c.streamMessagesRecv),
+		StreamBytesSent:    atomic.LoadUint64(&c.streamBytesSent),
+		StreamBytesRecv:    atomic.LoadUint64(&c.streamBytesRecv),
+	}
+	if done := r.OpSucceed + r.OpFailed + r.OpCanceled; r.OpStarted < done {
+		// Counters are read not simultaneously, thus operation could be done
+		// after opStarted was read.
+		r.OpStarted = done
 	}
 	if rtSum, rtCnt := c.opTime.Get(now); rtCnt > 0 {
 		r.AvgOpTime = time.Duration(rtSum / float64(rtCnt))
@@ -759,51 +1631,137 @@ func (c *connRuntime) stats() ConnStats {
 	return r
 }
 
+// opPending returns number of operations in flight. It is cheaper than
+// stats().OpPending().
+func (c *connRuntime) opPending() uint64 {
+	done := atomic.LoadUint64(&c.opSucceed) +
+		atomic.LoadUint64(&c.opFailed) +
+		atomic.LoadUint64(&c.opCanceled)
+	started := atomic.LoadUint64(&c.opStarted)
+	if started < done {
+		return 0
+	}
+	return started - done
+}
+
+// streamActive returns number of streams in flight.
+func (c *connRuntime) streamActive() uint64 {
+	done := atomic.LoadUint64(&c.streamFinished)
+	started := atomic.LoadUint64(&c.streamStarted)
+	if started < done {
+		return 0
+	}
+	return started - done
+}
+
+// idle reports whether there are no operations and streams in flight.
+func (c *connRuntime) idle() bool {
+	return c.opPending() == 0 && c.streamActive() == 0
+}
+
+// drained returns a channel which is closed when there are no operations and
+// streams in flight.
+func (c *connRuntime) drained() <-chan struct{} {
+	c.drainMu.Lock()
+	defer c.drainMu.Unlock()
+	if c.drainCh == nil {
+		c.drainCh = make(chan struct{})
+		atomic.StoreUint32(&c.draining, 1)
+	}
+	c.checkDrained()
+	return c.drainCh
+}
+
+// done must be called after operation or stream is completed.
+func (c *connRuntime) done() {
+	if atomic.LoadUint32(&c.draining) == 0 {
+		return
+	}
+	c.drainMu.Lock()
+	defer c.drainMu.Unlock()
+	c.checkDrained()
+}
+
+// c.drainMu must be held.
+func (c *connRuntime) checkDrained() {
+	if c.drainDone || !c.idle() {
+		return
+	}
+	c.drainDone = true
+	close(c.drainCh)
+}
+
 func (c *connRuntime) setState(s ConnState) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.state = s
+	atomic.StoreUint32(&c.state, uint32(s))
 }
 
-func (c *connRuntime) operationStart(start time.Time) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *connRuntime) getState() ConnState {
+	return ConnState(atomic.LoadUint32(&c.state))
+}
 
-	c.opStarted++
+// unavailableDone accounts operation result and returns the number of
+// consecutive operations failed due to endpoint unavailability.
+func (c *connRuntime) unavailableDone(unavailable bool) int {
+	if !unavailable {
+		atomic.StoreUint32(&c.unavailable, 0)
+		return 0
+	}
+	return int(atomic.AddUint32(&c.unavailable, 1))
+}
+
+func (c *connRuntime) operationStart(start time.Time) {
+	atomic.AddUint64(&c.opStarted, 1)
 	c.opRate.Add(start, 1)
 }
 
 func (c *connRuntime) operationDone(start, end time.Time, err error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	if err != nil {
-		c.opFailed++
+		atomic.AddUint64(&c.opFailed, 1)
 		c.errRate.Add(end, 1)
 	} else {
-		c.opSucceed++
+		atomic.AddUint64(&c.opSucceed, 1)
 	}
 	c.opTime.Add(end, float64(end.Sub(start)))
+	c.done()
+}
+
+// operationCanceled accounts operation failed due to the caller's context
+// cancelation. It does not affect error rate and operation time stats.
+func (c *connRuntime) operationCanceled() {
+	atomic.AddUint64(&c.opCanceled, 1)
+	c.done()
 }
 
 func (c *connRuntime) streamStart(now time.Time) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	atomic.AddUint64(&c.streamStarted, 1)
 	c.opRate.Add(now, 1)
 }
 
-func (c *connRuntime) streamRecv(now time.Time) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.opRate.Add(now, 1)
+func (c *connRuntime) streamSend(size int) {
+	atomic.AddUint64(&c.streamMessagesSent, 1)
+	atomic.AddUint64(&c.streamBytesSent, uint64(size))
+}
+
+func (c *connRuntime) streamRecv(size int) {
+	atomic.AddUint64(&c.streamMessagesRecv, 1)
+	atomic.AddUint64(&c.streamBytesRecv, uint64(size))
 }
 
 func (c *connRuntime) streamDone(now time.Time, err error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	atomic.AddUint64(&c.streamFinished, 1)
 	if err != nil {
 		c.errRate.Add(now, 1)
 	}
+	c.done()
+}
+
+// outgoingContext returns context with outgoing metadata md merged with the
+// one already stored in ctx (if any).
+func outgoingContext(ctx context.Context, md metadata.MD) context.Context {
+	if prev, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(prev, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
 }
 
 // withContextDialer is an adapter to allow the use of normal go-world net dial
@@ -1 +1,483 @@
 package ydb
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestConnStatsSince(t *testing.T) {
+	var (
+		errFail = errors.New("fail")
+		clock   = timetest.NewClock(time.Unix(0, 0))
+		c       = newConn(nil, connAddr{"a", 1}, clock)
+		calls   int
+	)
+	call := func() {
+		var err error
+		if calls%2 == 0 {
+			err = errFail
+		}
+		calls++
+		now := clock.Now()
+		c.runtime.operationStart(now)
+		c.runtime.operationDone(now, now, err)
+	}
+	read := func() ConnStats {
+		return c.runtime.stats()
+	}
+	for i := 0; i < 4; i++ {
+		call()
+	}
+	prev := read()
+	if d := prev.StatsSince(ConnStats{}); d.OpStarted != 4 || d.Interval != 0 {
+		t.Fatalf("unexpected delta from zero stats: %+v", d)
+	}
+
+	clock.Shift(30 * time.Second)
+	for i := 0; i < 6; i++ {
+		call()
+	}
+	d := read().StatsSince(prev)
+	exp := ConnStatsDelta{
+		Interval:     30 * time.Second,
+		OpStarted:    6,
+		OpFailed:     3,
+		OpSucceed:    3,
+		OpPerMinute:  12,
+		ErrPerMinute: 6,
+	}
+	if d != exp {
+		t.Fatalf("unexpected delta: %+v; want %+v", d, exp)
+	}
+}
+
+func TestConnStatsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if isClientCancel(ctx, context.Canceled) {
+		t.Fatalf("unexpected client cancel before context is canceled")
+	}
+	cancel()
+	if !isClientCancel(ctx, &TransportError{Reason: TransportErrorCanceled}) {
+		t.Fatalf("expected client cancel after context is canceled")
+	}
+
+	c := newConn(nil, connAddr{"a", 1}, nil)
+	now := time.Now()
+	c.runtime.operationStart(now)
+	c.runtime.operationStart(now)
+	c.runtime.operationCanceled()
+
+	s := c.runtime.stats()
+	if s.OpCanceled != 1 || s.OpFailed != 0 || s.OpPending() != 1 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+}
+
+func TestConnStatsStream(t *testing.T) {
+	c := newConn(nil, connAddr{"a", 1}, nil)
+	now := time.Now()
+	c.runtime.streamStart(now)
+	c.runtime.streamSend(10)
+	for i := 0; i < 3; i++ {
+		c.runtime.streamRecv(100)
+	}
+	c.runtime.streamDone(now, nil)
+
+	s := c.runtime.stats()
+	if s.StreamStarted != 1 ||
+		s.StreamMessagesSent != 1 ||
+		s.StreamMessagesRecv != 3 ||
+		s.StreamBytesSent != 10 ||
+		s.StreamBytesRecv != 300 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+	if d := s.StatsSince(ConnStats{}); d.StreamBytesRecv != 300 {
+		t.Fatalf("unexpected delta: %+v", d)
+	}
+}
+
+func TestStreamIdleWatcher(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(0, 0))
+	d := &driver{
+		streamIdleTimeout: time.Second,
+		clock:             clock,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := d.watchStreamIdle(cancel)
+	defer w.stop()
+
+	// Time spent while message is being processed must not be counted.
+	clock.Shift(time.Hour)
+	assertNoRecv(t, 50*time.Millisecond, ctx.Done())
+
+	w.reset()
+	clock.Shift(time.Second - 1)
+	w.pause()
+	w.reset()
+	clock.Shift(time.Second - 1)
+	assertNoRecv(t, 50*time.Millisecond, ctx.Done())
+	if w.exceeded() {
+		t.Fatalf("unexpected idle timeout")
+	}
+
+	clock.Shift(1)
+	assertRecv(t, 500*time.Millisecond, ctx.Done())
+	if !w.exceeded() {
+		t.Fatalf("expected idle timeout")
+	}
+
+	var nop *streamIdleWatcher
+	nop.reset()
+	nop.pause()
+	nop.stop()
+	if nop.exceeded() {
+		t.Fatalf("unexpected idle timeout of nil watcher")
+	}
+}
+
+func TestMapGRPCErrorDetails(t *testing.T) {
+	s, err := status.New(codes.Unavailable, "overloaded").WithDetails(
+		&errdetails.DebugInfo{Detail: "debug"},
+		&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(3 * time.Second)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := mapGRPCError(s.Err()).(*TransportError)
+	if !ok {
+		t.Fatalf("unexpected error type")
+	}
+	if n := len(e.Details()); n != 2 {
+		t.Fatalf("unexpected number of details: %d", n)
+	}
+	if d, ok := e.RetryDelay(); !ok || d != 3*time.Second {
+		t.Fatalf("unexpected retry delay: %v (%t)", d, ok)
+	}
+	if d, ok := (&TransportError{}).RetryDelay(); ok {
+		t.Fatalf("unexpected retry delay: %v", d)
+	}
+}
+
+func TestDialerTarget(t *testing.T) {
+	rewrite := func(addr string) string {
+		if addr == "node:2135" {
+			return "localhost:12135"
+		}
+		return ""
+	}
+	for _, test := range []struct {
+		name       string
+		dialer     dialer
+		target     string
+		serverName string
+	}{
+		{
+			name:   "none",
+			dialer: dialer{},
+			target: "node:2135",
+		},
+		{
+			name: "rewrite insecure",
+			dialer: dialer{
+				rewrite: rewrite,
+			},
+			target: "localhost:12135",
+		},
+		{
+			name: "rewrite",
+			dialer: dialer{
+				tlsConfig: new(tls.Config),
+				rewrite:   rewrite,
+			},
+			target:     "localhost:12135",
+			serverName: "node",
+		},
+		{
+			name: "rewrite with server name",
+			dialer: dialer{
+				tlsConfig: &tls.Config{ServerName: "ydb"},
+				rewrite:   rewrite,
+			},
+			target: "localhost:12135",
+		},
+		{
+			name: "override",
+			dialer: dialer{
+				tlsConfig: &tls.Config{ServerName: "ydb"},
+				rewrite:   rewrite,
+				tlsName: func(string) string {
+					return "node.ydb"
+				},
+			},
+			target:     "localhost:12135",
+			serverName: "node.ydb",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			target, serverName := test.dialer.target("node:2135", "node", test.dialer.tlsConfig)
+			if target != test.target {
+				t.Errorf("unexpected target: %q; want %q", target, test.target)
+			}
+			if serverName != test.serverName {
+				t.Errorf("unexpected server name: %q; want %q", serverName, test.serverName)
+			}
+		})
+	}
+}
+
+func TestDialerEndpointTLSConfig(t *testing.T) {
+	var (
+		secure   = connAddr{"secure", 2135}
+		insecure = connAddr{"insecure", 2135}
+		unknown  = connAddr{"unknown", 2135}
+		global   = new(tls.Config)
+	)
+	for _, test := range []struct {
+		name   string
+		policy EndpointTLS
+		config *tls.Config
+		exp    map[connAddr]string
+	}{
+		{
+			name:   "ignore",
+			policy: EndpointTLSIgnore,
+			exp: map[connAddr]string{
+				secure:   "none",
+				insecure: "none",
+				unknown:  "none",
+			},
+		},
+		{
+			name:   "upgrade",
+			policy: EndpointTLSUpgrade,
+			exp: map[connAddr]string{
+				secure:   "new",
+				insecure: "none",
+				unknown:  "none",
+			},
+		},
+		{
+			name:   "upgrade with config",
+			policy: EndpointTLSUpgrade,
+			config: global,
+			exp: map[connAddr]string{
+				secure:   "global",
+				insecure: "global",
+				unknown:  "global",
+			},
+		},
+		{
+			name:   "follow with config",
+			policy: EndpointTLSFollow,
+			config: global,
+			exp: map[connAddr]string{
+				secure:   "global",
+				insecure: "none",
+				unknown:  "global",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			d := dialer{
+				tlsConfig: test.config,
+				tlsPolicy: test.policy,
+				ssl: &sslIndex{
+					m: map[connAddr]bool{
+						secure:   true,
+						insecure: false,
+					},
+				},
+			}
+			for addr, exp := range test.exp {
+				var act string
+				switch c := d.endpointTLSConfig(addr); {
+				case c == nil:
+					act = "none"
+				case c == global:
+					act = "global"
+				default:
+					act = "new"
+				}
+				if act != exp {
+					t.Errorf("unexpected tls config for %s: %s; want %s", addr, act, exp)
+				}
+			}
+		})
+	}
+}
+
+func TestOperationDetails(t *testing.T) {
+	details := OperationDetails{
+		Method: "/Ydb.Table.V1.TableService/ExecuteDataQuery",
+		Params: OperationParams{
+			Timeout: time.Second,
+		},
+		Deadline:    500 * time.Millisecond,
+		HasDeadline: true,
+		Elapsed:     500 * time.Millisecond,
+	}
+	for _, test := range []struct {
+		err     error
+		details bool
+	}{
+		{&OpError{Reason: StatusTimeout}, true},
+		{&OpError{Reason: StatusCancelled}, true},
+		{&OpError{Reason: StatusOverloaded}, false},
+		{&TransportError{Reason: TransportErrorDeadlineExceeded}, true},
+		{&TransportError{Reason: TransportErrorUnavailable}, false},
+		{context.DeadlineExceeded, false},
+	} {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			withOperationDetails(test.err, details)
+			act, ok := ErrorOperationDetails(test.err)
+			if ok != test.details {
+				t.Fatalf("unexpected details presence: %t; want %t", ok, test.details)
+			}
+			if !ok {
+				return
+			}
+			if act != details {
+				t.Errorf("unexpected details: %+v; want %+v", act, details)
+			}
+			const exp = "timeout=1s deadline=500ms elapsed=500ms"
+			if s := test.err.Error(); !strings.Contains(s, exp) {
+				t.Errorf("error message %q does not contain %q", s, exp)
+			}
+		})
+	}
+}
+
+func TestDialerGRPCDialOptions(t *testing.T) {
+	ln := newStubListener()
+	srv := grpc.NewServer()
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	var intercepted []string
+	d := dialer{
+		netDial: func(ctx context.Context, _ string) (net.Conn, error) {
+			select {
+			case c := <-ln.C:
+				return c, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+		grpcOpts: []grpc.DialOption{
+			grpc.WithUnaryInterceptor(func(
+				ctx context.Context, method string, req, reply interface{},
+				cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+			) error {
+				intercepted = append(intercepted, method)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}),
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := d.dialHostPort(ctx, "node", 2135)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.conn.Close()
+
+	var resp Ydb_Operations.GetOperationResponse
+	_ = conn.conn.Invoke(ctx, "/Test/Method", &resp, &resp)
+	if act, exp := intercepted, []string{"/Test/Method"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected intercepted methods: %v; want %v", act, exp)
+	}
+}
+
+func TestDriverUnauthenticatedRetryTimeout(t *testing.T) {
+	ln := newStubListener()
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(
+		func(interface{}, grpc.ServerStream) error {
+			return status.Error(codes.Unauthenticated, "unauthenticated")
+		},
+	))
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	var deadlines []time.Time
+	dial := dialer{
+		netDial: func(ctx context.Context, _ string) (net.Conn, error) {
+			select {
+			case c := <-ln.C:
+				return c, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+		grpcOpts: []grpc.DialOption{
+			grpc.WithUnaryInterceptor(func(
+				ctx context.Context, method string, req, reply interface{},
+				cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+			) error {
+				deadline, _ := ctx.Deadline()
+				deadlines = append(deadlines, deadline)
+				// Make the first attempt take some time.
+				time.Sleep(10 * time.Millisecond)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}),
+		},
+	}
+	_, balancer := simpleBalancer()
+	c := &cluster{
+		dial: func(ctx context.Context, s string, p int) (*conn, error) {
+			return dial.dialHostPort(ctx, s, p)
+		},
+		balancer: balancer,
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c.Insert(ctx, Endpoint{Addr: "node", Port: 2135})
+
+	d := &driver{
+		cluster:        c,
+		meta:           &meta{credentials: new(invalidatingCredentials)},
+		clock:          timeutil.ClockOrDefault(nil),
+		requestTimeout: 500 * time.Millisecond,
+	}
+	var (
+		req Ydb_Operations.GetOperationRequest
+		res Ydb_Operations.GetOperationResponse
+	)
+	err := d.doCall(context.Background(), internal.Wrap("/Test/Method", &req, &res))
+	if !isUnauthenticated(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(deadlines); n != 2 {
+		t.Fatalf("unexpected number of attempts: %d; want 2", n)
+	}
+	if !deadlines[1].Equal(deadlines[0]) {
+		t.Errorf(
+			"unexpected retry deadline: %s after the first one; want the same",
+			deadlines[1].Sub(deadlines[0]),
+		)
+	}
+}
@@ -0,0 +1,145 @@
+package ydb
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yandex-cloud/ydb-go-sdk/auth/iam"
+	"github.com/yandex-cloud/ydb-go-sdk/auth/metadata"
+)
+
+// WithConnectionString returns Option which sets endpoint, database, TLS and
+// authentication parameters from the connection string such as:
+//
+//     grpcs://ydb.serverless.yandexcloud.net:2135/?database=/ru-central1/b1g/etn&auth=metadata
+//
+// The scheme must be either "grpc" or "grpcs"; the latter enables TLS. If
+// port is omitted then it is inferred from the scheme (see DefaultGRPCPort
+// and DefaultGRPCSPort).
+//
+// The following query parameters are supported:
+//
+//     database              database name;
+//     auth                  authentication mode: "anonymous", "token",
+//                           "metadata" or "service_account_key_file";
+//     token                 static token used by "token" mode;
+//     token_file            path to the file with token used by "token" mode;
+//     key_file              path to the service account authorized key file
+//                           used by "service_account_key_file" mode;
+//     metadata_addr         address of metadata service used by "metadata"
+//                           mode;
+//     ca_file               path to the PEM file with root certificates;
+//     insecure_skip_verify  if true, server certificate is not verified.
+//
+// Note that the token passed right within the connection string may leak via
+// logs or process listings; consider using token_file instead.
+func WithConnectionString(dsn string) Option {
+	return func(o *options) error {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return fmt.Errorf("ydb: malformed connection string: %v", err)
+		}
+		switch u.Scheme {
+		case "grpc", "grpcs":
+		default:
+			return fmt.Errorf("ydb: unsupported connection string scheme: %q", u.Scheme)
+		}
+		if p := strings.Trim(u.Path, "/"); p != "" {
+			return fmt.Errorf("ydb: unexpected connection string path: %q", u.Path)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("ydb: empty host in connection string")
+		}
+		o.endpoint = u.Scheme + "://" + u.Host
+
+		q := u.Query()
+		for key := range q {
+			if !dsnParams[key] {
+				return fmt.Errorf("ydb: unknown connection string parameter: %q", key)
+			}
+		}
+		if s := q.Get("database"); s != "" {
+			o.config.Database = s
+		}
+		if err := dsnCredentials(o, q); err != nil {
+			return err
+		}
+		if name := q.Get("ca_file"); name != "" {
+			if err := o.setRootCertificatesFile(name); err != nil {
+				return fmt.Errorf("ydb: connection string ca_file: %v", err)
+			}
+		}
+		if s := q.Get("insecure_skip_verify"); s != "" {
+			skip, err := strconv.ParseBool(s)
+			if err != nil {
+				return fmt.Errorf("ydb: connection string insecure_skip_verify: %v", err)
+			}
+			if o.dialer.TLSConfig != nil {
+				o.dialer.TLSConfig = o.dialer.TLSConfig.Clone()
+			} else {
+				o.dialer.TLSConfig = new(tls.Config)
+			}
+			o.dialer.TLSConfig.InsecureSkipVerify = skip
+		}
+		return nil
+	}
+}
+
+var dsnParams = map[string]bool{
+	"database":             true,
+	"auth":                 true,
+	"token":                true,
+	"token_file":           true,
+	"key_file":             true,
+	"metadata_addr":        true,
+	"ca_file":              true,
+	"insecure_skip_verify": true,
+}
+
+func dsnCredentials(o *options, q url.Values) error {
+	switch mode := q.Get("auth"); mode {
+	case "":
+		if q.Get("token") != "" || q.Get("token_file") != "" || q.Get("key_file") != "" {
+			return fmt.Errorf("ydb: connection string auth mode is not set")
+		}
+
+	case "anonymous":
+		o.config.Credentials = nil
+
+	case "token":
+		token := q.Get("token")
+		if name := q.Get("token_file"); name != "" {
+			p, err := ioutil.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("ydb: connection string token_file: %v", err)
+			}
+			token = strings.TrimSpace(string(p))
+		}
+		if token == "" {
+			return fmt.Errorf("ydb: connection string token is empty")
+		}
+		o.config.Credentials = AuthTokenCredentials{
+			AuthToken: token,
+		}
+
+	case "metadata":
+		o.config.Credentials = &metadata.Client{
+			Addr: q.Get("metadata_addr"),
+		}
+
+	case "service_account_key_file":
+		c, err := iam.NewClientFromKeyFile(q.Get("key_file"))
+		if err != nil {
+			return fmt.Errorf("ydb: connection string key_file: %v", err)
+		}
+		o.config.Credentials = c
+
+	default:
+		return fmt.Errorf("ydb: unknown connection string auth mode: %q", mode)
+	}
+	return nil
+}
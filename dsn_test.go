@@ -0,0 +1,96 @@
+package ydb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/auth/metadata"
+)
+
+func TestWithConnectionString(t *testing.T) {
+	f, err := ioutil.TempFile("", "ydb-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("secret\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	for _, test := range []struct {
+		dsn      string
+		endpoint string
+		database string
+		creds    Credentials
+		skip     bool
+	}{
+		{
+			dsn:      "grpcs://ydb.serverless.yandexcloud.net:2135/?database=/ru-central1/b1g/etn",
+			endpoint: "grpcs://ydb.serverless.yandexcloud.net:2135",
+			database: "/ru-central1/b1g/etn",
+		},
+		{
+			dsn:      "grpc://localhost?database=/local&auth=token&token_file=" + f.Name(),
+			endpoint: "grpc://localhost",
+			database: "/local",
+			creds:    AuthTokenCredentials{AuthToken: "secret"},
+		},
+		{
+			dsn:      "grpcs://localhost:2136?auth=metadata&metadata_addr=localhost:1&insecure_skip_verify=1",
+			endpoint: "grpcs://localhost:2136",
+			creds:    &metadata.Client{Addr: "localhost:1"},
+			skip:     true,
+		},
+	} {
+		t.Run(test.dsn, func(t *testing.T) {
+			var o options
+			if err := WithConnectionString(test.dsn)(&o); err != nil {
+				t.Fatal(err)
+			}
+			if act, exp := o.endpoint, test.endpoint; act != exp {
+				t.Errorf("unexpected endpoint: %q; want %q", act, exp)
+			}
+			if act, exp := o.config.Database, test.database; act != exp {
+				t.Errorf("unexpected database: %q; want %q", act, exp)
+			}
+			switch exp := test.creds.(type) {
+			case *metadata.Client:
+				act, ok := o.config.Credentials.(*metadata.Client)
+				if !ok || act.Addr != exp.Addr {
+					t.Errorf("unexpected credentials: %#v", o.config.Credentials)
+				}
+			default:
+				if act := o.config.Credentials; act != exp {
+					t.Errorf("unexpected credentials: %#v; want %#v", act, exp)
+				}
+			}
+			skip := o.dialer.TLSConfig != nil && o.dialer.TLSConfig.InsecureSkipVerify
+			if skip != test.skip {
+				t.Errorf("unexpected insecure skip verify: %t", skip)
+			}
+		})
+	}
+}
+
+func TestWithConnectionStringErrors(t *testing.T) {
+	for _, dsn := range []string{
+		"http://localhost",
+		"grpc://localhost/database",
+		"grpc://?database=/local",
+		"grpc://localhost?foo=bar",
+		"grpc://localhost?auth=kerberos",
+		"grpc://localhost?auth=token",
+		"grpc://localhost?token=secret",
+		"grpc://localhost?auth=service_account_key_file",
+		"grpc://localhost?insecure_skip_verify=maybe",
+	} {
+		t.Run(dsn, func(t *testing.T) {
+			var o options
+			if err := WithConnectionString(dsn)(&o); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ydb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// EndpointsCache is the interface of the storage of the endpoints list
+// received by the driver during discovery.
+//
+// Driver stores the endpoints after each successful discovery and loads them
+// when the initial discovery fails. That is, driver is able to start when the
+// discovery endpoint is unavailable but some of previously known endpoints
+// are alive.
+type EndpointsCache interface {
+	// LoadEndpoints returns previously stored endpoints list.
+	LoadEndpoints(context.Context) ([]Endpoint, error)
+
+	// StoreEndpoints stores endpoints list. Errors returned by
+	// StoreEndpoints() are ignored by the driver.
+	StoreEndpoints(context.Context, []Endpoint) error
+}
+
+// StaticEndpoints is an EndpointsCache which always loads its own endpoints
+// list and never stores anything. It may be used to preload the list of
+// endpoints known in advance.
+type StaticEndpoints []Endpoint
+
+// LoadEndpoints implements EndpointsCache.
+func (s StaticEndpoints) LoadEndpoints(context.Context) ([]Endpoint, error) {
+	es := make([]Endpoint, len(s))
+	copy(es, s)
+	return es, nil
+}
+
+// StoreEndpoints implements EndpointsCache.
+func (s StaticEndpoints) StoreEndpoints(context.Context, []Endpoint) error {
+	return nil
+}
+
+// FileEndpointsCache is an EndpointsCache which persists endpoints list in
+// the file in JSON format.
+type FileEndpointsCache struct {
+	// Path is a path to the file.
+	Path string
+}
+
+// LoadEndpoints implements EndpointsCache.
+func (f FileEndpointsCache) LoadEndpoints(context.Context) ([]Endpoint, error) {
+	p, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	var es []Endpoint
+	if err := json.Unmarshal(p, &es); err != nil {
+		return nil, err
+	}
+	return es, nil
+}
+
+// StoreEndpoints implements EndpointsCache.
+// It writes the list to the temporary file first and then renames it, such
+// that concurrently running processes never read partially written file.
+func (f FileEndpointsCache) StoreEndpoints(_ context.Context, es []Endpoint) error {
+	p, err := json.Marshal(es)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(p); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,103 @@
+package ydb
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileEndpointsCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ydb-endpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	c := FileEndpointsCache{
+		Path: filepath.Join(dir, "endpoints.json"),
+	}
+	if _, err := c.LoadEndpoints(ctx); err == nil {
+		t.Fatalf("no error for missing file")
+	}
+	exp := []Endpoint{
+		{Addr: "foo", Port: 2135, Local: true},
+		{Addr: "bar", Port: 2135, LoadFactor: 0.5, SSL: true},
+	}
+	if err := c.StoreEndpoints(ctx, exp); err != nil {
+		t.Fatal(err)
+	}
+	act, err := c.LoadEndpoints(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Fatalf("unexpected endpoints: %+v; want %+v", act, exp)
+	}
+	fs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fs); n != 1 {
+		t.Fatalf("unexpected number of files: %d", n)
+	}
+}
+
+func TestDialerCachedEndpoints(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		discoveryErr = errors.New("discovery error")
+		endpoints    = []Endpoint{
+			{Addr: "foo", Port: 2135},
+		}
+	)
+	for _, test := range []struct {
+		name  string
+		cache EndpointsCache
+		exp   []Endpoint
+	}{
+		{
+			name: "no cache",
+		},
+		{
+			name:  "empty cache",
+			cache: StaticEndpoints(nil),
+		},
+		{
+			name: "broken cache",
+			cache: FileEndpointsCache{
+				Path: filepath.Join(os.TempDir(), "ydb-endpoints-missing.json"),
+			},
+		},
+		{
+			name:  "static endpoints",
+			cache: StaticEndpoints(endpoints),
+			exp:   endpoints,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			d := dialer{
+				config: DriverConfig{
+					EndpointsCache: test.cache,
+				},
+			}
+			act, err := d.cachedEndpoints(ctx, discoveryErr)
+			if test.exp == nil {
+				if err != discoveryErr {
+					t.Fatalf("unexpected error: %v; want %v", err, discoveryErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(act, test.exp) {
+				t.Fatalf("unexpected endpoints: %+v; want %+v", act, test.exp)
+			}
+		})
+	}
+}
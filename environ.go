@@ -0,0 +1,166 @@
+package ydb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/yandex-cloud/ydb-go-sdk/auth/iam"
+	"github.com/yandex-cloud/ydb-go-sdk/auth/metadata"
+)
+
+// Environment variables used by WithEnvironConfig().
+const (
+	// EnvEndpoint contains the address to dial in the Dialer.Dial() format.
+	EnvEndpoint = "YDB_ENDPOINT"
+
+	// EnvDatabase contains the database name.
+	EnvDatabase = "YDB_DATABASE"
+
+	// EnvAccessTokenCredentials contains the access token used for
+	// authentication as is.
+	EnvAccessTokenCredentials = "YDB_ACCESS_TOKEN_CREDENTIALS"
+
+	// EnvAnonymousCredentials, if true, disables authentication.
+	EnvAnonymousCredentials = "YDB_ANONYMOUS_CREDENTIALS"
+
+	// EnvMetadataCredentials, if true, enables authentication by tokens
+	// issued by the metadata service of the virtual machine.
+	EnvMetadataCredentials = "YDB_METADATA_CREDENTIALS"
+
+	// EnvServiceAccountKeyFileCredentials contains path to the service
+	// account authorized key file used to issue iam tokens.
+	EnvServiceAccountKeyFileCredentials = "YDB_SERVICE_ACCOUNT_KEY_FILE_CREDENTIALS"
+
+	// EnvSSLRootCertificatesFile contains path to the PEM file with root
+	// certificates used to verify server certificates.
+	EnvSSLRootCertificatesFile = "YDB_SSL_ROOT_CERTIFICATES_FILE"
+)
+
+// WithEnvironConfig returns Option which overrides parameters set by previous
+// options with ones from the environment variables (see Env* constants).
+// Unset variables do not change parameters.
+//
+// Credentials variables are checked in the following order:
+// EnvAccessTokenCredentials, EnvServiceAccountKeyFileCredentials,
+// EnvMetadataCredentials and EnvAnonymousCredentials; the first one set takes
+// effect.
+//
+// WithEnvironConfig is usually passed after options configured in the code:
+//
+//     ydb.New(ctx,
+//         ydb.WithDialer(&dialer),
+//         ydb.WithEnvironConfig(),
+//     )
+func WithEnvironConfig() Option {
+	return func(o *options) error {
+		if s, ok := os.LookupEnv(EnvEndpoint); ok {
+			o.endpoint = s
+		}
+		if s, ok := os.LookupEnv(EnvDatabase); ok {
+			o.config.Database = s
+		}
+		if err := environCredentials(o); err != nil {
+			return err
+		}
+		if name, ok := os.LookupEnv(EnvSSLRootCertificatesFile); ok {
+			if err := o.setRootCertificatesFile(name); err != nil {
+				return fmt.Errorf("ydb: %s: %v", EnvSSLRootCertificatesFile, err)
+			}
+		}
+		return nil
+	}
+}
+
+// setRootCertificatesFile makes dialer to use TLS with root certificates from
+// the PEM file with given name.
+func (o *options) setRootCertificatesFile(name string) error {
+	p, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(p) {
+		return fmt.Errorf("no certificates in %q", name)
+	}
+	if o.dialer.TLSConfig != nil {
+		o.dialer.TLSConfig = o.dialer.TLSConfig.Clone()
+	} else {
+		o.dialer.TLSConfig = new(tls.Config)
+	}
+	o.dialer.TLSConfig.RootCAs = pool
+	return nil
+}
+
+func environCredentials(o *options) error {
+	if token, ok := os.LookupEnv(EnvAccessTokenCredentials); ok {
+		o.config.Credentials = AuthTokenCredentials{
+			AuthToken: token,
+		}
+		return nil
+	}
+	if name, ok := os.LookupEnv(EnvServiceAccountKeyFileCredentials); ok {
+		c, err := iam.NewClientFromKeyFile(name)
+		if err != nil {
+			return fmt.Errorf("ydb: %s: %v", EnvServiceAccountKeyFileCredentials, err)
+		}
+		o.config.Credentials = c
+		return nil
+	}
+	if on, err := environBool(EnvMetadataCredentials); err != nil {
+		return err
+	} else if on {
+		o.config.Credentials = new(metadata.Client)
+		return nil
+	}
+	if on, err := environBool(EnvAnonymousCredentials); err != nil {
+		return err
+	} else if on {
+		o.config.Credentials = nil
+	}
+	return nil
+}
+
+// DefaultCredentialsChain returns Credentials which try sources of the token
+// in order: the access token from EnvAccessTokenCredentials, then the iam
+// token issued for the service account key file from
+// EnvServiceAccountKeyFileCredentials, and then the token issued by the
+// metadata service of the virtual machine.
+//
+// Sources which environment variables are not set are skipped. The metadata
+// service is always tried last. The source which returned the token is
+// reported by the DriverTrace's GetCredentialsDone callback.
+//
+// It returns error if the service account key file can not be read.
+func DefaultCredentialsChain() (Credentials, error) {
+	var cs []Credentials
+	if token, ok := os.LookupEnv(EnvAccessTokenCredentials); ok {
+		cs = append(cs, AuthTokenCredentials{
+			AuthToken: token,
+		})
+	}
+	if name, ok := os.LookupEnv(EnvServiceAccountKeyFileCredentials); ok {
+		c, err := iam.NewClientFromKeyFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("ydb: %s: %v", EnvServiceAccountKeyFileCredentials, err)
+		}
+		cs = append(cs, c)
+	}
+	cs = append(cs, new(metadata.Client))
+	return MultiCredentials(cs...), nil
+}
+
+func environBool(name string) (bool, error) {
+	s, ok := os.LookupEnv(name)
+	if !ok || s == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("ydb: %s: %v", name, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,169 @@
+package ydb
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/auth/metadata"
+)
+
+func setenv(t *testing.T, env map[string]string) (cleanup func()) {
+	prev := make(map[string]*string, len(env))
+	for k, v := range env {
+		if s, ok := os.LookupEnv(k); ok {
+			prev[k] = &s
+		} else {
+			prev[k] = nil
+		}
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		for k, v := range prev {
+			if v != nil {
+				_ = os.Setenv(k, *v)
+			} else {
+				_ = os.Unsetenv(k)
+			}
+		}
+	}
+}
+
+func TestWithEnvironConfig(t *testing.T) {
+	defer setenv(t, map[string]string{
+		EnvEndpoint:            "grpcs://ydb.example.net",
+		EnvDatabase:            "/env",
+		EnvMetadataCredentials: "1",
+	})()
+
+	var o options
+	for _, opt := range []Option{
+		WithDialer(&Dialer{
+			DriverConfig: &DriverConfig{
+				Database:       "/code",
+				Credentials:    AuthTokenCredentials{AuthToken: "token"},
+				RequestTimeout: 1,
+			},
+		}),
+		WithEndpoint("localhost"),
+		WithEnvironConfig(),
+	} {
+		if err := opt(&o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if act, exp := o.endpoint, "grpcs://ydb.example.net"; act != exp {
+		t.Errorf("unexpected endpoint: %q; want %q", act, exp)
+	}
+	if act, exp := o.config.Database, "/env"; act != exp {
+		t.Errorf("unexpected database: %q; want %q", act, exp)
+	}
+	if _, ok := o.config.Credentials.(*metadata.Client); !ok {
+		t.Errorf("unexpected credentials: %#v", o.config.Credentials)
+	}
+	if act, exp := o.config.RequestTimeout, time.Duration(1); act != exp {
+		t.Errorf("unexpected request timeout: %v; want %v", act, exp)
+	}
+}
+
+func TestWithEnvironConfigAccessToken(t *testing.T) {
+	defer setenv(t, map[string]string{
+		EnvAccessTokenCredentials: "token",
+		EnvMetadataCredentials:    "1",
+	})()
+
+	var o options
+	if err := WithEnvironConfig()(&o); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := o.config.Credentials, (AuthTokenCredentials{AuthToken: "token"}); act != exp {
+		t.Errorf("unexpected credentials: %#v; want %#v", act, exp)
+	}
+}
+
+func TestNewNoEndpoint(t *testing.T) {
+	if _, err := New(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestWithEnvironConfigAnonymous(t *testing.T) {
+	defer setenv(t, map[string]string{
+		EnvAnonymousCredentials: "true",
+	})()
+	o := options{
+		config: DriverConfig{
+			Credentials: AuthTokenCredentials{AuthToken: "token"},
+		},
+	}
+	if err := WithEnvironConfig()(&o); err != nil {
+		t.Fatal(err)
+	}
+	if o.config.Credentials != nil {
+		t.Errorf("unexpected credentials: %#v", o.config.Credentials)
+	}
+}
+
+func TestWithEnvironConfigErrors(t *testing.T) {
+	f, err := ioutil.TempFile("", "ydb-environ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	_ = f.Close()
+
+	for name, env := range map[string]map[string]string{
+		"malformed bool": {EnvMetadataCredentials: "yes please"},
+		"no key file":    {EnvServiceAccountKeyFileCredentials: f.Name() + ".missing"},
+		"malformed key":  {EnvServiceAccountKeyFileCredentials: f.Name()},
+		"no certs":       {EnvSSLRootCertificatesFile: f.Name()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer setenv(t, env)()
+			o := options{
+				dialer: Dialer{
+					TLSConfig: new(tls.Config),
+				},
+			}
+			if err := WithEnvironConfig()(&o); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestDefaultCredentialsChain(t *testing.T) {
+	defer setenv(t, map[string]string{
+		EnvAccessTokenCredentials: "token",
+	})()
+
+	c, err := DefaultCredentialsChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := c.(*multiCredentials)
+	if !ok {
+		t.Fatalf("unexpected credentials: %#v", c)
+	}
+	if n := len(m.cs); n != 2 {
+		t.Fatalf("unexpected number of credentials: %d", n)
+	}
+	if act, exp := m.cs[0], (AuthTokenCredentials{AuthToken: "token"}); act != exp {
+		t.Errorf("unexpected first credentials: %#v; want %#v", act, exp)
+	}
+	if _, ok := m.cs[1].(*metadata.Client); !ok {
+		t.Errorf("unexpected last credentials: %#v", m.cs[1])
+	}
+	token, err := c.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token" {
+		t.Errorf("unexpected token: %q", token)
+	}
+}
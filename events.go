@@ -0,0 +1,181 @@
+package ydb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// EventType describes the kind of the driver event.
+type EventType uint
+
+const (
+	EventUnknown EventType = iota
+
+	// EventEndpointAdded is emitted when new endpoint is found by discovery.
+	EventEndpointAdded
+
+	// EventEndpointRemoved is emitted when endpoint is not found by
+	// discovery anymore.
+	EventEndpointRemoved
+
+	// EventEndpointPessimized is emitted when connection to the endpoint is
+	// considered broken and the endpoint is excluded from balancing until
+	// reconnection.
+	EventEndpointPessimized
+
+	// EventEndpointRestored is emitted when connection to the previously
+	// pessimized endpoint is established again.
+	EventEndpointRestored
+
+	// EventDiscoveryFailed is emitted when background discovery round fails.
+	EventDiscoveryFailed
+
+	// EventCredentialsRefreshed is emitted when credentials return a token
+	// which differs from the previously used one.
+	EventCredentialsRefreshed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventEndpointAdded:
+		return "endpoint added"
+	case EventEndpointRemoved:
+		return "endpoint removed"
+	case EventEndpointPessimized:
+		return "endpoint pessimized"
+	case EventEndpointRestored:
+		return "endpoint restored"
+	case EventDiscoveryFailed:
+		return "discovery failed"
+	case EventCredentialsRefreshed:
+		return "credentials refreshed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes topology or state change of the driver.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Endpoint is set for EventEndpointAdded and EventEndpointRemoved
+	// events.
+	Endpoint Endpoint
+
+	// Address is the "host:port" address of the endpoint; it is set for all
+	// endpoint events.
+	Address string
+
+	// Error is set for EventDiscoveryFailed events.
+	Error error
+}
+
+// EventSubscriber is an optional interface of Driver which is implemented by
+// drivers created by Dialer. Other drivers (such as ones created by
+// NewFailoverDriver()) do not publish events, thus type assertion of them to
+// EventSubscriber fails.
+type EventSubscriber interface {
+	// Subscribe makes driver to send its events to ch until returned
+	// unsubscribe function is called.
+	//
+	// Events are sent without blocking: if ch is not ready to receive, the
+	// event is dropped. Thus ch should be buffered and drained in time. Note
+	// that ch is never closed by the driver.
+	Subscribe(ch chan<- Event) (unsubscribe func())
+}
+
+// Subscribe implements EventSubscriber.
+func (d *driver) Subscribe(ch chan<- Event) (unsubscribe func()) {
+	return d.events.subscribe(ch)
+}
+
+// eventBus dispatches driver events to the subscribed channels.
+type eventBus struct {
+	clock timeutil.Clock
+
+	mu   sync.RWMutex
+	id   uint64
+	subs map[uint64]chan<- Event
+}
+
+func (b *eventBus) subscribe(ch chan<- Event) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[uint64]chan<- Event)
+	}
+	b.id++
+	id := b.id
+	b.subs[id] = ch
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+		})
+	}
+}
+
+func (b *eventBus) publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.subs) == 0 {
+		return
+	}
+	e.Time = timeutil.ClockOrDefault(b.clock).Now()
+	for _, ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func (b *eventBus) publishEndpoints(t EventType, es []Endpoint) {
+	for _, e := range es {
+		b.publish(Event{
+			Type:     t,
+			Endpoint: e,
+			Address:  connAddr{e.Addr, e.Port}.String(),
+		})
+	}
+}
+
+// trace returns DriverTrace which emits events derived from the driver's
+// trace callbacks.
+func (b *eventBus) trace() DriverTrace {
+	return DriverTrace{
+		TrackConnStart: func(info TrackConnStartInfo) {
+			b.publish(Event{
+				Type:    EventEndpointPessimized,
+				Address: info.Address,
+			})
+		},
+		TrackConnDone: func(info TrackConnDoneInfo) {
+			b.publish(Event{
+				Type:    EventEndpointRestored,
+				Address: info.Address,
+			})
+		},
+		DiscoveryDone: func(info DiscoveryDoneInfo) {
+			if info.Error != nil {
+				b.publish(Event{
+					Type:  EventDiscoveryFailed,
+					Error: info.Error,
+				})
+			}
+		},
+		DiscoveryDiff: func(info DiscoveryDiffInfo) {
+			b.publishEndpoints(EventEndpointAdded, info.Added)
+			b.publishEndpoints(EventEndpointRemoved, info.Removed)
+		},
+	}
+}
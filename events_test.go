@@ -0,0 +1,113 @@
+package ydb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestEventBus(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(10, 0))
+	bus := &eventBus{
+		clock: clock,
+	}
+	var (
+		ch1 = make(chan Event, 10)
+		ch2 = make(chan Event, 1)
+	)
+	unsubscribe := bus.subscribe(ch1)
+	bus.subscribe(ch2)
+
+	trace := bus.trace()
+	e := Endpoint{Addr: "foo", Port: 42}
+	trace.DiscoveryDiff(DiscoveryDiffInfo{
+		Added:   []Endpoint{e},
+		Removed: []Endpoint{e},
+		Updated: []Endpoint{e},
+	})
+	trace.TrackConnStart(TrackConnStartInfo{Address: "foo:42"})
+	trace.TrackConnDone(TrackConnDoneInfo{Address: "foo:42"})
+	trace.DiscoveryDone(DiscoveryDoneInfo{})
+	errTest := errors.New("test")
+	trace.DiscoveryDone(DiscoveryDoneInfo{Error: errTest})
+
+	exp := []Event{
+		{Type: EventEndpointAdded, Endpoint: e, Address: "foo:42"},
+		{Type: EventEndpointRemoved, Endpoint: e, Address: "foo:42"},
+		{Type: EventEndpointPessimized, Address: "foo:42"},
+		{Type: EventEndpointRestored, Address: "foo:42"},
+		{Type: EventDiscoveryFailed, Error: errTest},
+	}
+	for i, exp := range exp {
+		exp.Time = clock.Now()
+		select {
+		case act := <-ch1:
+			if act != exp {
+				t.Errorf("#%d unexpected event: %+v; want %+v", i, act, exp)
+			}
+		default:
+			t.Fatalf("#%d no event", i)
+		}
+	}
+	// ch2 is full after the first event; others must be dropped.
+	if n := len(ch2); n != 1 {
+		t.Errorf("unexpected number of events in full channel: %d", n)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	bus.publish(Event{Type: EventDiscoveryFailed})
+	if n := len(ch1); n != 0 {
+		t.Errorf("unexpected events after unsubscribe: %d", n)
+	}
+}
+
+func TestMetaCredentialsRefreshedEvent(t *testing.T) {
+	var (
+		bus   = new(eventBus)
+		ch    = make(chan Event, 10)
+		token = "foo"
+	)
+	bus.subscribe(ch)
+	m := &meta{
+		events: bus,
+		credentials: CredentialsFunc(func(context.Context) (string, error) {
+			return token, nil
+		}),
+	}
+	for _, s := range []string{"foo", "foo", "bar"} {
+		token = s
+		if _, err := m.md(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if act, exp := len(ch), 2; act != exp {
+		t.Fatalf("unexpected number of events: %d; want %d", act, exp)
+	}
+	if e := <-ch; e.Type != EventCredentialsRefreshed {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
+
+func TestDriverEventSubscriber(t *testing.T) {
+	bus := new(eventBus)
+	s, ok := Driver(&driver{events: bus}).(EventSubscriber)
+	if !ok {
+		t.Fatalf("driver is not an event subscriber")
+	}
+	ch := make(chan Event, 1)
+	unsubscribe := s.Subscribe(ch)
+	defer unsubscribe()
+
+	bus.publish(Event{Type: EventCredentialsRefreshed})
+	if e := <-ch; e.Type != EventCredentialsRefreshed {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	if _, ok := Driver(new(stubDriver)).(EventSubscriber); ok {
+		t.Fatalf("unexpected event subscriber")
+	}
+}
@@ -0,0 +1,178 @@
+package ydb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+var (
+	// DefaultFailoverMaxErrorRate contains default ratio of failed operations
+	// to all operations of the primary cluster after which it is treated as
+	// unhealthy.
+	DefaultFailoverMaxErrorRate = 0.5
+
+	// DefaultFailoverCheckInterval contains default duration between primary
+	// cluster health evaluations.
+	DefaultFailoverCheckInterval = time.Second
+)
+
+// FailoverPolicy contains options of switching between primary and secondary
+// drivers.
+type FailoverPolicy struct {
+	// MinOnlineEndpoints is a minimum number of online endpoints within
+	// primary cluster to treat it as healthy.
+	// If MinOnlineEndpoints is zero then at least one online endpoint is
+	// required.
+	MinOnlineEndpoints int
+
+	// MaxErrorRate is a maximum ratio of failed operations to all operations
+	// per minute within primary cluster to treat it as healthy.
+	// If MaxErrorRate is zero then the DefaultFailoverMaxErrorRate is used.
+	MaxErrorRate float64
+
+	// CheckInterval is a minimum duration between primary cluster health
+	// evaluations. That is, health is evaluated lazily during calls, but not
+	// more often than once per CheckInterval.
+	// If CheckInterval is zero then the DefaultFailoverCheckInterval is used.
+	CheckInterval time.Duration
+
+	// OnSwitch is an optional callback called when failover driver changes
+	// the driver used for requests. Primary reports whether requests are
+	// routed to the primary driver after the switch.
+	// OnSwitch calls are serialized with the switches; thus OnSwitch must not
+	// make calls through the failover driver.
+	OnSwitch func(primary bool)
+}
+
+func (p FailoverPolicy) withDefaults() FailoverPolicy {
+	if p.MinOnlineEndpoints <= 0 {
+		p.MinOnlineEndpoints = 1
+	}
+	if p.MaxErrorRate == 0 {
+		p.MaxErrorRate = DefaultFailoverMaxErrorRate
+	}
+	if p.CheckInterval == 0 {
+		p.CheckInterval = DefaultFailoverCheckInterval
+	}
+	return p
+}
+
+// NewFailoverDriver returns Driver which routes requests to the primary driver
+// while its cluster is healthy and to the secondary driver otherwise.
+//
+// Primary cluster health is evaluated by its connections stats (see
+// ReadConnStats()): the number of online endpoints and the rate of failed
+// operations. When primary becomes healthy again, requests are routed back to
+// it.
+//
+// Note that if primary is not a driver created by Dial() it is always treated
+// as healthy. Health evaluations are timed by the primary driver's
+// DriverConfig.Clock.
+//
+// Close() of returned driver closes both primary and secondary drivers.
+func NewFailoverDriver(primary, secondary Driver, policy FailoverPolicy) Driver {
+	var clock timeutil.Clock
+	if d, ok := primary.(*driver); ok {
+		clock = d.clock
+	}
+	return &failoverDriver{
+		primary:   primary,
+		secondary: secondary,
+		policy:    policy.withDefaults(),
+		clock:     timeutil.ClockOrDefault(clock),
+		readStats: ReadConnStats,
+		healthy:   true,
+	}
+}
+
+type failoverDriver struct {
+	primary   Driver
+	secondary Driver
+	policy    FailoverPolicy
+	clock     timeutil.Clock
+
+	// readStats is used to obtain primary cluster connections stats.
+	readStats func(Driver, func(Endpoint, ConnStats))
+
+	mu      sync.Mutex
+	checked time.Time
+	healthy bool
+}
+
+func (f *failoverDriver) Call(ctx context.Context, op internal.Operation) error {
+	return f.next().Call(ctx, op)
+}
+
+func (f *failoverDriver) StreamRead(ctx context.Context, op internal.StreamOperation) error {
+	return f.next().StreamRead(ctx, op)
+}
+
+func (f *failoverDriver) Close() error {
+	err1 := f.primary.Close()
+	err2 := f.secondary.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
+
+func (f *failoverDriver) next() Driver {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.clock.Now()
+	if !f.checked.IsZero() && now.Sub(f.checked) < f.policy.CheckInterval {
+		return f.pick(f.healthy)
+	}
+	f.checked = now
+
+	// Health is checked and switched under the lock such that concurrent
+	// callers do not report the same switch twice or out of order.
+	if healthy := f.check(); healthy != f.healthy {
+		f.healthy = healthy
+		if fn := f.policy.OnSwitch; fn != nil {
+			fn(healthy)
+		}
+	}
+	return f.pick(f.healthy)
+}
+
+func (f *failoverDriver) pick(healthy bool) Driver {
+	if healthy {
+		return f.primary
+	}
+	return f.secondary
+}
+
+// check reports whether primary driver is healthy.
+func (f *failoverDriver) check() bool {
+	var (
+		known  bool
+		online int
+		ops    float64
+		errs   float64
+	)
+	f.readStats(f.primary, func(_ Endpoint, s ConnStats) {
+		known = true
+		if s.State == ConnOnline {
+			online++
+		}
+		ops += s.OpPerMinute
+		errs += s.ErrPerMinute
+	})
+	if _, dialed := f.primary.(*driver); !known && !dialed {
+		// No stats available for drivers not created by Dial().
+		return true
+	}
+	if online < f.policy.MinOnlineEndpoints {
+		return false
+	}
+	if ops > 0 && errs/ops > f.policy.MaxErrorRate {
+		return false
+	}
+	return true
+}
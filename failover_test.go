@@ -0,0 +1,100 @@
+package ydb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+type stubDriver struct {
+	calls int
+}
+
+func (s *stubDriver) Call(context.Context, internal.Operation) error {
+	s.calls++
+	return nil
+}
+func (s *stubDriver) StreamRead(context.Context, internal.StreamOperation) error {
+	s.calls++
+	return nil
+}
+func (s *stubDriver) Close() error {
+	return nil
+}
+
+func TestFailoverDriver(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(0, 0))
+
+	var (
+		primary   stubDriver
+		secondary stubDriver
+		stats     []ConnStats
+		switches  []bool
+	)
+	d := NewFailoverDriver(&primary, &secondary, FailoverPolicy{
+		CheckInterval: time.Second,
+		OnSwitch: func(p bool) {
+			switches = append(switches, p)
+		},
+	}).(*failoverDriver)
+	d.clock = clock
+	d.readStats = func(_ Driver, it func(Endpoint, ConnStats)) {
+		for _, s := range stats {
+			it(Endpoint{}, s)
+		}
+	}
+
+	call := func() {
+		_ = d.Call(context.Background(), internal.Operation{})
+	}
+	assertCalls := func(p, s int) {
+		t.Helper()
+		if primary.calls != p || secondary.calls != s {
+			t.Fatalf(
+				"unexpected calls: primary=%d secondary=%d; want %d and %d",
+				primary.calls, secondary.calls, p, s,
+			)
+		}
+	}
+
+	stats = []ConnStats{
+		{State: ConnOnline, OpPerMinute: 10, ErrPerMinute: 1},
+	}
+	call()
+	assertCalls(1, 0)
+
+	// Primary becomes unhealthy, but health is not re-evaluated until check
+	// interval passes.
+	stats = []ConnStats{
+		{State: ConnOnline, OpPerMinute: 10, ErrPerMinute: 9},
+	}
+	call()
+	assertCalls(2, 0)
+
+	clock.Shift(time.Second)
+	call()
+	assertCalls(2, 1)
+
+	// No online endpoints left.
+	stats = []ConnStats{
+		{State: ConnOffline},
+	}
+	clock.Shift(time.Second)
+	call()
+	assertCalls(2, 2)
+
+	// Primary recovered.
+	stats = []ConnStats{
+		{State: ConnOnline},
+	}
+	clock.Shift(time.Second)
+	call()
+	assertCalls(3, 2)
+
+	if len(switches) != 2 || switches[0] || !switches[1] {
+		t.Fatalf("unexpected switches: %v", switches)
+	}
+}
@@ -5,10 +5,14 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
-	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Issue"
 )
 
@@ -41,7 +45,35 @@ func (it IssueIterator) Get(i int) (issue Issue, nested IssueIterator) {
 type TransportError struct {
 	Reason TransportErrorCode
 
-	message string
+	message       string
+	details       *OperationDetails
+	statusDetails []proto.Message
+}
+
+// Details returns typed messages attached by the server to the gRPC status,
+// such as *errdetails.RetryInfo or *errdetails.DebugInfo from the
+// google.golang.org/genproto/googleapis/rpc/errdetails package. Messages of
+// types which are not linked into the program are omitted.
+func (t *TransportError) Details() []proto.Message {
+	return t.statusDetails
+}
+
+// RetryDelay returns the delay suggested by the server to wait before
+// retrying the call via errdetails.RetryInfo status detail. It returns false
+// if there is no such suggestion.
+func (t *TransportError) RetryDelay() (time.Duration, bool) {
+	for _, m := range t.statusDetails {
+		r, ok := m.(*errdetails.RetryInfo)
+		if !ok || r.RetryDelay == nil {
+			continue
+		}
+		d, err := ptypes.Duration(r.RetryDelay)
+		if err != nil {
+			continue
+		}
+		return d, true
+	}
+	return 0, false
 }
 
 func (t *TransportError) Error() string {
@@ -49,9 +81,22 @@ func (t *TransportError) Error() string {
 	if t.message != "" {
 		s += ": " + t.message
 	}
+	if t.details != nil {
+		s += " (" + t.details.String() + ")"
+	}
 	return s
 }
 
+// statusDetails returns successfully decoded details of the gRPC status.
+func statusDetails(s *status.Status) (ms []proto.Message) {
+	for _, d := range s.Details() {
+		if m, ok := d.(proto.Message); ok {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
 // IsTransportError reports whether err is TransportError with given code as
 // the Reason.
 func IsTransportError(err error, code TransportErrorCode) bool {
@@ -66,7 +111,8 @@ func IsTransportError(err error, code TransportErrorCode) bool {
 type OpError struct {
 	Reason StatusCode
 
-	issues []*Ydb_Issue.IssueMessage
+	issues  []*Ydb_Issue.IssueMessage
+	details *OperationDetails
 }
 
 func (e *OpError) Issues() IssueIterator {
@@ -74,7 +120,7 @@ func (e *OpError) Issues() IssueIterator {
 }
 
 func (e *OpError) Error() string {
-	if len(e.issues) == 0 {
+	if len(e.issues) == 0 && e.details == nil {
 		return e.Reason.String()
 	}
 	var buf bytes.Buffer
@@ -84,9 +130,85 @@ func (e *OpError) Error() string {
 		buf.WriteByte(':')
 		dumpIssues(&buf, e.issues)
 	}
+	if e.details != nil {
+		buf.WriteString(" (")
+		buf.WriteString(e.details.String())
+		buf.WriteByte(')')
+	}
 	return buf.String()
 }
 
+// OperationDetails describes effective parameters of the operation which
+// failed due to timeout or cancelation. It helps to find out whether the
+// client or the server gave up first.
+type OperationDetails struct {
+	Method string
+
+	// Params contains operation parameters sent to the server.
+	Params OperationParams
+
+	// Deadline is the time left until the client context deadline at the
+	// moment when request was sent. It is meaningful only if HasDeadline is
+	// true.
+	Deadline    time.Duration
+	HasDeadline bool
+
+	// Elapsed is the time spent waiting for the response.
+	Elapsed time.Duration
+}
+
+func (d OperationDetails) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("method=")
+	buf.WriteString(d.Method)
+	if t := d.Params.Timeout; t > 0 {
+		buf.WriteString(" timeout=")
+		buf.WriteString(t.String())
+	}
+	if t := d.Params.CancelAfter; t > 0 {
+		buf.WriteString(" cancel_after=")
+		buf.WriteString(t.String())
+	}
+	if d.HasDeadline {
+		buf.WriteString(" deadline=")
+		buf.WriteString(d.Deadline.String())
+	}
+	buf.WriteString(" elapsed=")
+	buf.WriteString(d.Elapsed.String())
+	return buf.String()
+}
+
+// ErrorOperationDetails returns operation details of err if it is OpError or
+// TransportError caused by timeout or cancelation.
+func ErrorOperationDetails(err error) (d OperationDetails, ok bool) {
+	var x *OperationDetails
+	switch e := err.(type) {
+	case *OpError:
+		x = e.details
+	case *TransportError:
+		x = e.details
+	}
+	if x == nil {
+		return d, false
+	}
+	return *x, true
+}
+
+// withOperationDetails sets details of err if it is OpError or TransportError
+// caused by timeout or cancelation.
+func withOperationDetails(err error, d OperationDetails) {
+	switch e := err.(type) {
+	case *OpError:
+		if e.Reason == StatusTimeout || e.Reason == StatusCancelled {
+			e.details = &d
+		}
+	case *TransportError:
+		if e.Reason == TransportErrorDeadlineExceeded || e.Reason == TransportErrorCanceled {
+			e.details = &d
+		}
+	}
+}
+
 // IsOpError reports whether err is OpError with given code as the Reason.
 func IsOpError(err error, code StatusCode) bool {
 	op, ok := err.(*OpError)
@@ -126,79 +248,6 @@ func dumpIssues(buf *bytes.Buffer, ms []*Ydb_Issue.IssueMessage) {
 	}
 }
 
-// StatusCode reports unsuccessful operation status code.
-type StatusCode int32
-
-func (e StatusCode) String() string {
-	return Ydb.StatusIds_StatusCode_name[int32(e)]
-}
-
-// Errors describing unsusccessful operation status.
-const (
-	StatusUnknownStatus      = StatusCode(Ydb.StatusIds_STATUS_CODE_UNSPECIFIED)
-	StatusBadRequest         = StatusCode(Ydb.StatusIds_BAD_REQUEST)
-	StatusUnauthorized       = StatusCode(Ydb.StatusIds_UNAUTHORIZED)
-	StatusInternalError      = StatusCode(Ydb.StatusIds_INTERNAL_ERROR)
-	StatusAborted            = StatusCode(Ydb.StatusIds_ABORTED)
-	StatusUnavailable        = StatusCode(Ydb.StatusIds_UNAVAILABLE)
-	StatusOverloaded         = StatusCode(Ydb.StatusIds_OVERLOADED)
-	StatusSchemeError        = StatusCode(Ydb.StatusIds_SCHEME_ERROR)
-	StatusGenericError       = StatusCode(Ydb.StatusIds_GENERIC_ERROR)
-	StatusTimeout            = StatusCode(Ydb.StatusIds_TIMEOUT)
-	StatusBadSession         = StatusCode(Ydb.StatusIds_BAD_SESSION)
-	StatusPreconditionFailed = StatusCode(Ydb.StatusIds_PRECONDITION_FAILED)
-	StatusAlreadyExists      = StatusCode(Ydb.StatusIds_ALREADY_EXISTS)
-	StatusNotFound           = StatusCode(Ydb.StatusIds_NOT_FOUND)
-	StatusSessionExpired     = StatusCode(Ydb.StatusIds_SESSION_EXPIRED)
-	StatusCancelled          = StatusCode(Ydb.StatusIds_CANCELLED)
-	StatusUndetermined       = StatusCode(Ydb.StatusIds_UNDETERMINED)
-	StatusUnsupported        = StatusCode(Ydb.StatusIds_UNSUPPORTED)
-	StatusSessionBusy        = StatusCode(Ydb.StatusIds_SESSION_BUSY)
-)
-
-func statusCode(s Ydb.StatusIds_StatusCode) StatusCode {
-	switch s {
-	case Ydb.StatusIds_BAD_REQUEST:
-		return StatusBadRequest
-	case Ydb.StatusIds_UNAUTHORIZED:
-		return StatusUnauthorized
-	case Ydb.StatusIds_INTERNAL_ERROR:
-		return StatusInternalError
-	case Ydb.StatusIds_ABORTED:
-		return StatusAborted
-	case Ydb.StatusIds_UNAVAILABLE:
-		return StatusUnavailable
-	case Ydb.StatusIds_OVERLOADED:
-		return StatusOverloaded
-	case Ydb.StatusIds_SCHEME_ERROR:
-		return StatusSchemeError
-	case Ydb.StatusIds_GENERIC_ERROR:
-		return StatusGenericError
-	case Ydb.StatusIds_TIMEOUT:
-		return StatusTimeout
-	case Ydb.StatusIds_BAD_SESSION:
-		return StatusBadSession
-	case Ydb.StatusIds_PRECONDITION_FAILED:
-		return StatusPreconditionFailed
-	case Ydb.StatusIds_ALREADY_EXISTS:
-		return StatusAlreadyExists
-	case Ydb.StatusIds_NOT_FOUND:
-		return StatusNotFound
-	case Ydb.StatusIds_SESSION_EXPIRED:
-		return StatusSessionExpired
-	case Ydb.StatusIds_CANCELLED:
-		return StatusCancelled
-	case Ydb.StatusIds_UNDETERMINED:
-		return StatusUndetermined
-	case Ydb.StatusIds_UNSUPPORTED:
-		return StatusUnsupported
-	case Ydb.StatusIds_SESSION_BUSY:
-		return StatusSessionBusy
-	default:
-		return StatusUnknownStatus
-	}
-}
-
 type TransportErrorCode int32
 
 func (t TransportErrorCode) String() string {
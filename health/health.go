@@ -0,0 +1,215 @@
+// Package health provides an http.Handler reporting ydb driver health.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/table"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// DefaultCredentialsTimeout is the default timeout of credentials check.
+const DefaultCredentialsTimeout = time.Second
+
+// Handler is an http.Handler which reports driver health as JSON document
+// (see Status). It responds with 200 OK status code if driver is ready with
+// respect to configured criteria, or with 503 Service Unavailable otherwise.
+//
+// Handler may be mounted on an application's debug mux:
+//
+//     http.Handle("/debug/ydb/health", &health.Handler{
+//         Driver:      driver,
+//         SessionPool: &pool,
+//     })
+type Handler struct {
+	// Driver is the driver which health is reported.
+	// Driver must not be nil.
+	Driver ydb.Driver
+
+	// SessionPool is an optional session pool which status is reported.
+	// Closed session pool makes the driver not ready.
+	SessionPool *table.SessionPool
+
+	// Credentials is an optional credentials which validity is reported.
+	// Credentials are checked by obtaining the token; the failure makes the
+	// driver not ready.
+	Credentials ydb.Credentials
+
+	// CredentialsTimeout limits time spent on credentials check.
+	// If CredentialsTimeout is zero then the DefaultCredentialsTimeout is
+	// used.
+	CredentialsTimeout time.Duration
+
+	// MinAliveEndpoints is the minimum number of online endpoints required
+	// for driver to be ready. Endpoints are checked only for drivers created
+	// by ydb.Dialer.
+	// If MinAliveEndpoints is zero then at least one online endpoint is
+	// required.
+	MinAliveEndpoints int
+
+	// MaxDiscoveryAge is the maximum duration since the last successful
+	// endpoints discovery after which driver is not ready.
+	// If MaxDiscoveryAge is zero then discovery freshness is not checked.
+	MaxDiscoveryAge time.Duration
+
+	// Clock is a source of time used by the handler.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+}
+
+// Status is the driver health report.
+type Status struct {
+	Ready bool `json:"ready"`
+
+	// Problems contains descriptions of failed readiness criteria.
+	Problems []string `json:"problems,omitempty"`
+
+	AliveEndpoints int              `json:"alive_endpoints"`
+	Endpoints      []EndpointStatus `json:"endpoints"`
+
+	Discovery   *DiscoveryStatus   `json:"discovery,omitempty"`
+	SessionPool *SessionPoolStatus `json:"session_pool,omitempty"`
+	Credentials *CredentialsStatus `json:"credentials,omitempty"`
+}
+
+type (
+	EndpointStatus struct {
+		Addr         string  `json:"addr"`
+		Port         int     `json:"port"`
+		Local        bool    `json:"local"`
+		State        string  `json:"state"`
+		OpPending    uint64  `json:"op_pending"`
+		OpPerMinute  float64 `json:"op_per_minute"`
+		ErrPerMinute float64 `json:"err_per_minute"`
+	}
+	DiscoveryStatus struct {
+		Enabled     bool       `json:"enabled"`
+		LastSuccess *time.Time `json:"last_success,omitempty"`
+		Age         string     `json:"age,omitempty"`
+		Error       string     `json:"error,omitempty"`
+		Endpoints   int        `json:"endpoints"`
+	}
+	SessionPoolStatus struct {
+		Size    int  `json:"size"`
+		Idle    int  `json:"idle"`
+		InUse   int  `json:"in_use"`
+		Waiting int  `json:"waiting"`
+		Limit   int  `json:"limit"`
+		Closed  bool `json:"closed"`
+	}
+	CredentialsStatus struct {
+		Valid bool   `json:"valid"`
+		Error string `json:"error,omitempty"`
+	}
+)
+
+// ServeHTTP implements http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := h.Check(r.Context())
+	p, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if s.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write(p)
+}
+
+// Check returns current driver health status.
+func (h *Handler) Check(ctx context.Context) (s Status) {
+	clock := timeutil.ClockOrDefault(h.Clock)
+	problem := func(format string, args ...interface{}) {
+		s.Problems = append(s.Problems, fmt.Sprintf(format, args...))
+	}
+
+	s.Endpoints = []EndpointStatus{}
+	ydb.ReadConnStats(h.Driver, func(e ydb.Endpoint, c ydb.ConnStats) {
+		if c.State == ydb.ConnOnline {
+			s.AliveEndpoints++
+		}
+		s.Endpoints = append(s.Endpoints, EndpointStatus{
+			Addr:         e.Addr,
+			Port:         e.Port,
+			Local:        e.Local,
+			State:        c.State.String(),
+			OpPending:    c.OpPending(),
+			OpPerMinute:  c.OpPerMinute,
+			ErrPerMinute: c.ErrPerMinute,
+		})
+	})
+	// Note that endpoints and discovery are known only for drivers created
+	// by ydb.Dialer.
+	d, known := ydb.ReadDiscoveryStats(h.Driver)
+	min := h.MinAliveEndpoints
+	if min <= 0 {
+		min = 1
+	}
+	if known && s.AliveEndpoints < min {
+		problem("too few alive endpoints: %d; want at least %d", s.AliveEndpoints, min)
+	}
+
+	if d.Enabled {
+		x := &DiscoveryStatus{
+			Enabled:   true,
+			Endpoints: d.Endpoints,
+		}
+		if d.LastError != nil {
+			x.Error = d.LastError.Error()
+		}
+		if !d.LastSuccess.IsZero() {
+			age := clock.Now().Sub(d.LastSuccess)
+			x.LastSuccess = &d.LastSuccess
+			x.Age = age.String()
+			if m := h.MaxDiscoveryAge; m > 0 && age > m {
+				problem("discovery is stale: last success was %s ago", age)
+			}
+		}
+		s.Discovery = x
+	}
+
+	if p := h.SessionPool; p != nil {
+		stats := p.Stats()
+		s.SessionPool = &SessionPoolStatus{
+			Size:    stats.Size,
+			Idle:    stats.Idle,
+			InUse:   stats.InUse(),
+			Waiting: stats.Waiting,
+			Limit:   stats.Limit,
+			Closed:  stats.Closed,
+		}
+		if stats.Closed {
+			problem("session pool is closed")
+		}
+	}
+
+	if c := h.Credentials; c != nil {
+		timeout := h.CredentialsTimeout
+		if timeout <= 0 {
+			timeout = DefaultCredentialsTimeout
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		_, err := c.Token(ctx)
+		cancel()
+
+		s.Credentials = &CredentialsStatus{
+			Valid: err == nil,
+		}
+		if err != nil {
+			s.Credentials.Error = err.Error()
+			problem("invalid credentials: %v", err)
+		}
+	}
+
+	s.Ready = len(s.Problems) == 0
+	return s
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestHandler(t *testing.T) {
+	var tokenErr error
+	pool := &table.SessionPool{
+		IdleThreshold:     -1,
+		BusyCheckInterval: -1,
+		Builder: &table.Client{
+			Driver: new(testutil.Driver),
+		},
+	}
+	h := &Handler{
+		Driver:      new(testutil.Driver),
+		SessionPool: pool,
+		Credentials: ydb.CredentialsFunc(func(context.Context) (string, error) {
+			return "token", tokenErr
+		}),
+	}
+	check := func(code int) (s Status) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if w.Code != code {
+			t.Errorf("unexpected status code: %d; want %d", w.Code, code)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	s := check(http.StatusOK)
+	if !s.Ready || len(s.Problems) > 0 {
+		t.Errorf("unexpected status: %+v", s)
+	}
+	if s.SessionPool == nil || s.SessionPool.Limit != table.DefaultSessionPoolSizeLimit {
+		t.Errorf("unexpected session pool status: %+v", s.SessionPool)
+	}
+	if s.Credentials == nil || !s.Credentials.Valid {
+		t.Errorf("unexpected credentials status: %+v", s.Credentials)
+	}
+
+	tokenErr = errors.New("expired")
+	_ = pool.Close(context.Background())
+
+	s = check(http.StatusServiceUnavailable)
+	if s.Ready || len(s.Problems) != 2 {
+		t.Errorf("unexpected status: %+v", s)
+	}
+	if s.Credentials == nil || s.Credentials.Error != "expired" {
+		t.Errorf("unexpected credentials status: %+v", s.Credentials)
+	}
+}
@@ -0,0 +1,190 @@
+package ydb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// Default parameters used by Hedger.
+const (
+	DefaultHedgeDelay  = 100 * time.Millisecond
+	DefaultHedgeBudget = 0.1
+)
+
+// HedgerStats contains Hedger counters.
+type HedgerStats struct {
+	// Calls is the number of calls of allowed methods.
+	Calls uint64
+
+	// Hedged is the number of extra requests sent.
+	Hedged uint64
+
+	// Wins is the number of calls completed by the extra request.
+	Wins uint64
+}
+
+// Hedger contains logic of hedging unary operations. That is, if operation
+// is not completed within the Delay, the same request is sent once again and
+// the first successful response is used. The other request is canceled
+// immediately.
+//
+// Hedging amplifies cluster load, so only methods listed in Methods are
+// hedged and the number of extra requests is limited by the Budget.
+//
+// Hedger may be used as driver middleware (see CallMiddleware()).
+type Hedger struct {
+	// Methods is an allow-list of full gRPC method names which may be hedged,
+	// e.g. "/Ydb.Table.V1.TableService/ExecuteDataQuery". Only idempotent
+	// methods should be listed here.
+	// If Methods is empty then no method is hedged.
+	Methods []string
+
+	// Delay is the time to wait for the response before sending the extra
+	// request.
+	// If Delay is zero then the DefaultHedgeDelay is used.
+	Delay time.Duration
+
+	// Budget is the maximum ratio of extra requests to the calls of allowed
+	// methods. For example, 0.1 means that at most 10% of calls are hedged.
+	// If Budget is zero then the DefaultHedgeBudget is used.
+	Budget float64
+
+	// Clock is a source of time used to wait for the Delay.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	once    sync.Once
+	methods map[string]bool
+
+	calls  uint64
+	hedged uint64
+	wins   uint64
+}
+
+// CallMiddleware returns driver middleware which hedges unary operations.
+func (h *Hedger) CallMiddleware() CallMiddleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, op internal.Operation) error {
+			method, req, res := internal.Unwrap(op)
+			if res == nil || !h.allowed(method) {
+				return next(ctx, op)
+			}
+			return h.call(ctx, next, method, req, res)
+		}
+	}
+}
+
+// Stats returns current counters of the hedger.
+func (h *Hedger) Stats() HedgerStats {
+	return HedgerStats{
+		Calls:  atomic.LoadUint64(&h.calls),
+		Hedged: atomic.LoadUint64(&h.hedged),
+		Wins:   atomic.LoadUint64(&h.wins),
+	}
+}
+
+type hedgeResult struct {
+	res   proto.Message
+	err   error
+	hedge bool
+}
+
+func (h *Hedger) call(
+	ctx context.Context, next CallFunc,
+	method string, req, res proto.Message,
+) error {
+	atomic.AddUint64(&h.calls, 1)
+
+	// Each request is made with its own copy of messages because requests are
+	// modified by the driver and the loser may still be running when the call
+	// returns.
+	hreq := proto.Clone(req)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, 2)
+	start := func(req proto.Message, hedge bool) {
+		res := proto.Clone(res)
+		go func() {
+			err := next(ctx, internal.Wrap(method, req, res))
+			results <- hedgeResult{res, err, hedge}
+		}()
+	}
+	start(req, false)
+	pending := 1
+
+	timer := timeutil.ClockOrDefault(h.Clock).NewTimer(h.delay())
+	defer timer.Stop()
+	timeout := timer.C()
+	for {
+		select {
+		case <-timeout:
+			timeout = nil
+			if h.reserve() {
+				start(hreq, true)
+				pending++
+			}
+
+		case r := <-results:
+			pending--
+			if r.err != nil && pending > 0 {
+				// Wait for the other request.
+				continue
+			}
+			if r.err == nil {
+				if r.hedge {
+					atomic.AddUint64(&h.wins, 1)
+				}
+				res.Reset()
+				proto.Merge(res, r.res)
+			}
+			return r.err
+		}
+	}
+}
+
+func (h *Hedger) allowed(method string) bool {
+	h.once.Do(func() {
+		h.methods = make(map[string]bool, len(h.Methods))
+		for _, m := range h.Methods {
+			h.methods[m] = true
+		}
+	})
+	return h.methods[method]
+}
+
+// reserve reports whether an extra request fits the budget and accounts it if
+// so.
+func (h *Hedger) reserve() bool {
+	b := h.Budget
+	if b == 0 {
+		b = DefaultHedgeBudget
+	}
+	for {
+		var (
+			calls  = atomic.LoadUint64(&h.calls)
+			hedged = atomic.LoadUint64(&h.hedged)
+		)
+		if float64(hedged+1) > b*float64(calls) {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&h.hedged, hedged, hedged+1) {
+			return true
+		}
+	}
+}
+
+func (h *Hedger) delay() time.Duration {
+	if h.Delay == 0 {
+		return DefaultHedgeDelay
+	}
+	return h.Delay
+}
@@ -0,0 +1,89 @@
+package ydb
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestHedger(t *testing.T) {
+	timerC := make(chan time.Time, 1)
+	cleanup := timeutil.StubTestHookNewTimer(func(time.Duration) timeutil.Timer {
+		return timetest.Timer{Ch: timerC}
+	})
+	defer cleanup()
+
+	const method = "/Ydb.Table.V1.TableService/ExecuteDataQuery"
+	h := Hedger{
+		Methods: []string{method},
+		Budget:  0.5,
+	}
+	var (
+		started  = make(chan struct{}, 2)
+		canceled = make(chan struct{}, 2)
+
+		// slow is the original request of the call which must be slow. The
+		// hedged request is a copy of it, thus it is distinguished by
+		// identity rather than by the order of arrival.
+		slow atomic.Value
+	)
+	call := h.CallMiddleware()(func(ctx context.Context, op internal.Operation) error {
+		_, req, res := internal.Unwrap(op)
+		started <- struct{}{}
+		if x, _ := slow.Load().(*Ydb_Table.ExecuteDataQueryRequest); x == req {
+			<-ctx.Done()
+			canceled <- struct{}{}
+			return ctx.Err()
+		}
+		res.(*Ydb_Table.ExecuteQueryResult).QueryMeta = &Ydb_Table.QueryMeta{
+			Id: "query",
+		}
+		return nil
+	})
+	do := func(method, session string) *Ydb_Table.ExecuteQueryResult {
+		t.Helper()
+		req := &Ydb_Table.ExecuteDataQueryRequest{SessionId: session}
+		if session == "slow" {
+			slow.Store(req)
+		}
+		res := new(Ydb_Table.ExecuteQueryResult)
+		err := call(context.Background(), internal.Wrap(method, req, res))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// The first call does not fit the budget.
+	do(method, "fast")
+	<-started
+
+	timerC <- time.Time{}
+	res := do(method, "slow")
+	<-started
+	<-started
+	if id := res.GetQueryMeta().GetId(); id != "query" {
+		t.Fatalf("unexpected response: %q", id)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("loser request was not canceled")
+	}
+	if act, exp := h.Stats(), (HedgerStats{Calls: 2, Hedged: 1, Wins: 1}); act != exp {
+		t.Fatalf("unexpected stats: %+v; want %+v", act, exp)
+	}
+
+	// Methods which are not allowed must not be hedged.
+	timerC <- time.Time{}
+	do("/Ydb.Table.V1.TableService/ExecuteSchemeQuery", "fast")
+	if act, exp := h.Stats(), (HedgerStats{Calls: 2, Hedged: 1, Wins: 1}); act != exp {
+		t.Fatalf("unexpected stats: %+v; want %+v", act, exp)
+	}
+}
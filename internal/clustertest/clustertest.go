@@ -0,0 +1,44 @@
+/*
+Package clustertest gives the ydbtest package access to the balancing logic
+of the ydb package, which is not exported.
+*/
+package clustertest
+
+import (
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// Cluster is a set of endpoint connections balanced the same way as by the
+// driver, but without any network interaction.
+//
+// Connections are referred by their indexes in order of Add() calls.
+// Cluster is not safe for use by multiple goroutines simultaneously.
+type Cluster interface {
+	// Add adds online connection to the endpoint and returns its index.
+	Add(addr string, port int, loadFactor float32, local bool) int
+
+	// SetOnline marks i-th connection as online or offline. Offline
+	// connections are not chosen by Next().
+	SetOnline(i int, online bool)
+
+	// Next returns index of the connection chosen by the balancer.
+	// It returns -1 if there are no online connections.
+	Next() int
+
+	// OperationStart and OperationDone account operation in the i-th
+	// connection stats.
+	OperationStart(i int, start time.Time)
+	OperationDone(i int, start, end time.Time, err error)
+
+	// Stats returns ydb.ConnStats of the i-th connection.
+	Stats(i int) interface{}
+}
+
+// New creates Cluster with balancer described by config, which must be nil
+// or *ydb.DriverConfig. Connections stats are collected with respect to
+// clock.
+//
+// New is set by the ydb package.
+var New func(config interface{}, clock timeutil.Clock) Cluster
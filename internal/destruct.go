@@ -0,0 +1,250 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+)
+
+// TypeError describes a mismatch between the actual type of a value and the
+// type expected by destructuring function.
+type TypeError struct {
+	Type T
+	Want string
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("ydb: unexpected value type: %s; want %s", e.Type, e.Want)
+}
+
+// ValueType returns type of v.
+func ValueType(v V) T {
+	return v.(Value).t
+}
+
+// IsNull reports whether v is a NULL value.
+func IsNull(v V) bool {
+	_, null := v.(Value).v.Value.(*Ydb.Value_NullFlagValue)
+	return null
+}
+
+// UnwrapOptional returns the value wrapped by optional value v. It returns
+// false if v is NULL. If v is not of optional type, it returns v and true.
+func UnwrapOptional(v V) (V, bool) {
+	x := v.(Value)
+	opt, ok := x.t.(OptionalType)
+	if !ok {
+		return v, true
+	}
+	if IsNull(x) {
+		return nil, false
+	}
+	inner := x.v
+	if _, nested := opt.T.(OptionalType); nested {
+		inner = x.v.GetNestedValue()
+	}
+	return Value{
+		t: opt.T,
+		v: inner,
+	}, true
+}
+
+// ListItems returns items of the list value v.
+func ListItems(v V) ([]V, error) {
+	x := v.(Value)
+	t, ok := x.t.(ListType)
+	if !ok {
+		return nil, &TypeError{Type: x.t, Want: "List"}
+	}
+	vs := make([]V, len(x.v.Items))
+	for i, item := range x.v.Items {
+		vs[i] = Value{t: t.T, v: item}
+	}
+	return vs, nil
+}
+
+// TupleItems returns elements of the tuple value v.
+func TupleItems(v V) ([]V, error) {
+	x := v.(Value)
+	t, ok := x.t.(TupleType)
+	if !ok {
+		return nil, &TypeError{Type: x.t, Want: "Tuple"}
+	}
+	vs := make([]V, len(x.v.Items))
+	for i, item := range x.v.Items {
+		vs[i] = Value{t: t.Elems[i], v: item}
+	}
+	return vs, nil
+}
+
+// StructFieldsOf calls it for each field of the struct value v in order of
+// the struct type declaration.
+func StructFieldsOf(v V, it func(string, V)) error {
+	x := v.(Value)
+	t, ok := x.t.(StructType)
+	if !ok {
+		return &TypeError{Type: x.t, Want: "Struct"}
+	}
+	for i, item := range x.v.Items {
+		f := t.Fields[i]
+		it(f.Name, Value{t: f.Type, v: item})
+	}
+	return nil
+}
+
+// DictPairs calls it for each key and payload pair of the dict value v.
+func DictPairs(v V, it func(V, V)) error {
+	x := v.(Value)
+	t, ok := x.t.(DictType)
+	if !ok {
+		return &TypeError{Type: x.t, Want: "Dict"}
+	}
+	for _, p := range x.v.Pairs {
+		it(
+			Value{t: t.Key, v: p.Key},
+			Value{t: t.Payload, v: p.Payload},
+		)
+	}
+	return nil
+}
+
+// VariantItem returns the value stored in variant value v and its index.
+func VariantItem(v V) (V, uint32, error) {
+	x := v.(Value)
+	t, ok := x.t.(VariantType)
+	if !ok {
+		return nil, 0, &TypeError{Type: x.t, Want: "Variant"}
+	}
+	i := x.v.VariantIndex
+	typ, ok := t.at(int(i))
+	if !ok {
+		return nil, 0, fmt.Errorf("ydb: no %d-th variant for %s", i, t)
+	}
+	return Value{t: typ, v: x.v.GetNestedValue()}, i, nil
+}
+
+func primitiveValue(v V, t PrimitiveType) (*Ydb.Value, error) {
+	x := v.(Value)
+	if !TypesEqual(x.t, t) {
+		return nil, &TypeError{Type: x.t, Want: t.String()}
+	}
+	return x.v, nil
+}
+
+func BoolFromValue(v V) (bool, error) {
+	x, err := primitiveValue(v, TypeBool)
+	return x.GetBoolValue(), err
+}
+
+func Int8FromValue(v V) (int8, error) {
+	x, err := primitiveValue(v, TypeInt8)
+	return int8(x.GetInt32Value()), err
+}
+
+func Uint8FromValue(v V) (uint8, error) {
+	x, err := primitiveValue(v, TypeUint8)
+	return uint8(x.GetUint32Value()), err
+}
+
+func Int16FromValue(v V) (int16, error) {
+	x, err := primitiveValue(v, TypeInt16)
+	return int16(x.GetInt32Value()), err
+}
+
+func Uint16FromValue(v V) (uint16, error) {
+	x, err := primitiveValue(v, TypeUint16)
+	return uint16(x.GetUint32Value()), err
+}
+
+func Int32FromValue(v V) (int32, error) {
+	x, err := primitiveValue(v, TypeInt32)
+	return x.GetInt32Value(), err
+}
+
+func Uint32FromValue(v V) (uint32, error) {
+	x, err := primitiveValue(v, TypeUint32)
+	return x.GetUint32Value(), err
+}
+
+func Int64FromValue(v V) (int64, error) {
+	x, err := primitiveValue(v, TypeInt64)
+	return x.GetInt64Value(), err
+}
+
+func Uint64FromValue(v V) (uint64, error) {
+	x, err := primitiveValue(v, TypeUint64)
+	return x.GetUint64Value(), err
+}
+
+func FloatFromValue(v V) (float32, error) {
+	x, err := primitiveValue(v, TypeFloat)
+	return x.GetFloatValue(), err
+}
+
+func DoubleFromValue(v V) (float64, error) {
+	x, err := primitiveValue(v, TypeDouble)
+	return x.GetDoubleValue(), err
+}
+
+func DateFromValue(v V) (uint32, error) {
+	x, err := primitiveValue(v, TypeDate)
+	return x.GetUint32Value(), err
+}
+
+func DatetimeFromValue(v V) (uint32, error) {
+	x, err := primitiveValue(v, TypeDatetime)
+	return x.GetUint32Value(), err
+}
+
+func TimestampFromValue(v V) (uint64, error) {
+	x, err := primitiveValue(v, TypeTimestamp)
+	return x.GetUint64Value(), err
+}
+
+func IntervalFromValue(v V) (int64, error) {
+	x, err := primitiveValue(v, TypeInterval)
+	return x.GetInt64Value(), err
+}
+
+func TzDateFromValue(v V) (string, error) {
+	x, err := primitiveValue(v, TypeTzDate)
+	return x.GetTextValue(), err
+}
+
+func TzDatetimeFromValue(v V) (string, error) {
+	x, err := primitiveValue(v, TypeTzDatetime)
+	return x.GetTextValue(), err
+}
+
+func TzTimestampFromValue(v V) (string, error) {
+	x, err := primitiveValue(v, TypeTzTimestamp)
+	return x.GetTextValue(), err
+}
+
+func StringFromValue(v V) ([]byte, error) {
+	x, err := primitiveValue(v, TypeString)
+	return x.GetBytesValue(), err
+}
+
+func UTF8FromValue(v V) (string, error) {
+	x, err := primitiveValue(v, TypeUTF8)
+	return x.GetTextValue(), err
+}
+
+func YSONFromValue(v V) (string, error) {
+	x, err := primitiveValue(v, TypeYSON)
+	return x.GetTextValue(), err
+}
+
+func JSONFromValue(v V) (string, error) {
+	x, err := primitiveValue(v, TypeJSON)
+	return x.GetTextValue(), err
+}
+
+func UUIDFromValue(v V) (u [16]byte, err error) {
+	x, err := primitiveValue(v, TypeUUID)
+	if err != nil {
+		return u, err
+	}
+	return BigEndianUint128(x.High_128, x.GetLow_128()), nil
+}
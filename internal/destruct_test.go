@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestUnwrapOptional(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		value V
+		depth int
+		null  bool
+	}{
+		{
+			name:  "not optional",
+			value: Int32Value(42),
+		},
+		{
+			name:  "optional",
+			value: OptionalValue(Int32Value(42)),
+			depth: 1,
+		},
+		{
+			name:  "nested optional",
+			value: OptionalValue(OptionalValue(Int32Value(42))),
+			depth: 2,
+		},
+		{
+			name:  "null",
+			value: NullValue(TypeInt32),
+			null:  true,
+		},
+		{
+			name:  "nested null",
+			value: OptionalValue(NullValue(TypeInt32)),
+			depth: 1,
+			null:  true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			v := test.value
+			for i := 0; i < test.depth; i++ {
+				var ok bool
+				v, ok = UnwrapOptional(v)
+				if !ok {
+					t.Fatalf("unexpected null at depth %d", i)
+				}
+			}
+			if IsNull(v) != test.null {
+				t.Fatalf("unexpected null: %t", IsNull(v))
+			}
+			if test.null {
+				if _, ok := UnwrapOptional(v); ok {
+					t.Fatalf("unexpected unwrap of null")
+				}
+				return
+			}
+			x, err := Int32FromValue(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x != 42 {
+				t.Fatalf("unexpected value: %d", x)
+			}
+		})
+	}
+}
+
+func TestPrimitiveFromValue(t *testing.T) {
+	if x, err := Uint8FromValue(Uint8Value(7)); err != nil || x != 7 {
+		t.Errorf("unexpected Uint8: %v, %v", x, err)
+	}
+	if x, err := UTF8FromValue(UTF8Value("foo")); err != nil || x != "foo" {
+		t.Errorf("unexpected UTF8: %v, %v", x, err)
+	}
+	if x, err := StringFromValue(StringValue([]byte("bar"))); err != nil || string(x) != "bar" {
+		t.Errorf("unexpected String: %v, %v", x, err)
+	}
+	u := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	if x, err := UUIDFromValue(UUIDValue(u)); err != nil || x != u {
+		t.Errorf("unexpected UUID: %v, %v", x, err)
+	}
+	_, err := Int64FromValue(Int32Value(1))
+	if _, ok := err.(*TypeError); !ok {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err = Int32FromValue(OptionalValue(Int32Value(1)))
+	if _, ok := err.(*TypeError); !ok {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestContainerItems(t *testing.T) {
+	list := ListValue(3, func(i int) V {
+		return Int32Value(int32(i))
+	})
+	items, err := ListItems(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ints []int32
+	for _, item := range items {
+		x, err := Int32FromValue(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ints = append(ints, x)
+	}
+	if exp := []int32{0, 1, 2}; !reflect.DeepEqual(ints, exp) {
+		t.Errorf("unexpected list items: %v; want %v", ints, exp)
+	}
+	if _, err := TupleItems(list); err == nil {
+		t.Errorf("no error for list as tuple")
+	}
+
+	var p StructValueProto
+	p.Add("foo", UTF8Value("bar"))
+	p.Add("baz", Int32Value(42))
+	var names []string
+	err = StructFieldsOf(StructValue(&p), func(name string, v V) {
+		names = append(names, fmt.Sprintf("%s:%s", name, ValueType(v)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"foo:Utf8", "baz:Int32"}; !reflect.DeepEqual(names, exp) {
+		t.Errorf("unexpected struct fields: %v; want %v", names, exp)
+	}
+
+	dict := DictValue(4, func(i int) V {
+		if i%2 == 0 {
+			return UTF8Value(string(rune('a' + i/2)))
+		}
+		return Int32Value(int32(i / 2))
+	})
+	m := make(map[string]int32)
+	err = DictPairs(dict, func(k, v V) {
+		key, _ := UTF8FromValue(k)
+		val, _ := Int32FromValue(v)
+		m[key] = val
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]int32{"a": 0, "b": 1}; !reflect.DeepEqual(m, exp) {
+		t.Errorf("unexpected dict pairs: %v; want %v", m, exp)
+	}
+
+	variant := VariantValue(Int32Value(42), 1, VariantType{T: TupleType{
+		Elems: []T{
+			TypeString,
+			TypeInt32,
+		},
+	}})
+	v, i, err := VariantItem(variant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, err := Int32FromValue(v); err != nil || x != 42 || i != 1 {
+		t.Errorf("unexpected variant item: %d, %v (%v)", i, x, err)
+	}
+}
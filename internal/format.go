@@ -0,0 +1,476 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/decimal"
+)
+
+// Layouts used to render date and time values.
+const (
+	layoutDate      = "2006-01-02"
+	layoutDatetime  = "2006-01-02T15:04:05Z"
+	layoutTimestamp = "2006-01-02T15:04:05.000000Z"
+)
+
+// FormatValue returns human-readable representation of v. Unlike String()
+// method of the Value it does not contain types information: optional values
+// are rendered as its underlying value or NULL, containers are rendered
+// JSON-like.
+func FormatValue(v V) string {
+	var (
+		buf bytes.Buffer
+		x   = v.toYDB()
+	)
+	formatValue(&buf, TypeFromYDB(x.Type), x.Value)
+	return buf.String()
+}
+
+// FormatYQL returns YQL literal expression which evaluates to v. It returns
+// error if v is of type which could not be represented as literal.
+func FormatYQL(v V) (string, error) {
+	var (
+		buf bytes.Buffer
+		x   = v.toYDB()
+	)
+	if err := formatYQL(&buf, TypeFromYDB(x.Type), x.Value); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func formatValue(buf *bytes.Buffer, t T, v *Ydb.Value) {
+	if _, ok := v.Value.(*Ydb.Value_NullFlagValue); ok {
+		if _, void := t.(VoidType); void {
+			buf.WriteString("Void")
+		} else {
+			buf.WriteString("NULL")
+		}
+		return
+	}
+	switch x := t.(type) {
+	case PrimitiveType:
+		formatPrimitive(buf, x, v)
+
+	case DecimalType:
+		buf.WriteString(formatDecimal(x, v))
+
+	case OptionalType:
+		if n, ok := v.Value.(*Ydb.Value_NestedValue); ok {
+			v = n.NestedValue
+		}
+		formatValue(buf, x.T, v)
+
+	case ListType:
+		buf.WriteByte('[')
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			formatValue(buf, x.T, item)
+		}
+		buf.WriteByte(']')
+
+	case TupleType:
+		buf.WriteByte('(')
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			formatValue(buf, x.Elems[i], item)
+		}
+		buf.WriteByte(')')
+
+	case StructType:
+		buf.WriteByte('{')
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(x.Fields[i].Name)
+			buf.WriteString(": ")
+			formatValue(buf, x.Fields[i].Type, item)
+		}
+		buf.WriteByte('}')
+
+	case DictType:
+		buf.WriteByte('{')
+		for i, pair := range v.Pairs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			formatValue(buf, x.Key, pair.Key)
+			buf.WriteString(": ")
+			formatValue(buf, x.Payload, pair.Payload)
+		}
+		buf.WriteByte('}')
+
+	case VariantType:
+		name, t := x.item(int(v.VariantIndex))
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		formatValue(buf, t, v.GetNestedValue())
+
+	default:
+		buf.WriteString("<unknown>")
+	}
+}
+
+func formatPrimitive(buf *bytes.Buffer, t PrimitiveType, v *Ydb.Value) {
+	switch t {
+	case TypeDate:
+		buf.WriteString(UnmarshalDate(v.GetUint32Value()).UTC().Format(layoutDate))
+	case TypeDatetime:
+		buf.WriteString(UnmarshalDatetime(v.GetUint32Value()).UTC().Format(time.RFC3339))
+	case TypeTimestamp:
+		buf.WriteString(UnmarshalTimestamp(v.GetUint64Value()).UTC().Format(time.RFC3339Nano))
+	case TypeInterval:
+		// Interval is stored in microseconds.
+		buf.WriteString((time.Duration(v.GetInt64Value()) * time.Microsecond).String())
+	case TypeString:
+		buf.WriteString(strconv.Quote(string(v.GetBytesValue())))
+	case TypeUTF8:
+		buf.WriteString(strconv.Quote(v.GetTextValue()))
+	case TypeUUID:
+		buf.WriteString(formatUUID(v))
+	case TypeFloat:
+		buf.WriteString(strconv.FormatFloat(float64(v.GetFloatValue()), 'g', -1, 32))
+	case TypeDouble:
+		buf.WriteString(strconv.FormatFloat(v.GetDoubleValue(), 'g', -1, 64))
+	default:
+		x, _ := primitiveFromYDB(v)
+		if b, ok := x.([]byte); ok {
+			buf.Write(b)
+		} else {
+			fmt.Fprintf(buf, "%v", x)
+		}
+	}
+}
+
+func formatYQL(buf *bytes.Buffer, t T, v *Ydb.Value) error {
+	switch x := t.(type) {
+	case VoidType:
+		buf.WriteString("Void()")
+		return nil
+
+	case PrimitiveType:
+		return formatYQLPrimitive(buf, x, v)
+
+	case DecimalType:
+		fmt.Fprintf(buf, "Decimal(%q, %d, %d)", formatDecimal(x, v), x.Precision, x.Scale)
+		return nil
+
+	case OptionalType:
+		switch n := v.Value.(type) {
+		case *Ydb.Value_NullFlagValue:
+			buf.WriteString("Nothing(")
+			if err := formatYQLType(buf, t); err != nil {
+				return err
+			}
+			buf.WriteByte(')')
+			return nil
+		case *Ydb.Value_NestedValue:
+			v = n.NestedValue
+		}
+		buf.WriteString("Just(")
+		if err := formatYQL(buf, x.T, v); err != nil {
+			return err
+		}
+		buf.WriteByte(')')
+		return nil
+
+	case ListType:
+		if len(v.Items) == 0 {
+			buf.WriteString("ListCreate(")
+			if err := formatYQLType(buf, x.T); err != nil {
+				return err
+			}
+			buf.WriteByte(')')
+			return nil
+		}
+		buf.WriteString("AsList(")
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := formatYQL(buf, x.T, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(')')
+		return nil
+
+	case TupleType:
+		buf.WriteString("AsTuple(")
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := formatYQL(buf, x.Elems[i], item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(')')
+		return nil
+
+	case StructType:
+		buf.WriteString("AsStruct(")
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := formatYQL(buf, x.Fields[i].Type, item); err != nil {
+				return err
+			}
+			buf.WriteString(" AS ")
+			buf.WriteString(quoteIdent(x.Fields[i].Name))
+		}
+		buf.WriteByte(')')
+		return nil
+
+	case DictType:
+		if len(v.Pairs) == 0 {
+			buf.WriteString("DictCreate(")
+			if err := formatYQLType(buf, x.Key); err != nil {
+				return err
+			}
+			buf.WriteString(", ")
+			if err := formatYQLType(buf, x.Payload); err != nil {
+				return err
+			}
+			buf.WriteByte(')')
+			return nil
+		}
+		buf.WriteString("AsDict(")
+		for i, pair := range v.Pairs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("AsTuple(")
+			if err := formatYQL(buf, x.Key, pair.Key); err != nil {
+				return err
+			}
+			buf.WriteString(", ")
+			if err := formatYQL(buf, x.Payload, pair.Payload); err != nil {
+				return err
+			}
+			buf.WriteByte(')')
+		}
+		buf.WriteByte(')')
+		return nil
+
+	case VariantType:
+		name, item := x.item(int(v.VariantIndex))
+		buf.WriteString("Variant(")
+		if err := formatYQL(buf, item, v.GetNestedValue()); err != nil {
+			return err
+		}
+		buf.WriteString(", ")
+		buf.WriteString(strconv.Quote(name))
+		buf.WriteString(", ")
+		if err := formatYQLType(buf, t); err != nil {
+			return err
+		}
+		buf.WriteByte(')')
+		return nil
+
+	default:
+		return fmt.Errorf("ydb: could not format value of type %T as YQL literal", t)
+	}
+}
+
+func formatYQLPrimitive(buf *bytes.Buffer, t PrimitiveType, v *Ydb.Value) error {
+	var s string
+	switch t {
+	case TypeBool:
+		buf.WriteString(strconv.FormatBool(v.GetBoolValue()))
+		return nil
+	case TypeString:
+		buf.WriteString(strconv.Quote(string(v.GetBytesValue())))
+		return nil
+	case TypeUTF8:
+		buf.WriteString(strconv.Quote(v.GetTextValue()))
+		buf.WriteByte('u')
+		return nil
+	case TypeInt8, TypeInt16, TypeInt32:
+		s = strconv.FormatInt(int64(v.GetInt32Value()), 10)
+	case TypeUint8, TypeUint16, TypeUint32:
+		s = strconv.FormatUint(uint64(v.GetUint32Value()), 10)
+	case TypeInt64:
+		s = strconv.FormatInt(v.GetInt64Value(), 10)
+	case TypeUint64:
+		s = strconv.FormatUint(v.GetUint64Value(), 10)
+	case TypeFloat:
+		s = formatFloat(float64(v.GetFloatValue()), 32)
+	case TypeDouble:
+		s = formatFloat(v.GetDoubleValue(), 64)
+	case TypeDate:
+		s = UnmarshalDate(v.GetUint32Value()).UTC().Format(layoutDate)
+	case TypeDatetime:
+		s = UnmarshalDatetime(v.GetUint32Value()).UTC().Format(layoutDatetime)
+	case TypeTimestamp:
+		s = UnmarshalTimestamp(v.GetUint64Value()).UTC().Format(layoutTimestamp)
+	case TypeInterval:
+		s = formatInterval(v.GetInt64Value())
+	case TypeTzDate, TypeTzDatetime, TypeTzTimestamp, TypeYSON, TypeJSON:
+		s = v.GetTextValue()
+	case TypeUUID:
+		s = formatUUID(v)
+	default:
+		return fmt.Errorf("ydb: could not format value of type %s as YQL literal", t)
+	}
+	buf.WriteString(t.String())
+	buf.WriteByte('(')
+	buf.WriteString(strconv.Quote(s))
+	buf.WriteByte(')')
+	return nil
+}
+
+// formatYQLType writes YQL representation of type t.
+func formatYQLType(buf *bytes.Buffer, t T) (err error) {
+	list := func(ts []T) {
+		for i, t := range ts {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err == nil {
+				err = formatYQLType(buf, t)
+			}
+		}
+	}
+	fields := func(fs []StructField) {
+		for i, f := range fs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(quoteIdent(f.Name))
+			buf.WriteByte(':')
+			if err == nil {
+				err = formatYQLType(buf, f.Type)
+			}
+		}
+	}
+	switch x := t.(type) {
+	case PrimitiveType:
+		if x == TypeUnknown {
+			return fmt.Errorf("ydb: could not format unknown type")
+		}
+		buf.WriteString(x.String())
+	case VoidType, DecimalType:
+		t.toString(buf)
+	case OptionalType:
+		buf.WriteString("Optional<")
+		err = formatYQLType(buf, x.T)
+		buf.WriteByte('>')
+	case ListType:
+		buf.WriteString("List<")
+		err = formatYQLType(buf, x.T)
+		buf.WriteByte('>')
+	case TupleType:
+		buf.WriteString("Tuple<")
+		list(x.Elems)
+		buf.WriteByte('>')
+	case StructType:
+		buf.WriteString("Struct<")
+		fields(x.Fields)
+		buf.WriteByte('>')
+	case DictType:
+		buf.WriteString("Dict<")
+		list([]T{x.Key, x.Payload})
+		buf.WriteByte('>')
+	case VariantType:
+		buf.WriteString("Variant<")
+		if x.S.Empty() {
+			list(x.T.Elems)
+		} else {
+			fields(x.S.Fields)
+		}
+		buf.WriteByte('>')
+	default:
+		return fmt.Errorf("ydb: could not format type %T", t)
+	}
+	return err
+}
+
+// item returns name (or index) and type of i-th variant item.
+func (v VariantType) item(i int) (string, T) {
+	if !v.S.Empty() {
+		f := v.S.Fields[i]
+		return f.Name, f.Type
+	}
+	return strconv.Itoa(i), v.T.Elems[i]
+}
+
+func formatDecimal(t DecimalType, v *Ydb.Value) string {
+	var p [16]byte
+	binary.BigEndian.PutUint64(p[0:8], v.High_128)
+	binary.BigEndian.PutUint64(p[8:16], v.GetLow_128())
+	return decimal.Format(decimal.FromInt128(p, t.Precision, t.Scale), t.Precision, t.Scale)
+}
+
+func formatUUID(v *Ydb.Value) string {
+	var (
+		p [16]byte
+		s [36]byte
+	)
+	binary.BigEndian.PutUint64(p[0:8], v.High_128)
+	binary.BigEndian.PutUint64(p[8:16], v.GetLow_128())
+	hex.Encode(s[0:8], p[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], p[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], p[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], p[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], p[10:])
+	return string(s[:])
+}
+
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// formatInterval returns ISO 8601 representation of the interval given in
+// microseconds.
+func formatInterval(us int64) string {
+	var sign string
+	if us < 0 {
+		sign = "-"
+		us = -us
+	}
+	s := strconv.FormatInt(us/1e6, 10)
+	if frac := us % 1e6; frac != 0 {
+		s += "." + fmt.Sprintf("%06d", frac)
+	}
+	return sign + "PT" + s + "S"
+}
+
+// quoteIdent returns name quoted with backticks.
+func quoteIdent(name string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('`')
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c == '`' || c == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(name[i])
+	}
+	buf.WriteByte('`')
+	return buf.String()
+}
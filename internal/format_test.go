@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func testStructValue() V {
+	var p StructValueProto
+	p.Add("id", Uint64Value(1))
+	p.Add("name", OptionalValue(UTF8Value("foo")))
+	p.Add("deleted", NullValue(TypeTimestamp))
+	return StructValue(&p)
+}
+
+func TestFormatValue(t *testing.T) {
+	for _, test := range []struct {
+		value V
+		human string
+		yql   string
+	}{
+		{
+			value: VoidValue,
+			human: "Void",
+			yql:   "Void()",
+		},
+		{
+			value: Int32Value(-42),
+			human: "-42",
+			yql:   `Int32("-42")`,
+		},
+		{
+			value: BoolValue(true),
+			human: "true",
+			yql:   "true",
+		},
+		{
+			value: DoubleValue(0.5),
+			human: "0.5",
+			yql:   `Double("0.5")`,
+		},
+		{
+			value: StringValue([]byte("a\"b\x00")),
+			human: `"a\"b\x00"`,
+			yql:   `"a\"b\x00"`,
+		},
+		{
+			value: UTF8Value("foo"),
+			human: `"foo"`,
+			yql:   `"foo"u`,
+		},
+		{
+			value: JSONValue(`{"a":1}`),
+			human: `{"a":1}`,
+			yql:   `Json("{\"a\":1}")`,
+		},
+		{
+			value: DateValue(MarshalDate(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))),
+			human: "2020-01-02",
+			yql:   `Date("2020-01-02")`,
+		},
+		{
+			value: TimestampValue(MarshalTimestamp(time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC))),
+			human: "2020-01-02T03:04:05.000006Z",
+			yql:   `Timestamp("2020-01-02T03:04:05.000006Z")`,
+		},
+		{
+			value: IntervalValue(-1500000),
+			human: "-1.5s",
+			yql:   `Interval("-PT1.500000S")`,
+		},
+		{
+			value: UUIDValue([16]byte{0: 0x12, 15: 0xff}),
+			human: "12000000-0000-0000-0000-0000000000ff",
+			yql:   `Uuid("12000000-0000-0000-0000-0000000000ff")`,
+		},
+		{
+			value: DecimalValue(DecimalType{Precision: 22, Scale: 9}, [16]byte{15: 10}),
+			human: "0.000000010",
+			yql:   `Decimal("0.000000010", 22, 9)`,
+		},
+		{
+			value: NullValue(TypeInt32),
+			human: "NULL",
+			yql:   "Nothing(Optional<Int32>)",
+		},
+		{
+			value: OptionalValue(OptionalValue(Int32Value(42))),
+			human: "42",
+			yql:   `Just(Just(Int32("42")))`,
+		},
+		{
+			value: ListValue(2, func(i int) V {
+				return Int32Value(int32(i))
+			}),
+			human: "[0, 1]",
+			yql:   `AsList(Int32("0"), Int32("1"))`,
+		},
+		{
+			value: ZeroValue(ListType{T: TypeUTF8}),
+			human: "[]",
+			yql:   "ListCreate(Utf8)",
+		},
+		{
+			value: TupleValue(2, func(i int) V {
+				return Int32Value(int32(i))
+			}),
+			human: "(0, 1)",
+			yql:   `AsTuple(Int32("0"), Int32("1"))`,
+		},
+		{
+			value: testStructValue(),
+			human: `{id: 1, name: "foo", deleted: NULL}`,
+			yql: "AsStruct(" +
+				"Uint64(\"1\") AS `id`, " +
+				"Just(\"foo\"u) AS `name`, " +
+				"Nothing(Optional<Timestamp>) AS `deleted`)",
+		},
+		{
+			value: DictValue(2, func(i int) V {
+				if i == 0 {
+					return UTF8Value("foo")
+				}
+				return Int32Value(42)
+			}),
+			human: `{"foo": 42}`,
+			yql:   `AsDict(AsTuple("foo"u, Int32("42")))`,
+		},
+		{
+			value: VariantValue(Int32Value(42), 1, VariantType{S: StructType{
+				Fields: []StructField{
+					{"foo", TypeString},
+					{"bar", TypeInt32},
+				},
+			}}),
+			human: "bar=42",
+			yql:   "Variant(Int32(\"42\"), \"bar\", Variant<`foo`:String,`bar`:Int32>)",
+		},
+	} {
+		t.Run(test.human, func(t *testing.T) {
+			if act := FormatValue(test.value); act != test.human {
+				t.Errorf("unexpected human-readable value: %s; want %s", act, test.human)
+			}
+			act, err := FormatYQL(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.yql {
+				t.Errorf("unexpected YQL literal: %s; want %s", act, test.yql)
+			}
+		})
+	}
+}
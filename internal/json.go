@@ -0,0 +1,221 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+)
+
+// WriteRowJSON writes JSON object representing the row with given columns to
+// the buf. See WriteValueJSON() for the values representation.
+func WriteRowJSON(buf *bytes.Buffer, cols []*Ydb.Column, row *Ydb.Value) error {
+	buf.WriteByte('{')
+	for i, item := range row.Items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(buf, cols[i].Name)
+		buf.WriteByte(':')
+		if err := writeValueJSON(buf, TypeFromYDB(cols[i].Type), item); err != nil {
+			return fmt.Errorf("ydb: column %q: %v", cols[i].Name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// WriteValueJSON writes JSON representation of v to the buf.
+//
+// Numeric values are written as JSON numbers (Interval as number of
+// microseconds); NULL and Void values as null; Date, Datetime and Timestamp
+// as RFC3339 strings; String as base64 string; Json as is; Decimal and Uuid
+// as strings. Lists and tuples are written as arrays, structs as objects and
+// variants as objects with the single field. Dicts with Utf8 keys are written
+// as objects while other dicts are written as arrays of [key, value] pairs.
+func WriteValueJSON(buf *bytes.Buffer, v V) error {
+	x := v.toYDB()
+	return writeValueJSON(buf, TypeFromYDB(x.Type), x.Value)
+}
+
+// FormatRow returns human-readable representation of the row with given
+// columns. See FormatValue() for details.
+func FormatRow(cols []*Ydb.Column, row *Ydb.Value) string {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range row.Items {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(cols[i].Name)
+		buf.WriteString(": ")
+		formatValue(&buf, TypeFromYDB(cols[i].Type), item)
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+func writeValueJSON(buf *bytes.Buffer, t T, v *Ydb.Value) error {
+	if _, ok := v.Value.(*Ydb.Value_NullFlagValue); ok {
+		buf.WriteString("null")
+		return nil
+	}
+	switch x := t.(type) {
+	case PrimitiveType:
+		return writePrimitiveJSON(buf, x, v)
+
+	case DecimalType:
+		writeJSONString(buf, formatDecimal(x, v))
+
+	case OptionalType:
+		if n, ok := v.Value.(*Ydb.Value_NestedValue); ok {
+			v = n.NestedValue
+		}
+		return writeValueJSON(buf, x.T, v)
+
+	case ListType:
+		buf.WriteByte('[')
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeValueJSON(buf, x.T, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+	case TupleType:
+		buf.WriteByte('[')
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeValueJSON(buf, x.Elems[i], item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+	case StructType:
+		buf.WriteByte('{')
+		for i, item := range v.Items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, x.Fields[i].Name)
+			buf.WriteByte(':')
+			if err := writeValueJSON(buf, x.Fields[i].Type, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+	case DictType:
+		object := x.Key == TypeUTF8
+		if object {
+			buf.WriteByte('{')
+		} else {
+			buf.WriteByte('[')
+		}
+		for i, pair := range v.Pairs {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if object {
+				writeJSONString(buf, pair.Key.GetTextValue())
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte('[')
+				if err := writeValueJSON(buf, x.Key, pair.Key); err != nil {
+					return err
+				}
+				buf.WriteByte(',')
+			}
+			if err := writeValueJSON(buf, x.Payload, pair.Payload); err != nil {
+				return err
+			}
+			if !object {
+				buf.WriteByte(']')
+			}
+		}
+		if object {
+			buf.WriteByte('}')
+		} else {
+			buf.WriteByte(']')
+		}
+
+	case VariantType:
+		name, t := x.item(int(v.VariantIndex))
+		buf.WriteByte('{')
+		writeJSONString(buf, name)
+		buf.WriteByte(':')
+		if err := writeValueJSON(buf, t, v.GetNestedValue()); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+
+	default:
+		return fmt.Errorf("could not marshal value of type %T to JSON", t)
+	}
+	return nil
+}
+
+func writePrimitiveJSON(buf *bytes.Buffer, t PrimitiveType, v *Ydb.Value) error {
+	switch t {
+	case TypeBool:
+		buf.WriteString(strconv.FormatBool(v.GetBoolValue()))
+	case TypeInt8, TypeInt16, TypeInt32:
+		buf.WriteString(strconv.FormatInt(int64(v.GetInt32Value()), 10))
+	case TypeUint8, TypeUint16, TypeUint32:
+		buf.WriteString(strconv.FormatUint(uint64(v.GetUint32Value()), 10))
+	case TypeInt64:
+		buf.WriteString(strconv.FormatInt(v.GetInt64Value(), 10))
+	case TypeUint64:
+		buf.WriteString(strconv.FormatUint(v.GetUint64Value(), 10))
+	case TypeInterval:
+		buf.WriteString(strconv.FormatInt(v.GetInt64Value(), 10))
+	case TypeFloat:
+		return writeJSONFloat(buf, float64(v.GetFloatValue()), 32)
+	case TypeDouble:
+		return writeJSONFloat(buf, v.GetDoubleValue(), 64)
+	case TypeDate:
+		writeJSONString(buf, UnmarshalDate(v.GetUint32Value()).UTC().Format(layoutDate))
+	case TypeDatetime:
+		writeJSONString(buf, UnmarshalDatetime(v.GetUint32Value()).UTC().Format(time.RFC3339))
+	case TypeTimestamp:
+		writeJSONString(buf, UnmarshalTimestamp(v.GetUint64Value()).UTC().Format(time.RFC3339Nano))
+	case TypeTzDate, TypeTzDatetime, TypeTzTimestamp, TypeUTF8, TypeYSON:
+		writeJSONString(buf, v.GetTextValue())
+	case TypeString:
+		writeJSONString(buf, base64.StdEncoding.EncodeToString(v.GetBytesValue()))
+	case TypeJSON:
+		if err := json.Compact(buf, []byte(v.GetTextValue())); err != nil {
+			return err
+		}
+	case TypeUUID:
+		writeJSONString(buf, formatUUID(v))
+	default:
+		return fmt.Errorf("could not marshal value of type %s to JSON", t)
+	}
+	return nil
+}
+
+func writeJSONFloat(buf *bytes.Buffer, f float64, bitSize int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("unsupported float value: %v", f)
+	}
+	buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	// Marshaling of the string never fails.
+	p, _ := json.Marshal(s)
+	buf.Write(p)
+}
@@ -22,6 +22,27 @@ func Columns(s *Scanner, it func(name string, typ internal.T)) {
 	s.columns(it)
 }
 
+// Current returns the current result set and row of the scanner.
+func Current(s *Scanner) (set *Ydb.ResultSet, row *Ydb.Value) {
+	return s.set, s.row
+}
+
+// Item returns type and value of the named column within the current row
+// without affecting scanner position.
+func Item(s *Scanner, name string) (t *Ydb.Type, v *Ydb.Value, ok bool) {
+	if !s.HasItems() {
+		return nil, nil, false
+	}
+	if s.setColumnIndex == nil {
+		s.indexSetColumns()
+	}
+	i, ok := s.setColumnIndex[name]
+	if !ok {
+		return nil, nil, false
+	}
+	return s.set.Columns[i].Type, s.row.Items[i], true
+}
+
 type Scanner struct {
 	set *Ydb.ResultSet
 	row *Ydb.Value
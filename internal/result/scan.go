@@ -0,0 +1,240 @@
+package result
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/decimal"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+// Scan copies values of the current row columns into the values pointed by
+// dst in order of result set columns. Number of dst must be equal to the
+// number of columns.
+//
+// Supported destination types are pointers to bool, int8, uint8, int16,
+// uint16, int32, uint32, int64, uint64, float32, float64, string, []byte,
+// [16]byte (Uuid), time.Time (Date, Datetime, Timestamp and their Tz*
+// variants), time.Duration (Interval), ydb.Value and interface{} (see Any()).
+// Decimal values may be scanned into [16]byte (raw big-endian int128), string
+// (formatted with the scale of the type) and big.Int (unscaled integer).
+// Struct values may be scanned into Go structs as described for
+// ScanStruct().
+//
+// Optional values are unwrapped automatically. NULL values are scanned as
+// zero values, unless destination is a pointer to pointer (e.g. **int32),
+// which is set to nil for NULL and to newly allocated value otherwise.
+// Values of non-optional (NOT NULL) columns may be scanned into pointers to
+// pointers as well; such destinations are always set to non-nil value.
+//
+// Scan returns an error if type of a column does not match the type of its
+// destination. As any other scanning error it breaks the scanner such that
+// Err() returns the same error.
+func (s *Scanner) Scan(dst ...interface{}) error {
+	if !s.HasItems() {
+		s.noValueError()
+		return s.err
+	}
+	if n := len(s.row.Items); n != len(dst) {
+		s.errorf("scan: got %d destinations for %d columns", len(dst), n)
+		return s.err
+	}
+	s.nextItem = 0
+	for _, d := range dst {
+		if !s.NextItem() {
+			break
+		}
+		s.scan(d)
+		if s.err != nil {
+			break
+		}
+	}
+	return s.err
+}
+
+func (s *Scanner) scan(dst interface{}) {
+	if v, ok := dst.(*ydb.Value); ok {
+		*v = s.Value()
+		return
+	}
+	if s.isCurrentTypeOptional() {
+		s.scanOptional(dst)
+		return
+	}
+	switch v := dst.(type) {
+	case *bool:
+		*v = s.Bool()
+	case *int8:
+		*v = s.Int8()
+	case *uint8:
+		*v = s.Uint8()
+	case *int16:
+		*v = s.Int16()
+	case *uint16:
+		*v = s.Uint16()
+	case *int32:
+		*v = s.Int32()
+	case *uint32:
+		*v = s.Uint32()
+	case *int64:
+		*v = s.Int64()
+	case *uint64:
+		*v = s.Uint64()
+	case *float32:
+		*v = s.Float()
+	case *float64:
+		*v = s.Double()
+	case *[16]byte:
+		if s.isCurrentTypeDecimal() {
+			*v, _, _ = s.UnwrapDecimal()
+		} else {
+			*v = s.UUID()
+		}
+	case *big.Int:
+		if s.isCurrentTypeDecimal() {
+			x, precision, scale := s.UnwrapDecimal()
+			v.Set(decimal.FromInt128(x, precision, scale))
+		} else {
+			s.scanTypeError("decimal")
+		}
+	case *time.Duration:
+		*v = internal.UnmarshalInterval(s.Interval())
+	case *time.Time:
+		*v = s.time()
+	case *string:
+		*v = s.string()
+	case *[]byte:
+		*v = s.byteString()
+	case *interface{}:
+		*v = s.Any()
+	default:
+		p := reflect.ValueOf(dst)
+		if p.Kind() == reflect.Ptr && !p.IsNil() && p.Elem().Kind() == reflect.Struct {
+			s.scanStruct(p.Elem())
+			return
+		}
+		if p.Kind() == reflect.Ptr && !p.IsNil() && p.Elem().Kind() == reflect.Ptr {
+			// Destination is a pointer to pointer while the value is not
+			// optional (NOT NULL). It is never set to nil.
+			x := reflect.New(p.Elem().Type().Elem())
+			s.scan(x.Interface())
+			if s.err == nil {
+				p.Elem().Set(x)
+			}
+			return
+		}
+		s.errorf("scan: unsupported destination type %T at %q", dst, s.Path())
+	}
+}
+
+func (s *Scanner) scanOptional(dst interface{}) {
+	p := reflect.ValueOf(dst)
+	if p.Kind() != reflect.Ptr || p.IsNil() {
+		s.errorf("scan: destination is not a pointer: %T", dst)
+		return
+	}
+	e := p.Elem()
+	if s.isNull() {
+		e.Set(reflect.Zero(e.Type()))
+		return
+	}
+	s.Unwrap()
+	if e.Kind() == reflect.Ptr {
+		// Destination is a pointer to pointer to the value.
+		x := reflect.New(e.Type().Elem())
+		s.scan(x.Interface())
+		if s.err == nil {
+			e.Set(x)
+		}
+		return
+	}
+	s.scan(dst)
+}
+
+func (s *Scanner) time() (t time.Time) {
+	if s.err != nil {
+		return
+	}
+	switch s.stack.current().t.GetTypeId() {
+	case Ydb.Type_DATE:
+		return internal.UnmarshalDate(s.uint32())
+	case Ydb.Type_DATETIME:
+		return internal.UnmarshalDatetime(s.uint32())
+	case Ydb.Type_TIMESTAMP:
+		return internal.UnmarshalTimestamp(s.uint64())
+	case Ydb.Type_TZ_DATE:
+		t, err := internal.UnmarshalTzDate(s.text())
+		s.timeError(err)
+		return t
+	case Ydb.Type_TZ_DATETIME:
+		t, err := internal.UnmarshalTzDatetime(s.text())
+		s.timeError(err)
+		return t
+	case Ydb.Type_TZ_TIMESTAMP:
+		t, err := internal.UnmarshalTzTimestamp(s.text())
+		s.timeError(err)
+		return t
+	default:
+		s.scanTypeError("date or time")
+		return
+	}
+}
+
+func (s *Scanner) scanTypeError(exp string) {
+	s.errorf(
+		"unexpected type during scan at %q %s; want %s type",
+		s.Path(), s.Type(), exp,
+	)
+}
+
+func (s *Scanner) timeError(err error) {
+	if err != nil {
+		s.errorf("scan: bad time value at %q: %v", s.Path(), err)
+	}
+}
+
+func (s *Scanner) string() (v string) {
+	if s.err != nil {
+		return
+	}
+	if s.isCurrentTypeDecimal() {
+		x, precision, scale := s.UnwrapDecimal()
+		return decimal.Format(decimal.FromInt128(x, precision, scale), precision, scale)
+	}
+	switch s.stack.current().t.GetTypeId() {
+	case Ydb.Type_STRING:
+		return string(s.bytes())
+	case
+		Ydb.Type_UTF8,
+		Ydb.Type_YSON,
+		Ydb.Type_JSON,
+		Ydb.Type_TZ_DATE,
+		Ydb.Type_TZ_DATETIME,
+		Ydb.Type_TZ_TIMESTAMP:
+		return s.text()
+	default:
+		s.scanTypeError("string")
+		return
+	}
+}
+
+func (s *Scanner) byteString() (v []byte) {
+	if s.err != nil {
+		return
+	}
+	switch s.stack.current().t.GetTypeId() {
+	case Ydb.Type_STRING:
+		return s.bytes()
+	case
+		Ydb.Type_UTF8,
+		Ydb.Type_YSON,
+		Ydb.Type_JSON:
+		return []byte(s.text())
+	default:
+		s.scanTypeError("string")
+		return
+	}
+}
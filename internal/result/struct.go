@@ -0,0 +1,136 @@
+package result
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal/naming"
+)
+
+// ScanStruct copies values of the current row columns into the fields of the
+// struct pointed by dst.
+//
+// Columns are mapped to the exported struct fields by name, which is the
+// field name in snake case by default. It may be changed with the same "ydb"
+// field tag used by the yql package and the ydbgen tool: "column" key sets
+// the column name and "-" skips the field:
+//
+//     type Series struct {
+//         ID      uint64     `ydb:"column:series_id"`
+//         Title   string
+//         Info    *string
+//         Release time.Time
+//         Cache   []byte     `ydb:"-"`
+//     }
+//
+//     var s Series
+//     for res.NextRow() {
+//         if err := res.ScanStruct(&s); err != nil {
+//             return err
+//         }
+//     }
+//
+// Field values are scanned as described for Scan(). Struct columns are
+// scanned recursively into the fields of struct types. Columns which have no
+// corresponding field are ignored; fields which have no corresponding column
+// are left untouched.
+func (s *Scanner) ScanStruct(dst interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	p := reflect.ValueOf(dst)
+	if p.Kind() != reflect.Ptr || p.IsNil() || p.Elem().Kind() != reflect.Struct {
+		s.errorf("scan: destination is not a pointer to struct: %T", dst)
+		return s.err
+	}
+	if !s.HasItems() {
+		s.noValueError()
+		return s.err
+	}
+	fields, err := structFields(p.Elem().Type())
+	if err != nil {
+		s.errorf("scan: %v", err)
+		return s.err
+	}
+	s.nextItem = 0
+	for s.HasNextItem() {
+		s.NextItem()
+		i, ok := fields[s.stack.current().name]
+		if !ok {
+			continue
+		}
+		s.scan(p.Elem().FieldByIndex(i).Addr().Interface())
+		if s.err != nil {
+			break
+		}
+	}
+	return s.err
+}
+
+// scanStruct scans current item of Struct type into v.
+func (s *Scanner) scanStruct(v reflect.Value) {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		s.errorf("scan: %v", err)
+		return
+	}
+	n := s.StructIn()
+	for i := 0; i < n && s.err == nil; i++ {
+		name := s.StructField(i)
+		if j, ok := fields[name]; ok {
+			s.scan(v.FieldByIndex(j).Addr().Interface())
+		}
+	}
+	s.StructOut()
+}
+
+// structFieldsCache maps struct type to the map of column names to field
+// indices.
+var structFieldsCache sync.Map
+
+func structFields(t reflect.Type) (map[string][]int, error) {
+	if m, ok := structFieldsCache.Load(t); ok {
+		return m.(map[string][]int), nil
+	}
+	m := make(map[string][]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+		name := naming.CamelToSnake(f.Name)
+		if tag, ok := f.Tag.Lookup("ydb"); ok {
+			var skip bool
+			for _, pair := range strings.Split(tag, ",") {
+				switch key, value := splitPair(pair, ':'); key {
+				case "-":
+					skip = true
+				case "column":
+					name = value
+				}
+			}
+			if skip {
+				continue
+			}
+		}
+		if _, has := m[name]; has {
+			return nil, fmt.Errorf(
+				"duplicate column %q for fields of %s", name, t,
+			)
+		}
+		m[name] = f.Index
+	}
+	structFieldsCache.Store(t, m)
+	return m, nil
+}
+
+func splitPair(p string, sep byte) (key, value string) {
+	i := strings.IndexByte(p, sep)
+	if i == -1 {
+		return p, ""
+	}
+	return p[:i], p[i+1:]
+}
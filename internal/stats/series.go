@@ -3,27 +3,74 @@ package stats
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// bucket contains data accumulated during one span of series window.
+// Its fields are accessed atomically.
 type bucket struct {
-	sum float64
-	cnt int64
+	// epoch is the number of span since series start plus one. Zero epoch
+	// means that bucket was never used.
+	epoch int64
+	cnt   int64
+	sum   uint64 // Bits of float64 sum.
 }
 
-func (b *bucket) add(x bucket) {
-	b.sum += x.sum
-	b.cnt += x.cnt
+func (b *bucket) load(epoch int64) (sum float64, cnt int64, ok bool) {
+	if atomic.LoadInt64(&b.epoch) != epoch+1 {
+		return 0, 0, false
+	}
+	sum = math.Float64frombits(atomic.LoadUint64(&b.sum))
+	cnt = atomic.LoadInt64(&b.cnt)
+	return sum, cnt, true
+}
+
+// acquire prepares bucket to accumulate data of given epoch.
+// It returns false if bucket is already used by the later epoch.
+func (b *bucket) acquire(epoch int64) bool {
+	for {
+		e := atomic.LoadInt64(&b.epoch)
+		switch {
+		case e == epoch+1:
+			return true
+		case e > epoch+1:
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&b.epoch, e, epoch+1) {
+			atomic.StoreUint64(&b.sum, 0)
+			atomic.StoreInt64(&b.cnt, 0)
+			return true
+		}
+	}
+}
+
+func (b *bucket) add(x float64) {
+	for {
+		old := atomic.LoadUint64(&b.sum)
+		sum := math.Float64bits(math.Float64frombits(old) + x)
+		if atomic.CompareAndSwapUint64(&b.sum, old, sum) {
+			break
+		}
+	}
+	atomic.AddInt64(&b.cnt, 1)
 }
 
 // Series contains logic of accumulating time series data.
+//
+// Series is safe for use by multiple goroutines simultaneously and does not
+// use locks for Add() and Get() calls. Note that values added concurrently with
+// the start of the new span may be lost.
 type Series struct {
-	total   bucket
-	current bucket
+	// buckets is a ring of n+1 buckets: n buckets of the window and the
+	// current one.
 	buckets []bucket
+	span    int64
 
-	duration time.Duration
-	touched  time.Time
+	once  sync.Once
+	start time.Time
 }
 
 // NewSeries creates time series aggregation with d window size and granularity
@@ -38,88 +85,84 @@ func NewSeries(d time.Duration, n int) *Series {
 	if n <= 0 {
 		panic("ydb: internal: stats: zero or negative granularity for series")
 	}
+	span := int64(d) / int64(n)
+	if span == 0 {
+		span = 1
+	}
 	return &Series{
-		buckets:  make([]bucket, n),
-		duration: d,
+		buckets: make([]bucket, n+1),
+		span:    span,
 	}
 }
 
 // Add adds given value x at the time moment of now.
 func (s *Series) Add(now time.Time, x float64) {
-	s.rotate(now)
-	s.current.add(bucket{
-		sum: x,
-		cnt: 1,
-	})
+	e, ok := s.epoch(now)
+	if !ok {
+		return
+	}
+	b := s.bucket(e)
+	if b.acquire(e) {
+		b.add(x)
+	}
 }
 
 // Get returns accumulated data available at the moment of now.
+// That is, data accumulated during the window preceding the current span.
 func (s *Series) Get(now time.Time) (sum float64, cnt int64) {
-	s.rotate(now)
-	return s.total.sum, s.total.cnt
+	e, ok := s.epoch(now)
+	if !ok {
+		return 0, 0
+	}
+	n := int64(len(s.buckets) - 1)
+	for i := e - n; i < e; i++ {
+		if i < 0 {
+			continue
+		}
+		if bs, bc, ok := s.bucket(i).load(i); ok {
+			sum += bs
+			cnt += bc
+		}
+	}
+	return sum, cnt
 }
 
 // SumPer returns rate of accumulated data available at the moment of now.
 func (s *Series) SumPer(now time.Time, period time.Duration) float64 {
 	sum, _ := s.Get(now)
-	return sum * float64(period) / float64(s.duration)
+	n := int64(len(s.buckets) - 1)
+	return sum * float64(period) / float64(s.span*n)
 }
 
-func (s *Series) rotate(now time.Time) {
-	if s.touched.IsZero() {
-		s.touched = now
-		return
-	}
-
-	var (
-		d     = now.Sub(s.touched) // Elapsed duration.
-		n     = len(s.buckets)
-		span  = s.duration / time.Duration(n)
-		shift = int(d / span)
-	)
-	if shift == 0 {
-		return
-	}
-
-	// Slide window strictly on the span size grid.
-	s.touched = s.touched.Add(time.Duration(shift) * span)
-
-	if shift > n {
-		s.reset()
-		return
+// epoch returns the number of span which contains now. Spans are counted
+// from the moment of the first series usage.
+func (s *Series) epoch(now time.Time) (int64, bool) {
+	s.once.Do(func() {
+		s.start = now
+	})
+	d := int64(now.Sub(s.start))
+	if d < 0 {
+		return 0, false
 	}
+	return d / s.span, true
+}
 
-	current := s.current
-	s.current = bucket{}
-	s.total = current
-
-	for i := n - 1; i > 0; i-- {
-		var prev bucket
-		if i-shift >= 0 {
-			prev = s.buckets[i-shift]
-		}
-		s.total.add(prev)
-		s.buckets[i] = prev
-	}
-	s.buckets[shift-1] = current
+func (s *Series) bucket(epoch int64) *bucket {
+	return &s.buckets[epoch%int64(len(s.buckets))]
 }
 
 func (s *Series) String() string {
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "%0.f %d ", s.current.sum, s.current.cnt)
-	for i, b := range s.buckets {
+	for i := range s.buckets {
+		b := &s.buckets[i]
 		if i > 0 {
 			fmt.Fprint(&buf, ", ")
 		}
-		fmt.Fprintf(&buf, "[%0.f %d]", b.sum, b.cnt)
+		fmt.Fprintf(&buf, "[%d: %0.f %d]",
+			atomic.LoadInt64(&b.epoch)-1,
+			math.Float64frombits(atomic.LoadUint64(&b.sum)),
+			atomic.LoadInt64(&b.cnt),
+		)
 	}
-	fmt.Fprintf(&buf, " = %0.f %d\n", s.total.sum, s.total.cnt)
 	return buf.String()
 }
-
-func (s *Series) reset() {
-	s.current = bucket{}
-	for i := range s.buckets {
-		s.buckets[i] = bucket{}
-	}
-}
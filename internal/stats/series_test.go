@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
@@ -123,3 +124,41 @@ func TestSeriesRareEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestSeriesConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		n       = 1000
+	)
+	var (
+		s   = NewSeries(4*time.Second, 4)
+		now = time.Unix(0, 0)
+		wg  sync.WaitGroup
+	)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				s.Add(now, 1)
+				s.Get(now)
+			}
+		}()
+	}
+	wg.Wait()
+
+	sum, cnt := s.Get(now.Add(time.Second))
+	if sum != workers*n || cnt != workers*n {
+		t.Errorf("unexpected result: %v %v; want %v", sum, cnt, workers*n)
+	}
+}
+
+func BenchmarkSeriesAdd(b *testing.B) {
+	s := NewSeries(time.Minute, 12)
+	now := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Add(now, 1)
+		}
+	})
+}
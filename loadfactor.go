@@ -0,0 +1,97 @@
+package ydb
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLoadFactorMinWeight is the default minimum weight of an endpoint
+// used by the BalancingLoadFactor balancing method.
+const DefaultLoadFactorMinWeight = 0.1
+
+// LoadFactorConfig contains configuration of the BalancingLoadFactor
+// balancing method.
+type LoadFactorConfig struct {
+	// MinWeight is the minimum weight of an endpoint in range (0, 1]. That
+	// is, fully loaded endpoints are still picked MinWeight times as often
+	// as idle ones, such that they do not become starved of requests after
+	// the load goes down.
+	// If MinWeight is zero then the DefaultLoadFactorMinWeight is used.
+	MinWeight float64
+}
+
+// loadFactorBalancer implements balancing algorithm which picks connection at
+// random with probability proportional to its weight. Weight is computed
+// from the connection's load factor (obtained by discovery routine) as
+// 1 - loadFactor but not less than MinWeight.
+//
+// Weights are recomputed on each Insert(), Update() and Remove() call, that
+// is, after every discovery round.
+type loadFactorBalancer struct {
+	MinWeight float64
+	Source    rand.Source64
+
+	once sync.Once
+	rand *rand.Rand
+
+	conns connList
+	sums  []float64 // Cumulative weights of conns.
+}
+
+func (b *loadFactorBalancer) init() {
+	b.once.Do(func() {
+		if b.Source == nil {
+			b.Source = rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+		}
+		b.rand = rand.New(&lockedSource{src: b.Source})
+	})
+}
+
+func (b *loadFactorBalancer) Next() *conn {
+	b.init()
+	n := len(b.conns)
+	if n == 0 {
+		return nil
+	}
+	x := b.rand.Float64() * b.sums[n-1]
+	i := sort.SearchFloat64s(b.sums, x)
+	if i == n {
+		i = n - 1
+	}
+	return b.conns[i].conn
+}
+
+func (b *loadFactorBalancer) Insert(conn *conn, info connInfo) balancerElement {
+	e := b.conns.Insert(conn, info)
+	b.reweight()
+	return e
+}
+
+func (b *loadFactorBalancer) Update(x balancerElement, info connInfo) {
+	x.(*connListElement).info = info
+	b.reweight()
+}
+
+func (b *loadFactorBalancer) Remove(x balancerElement) {
+	b.conns.Remove(x.(*connListElement))
+	b.reweight()
+}
+
+func (b *loadFactorBalancer) reweight() {
+	min := b.MinWeight
+	if min <= 0 {
+		min = DefaultLoadFactorMinWeight
+	}
+	b.sums = b.sums[:0]
+	var sum float64
+	for _, x := range b.conns {
+		w := 1 - float64(x.info.loadFactor)
+		if w < min {
+			w = min
+		}
+		sum += w
+		b.sums = append(b.sums, sum)
+	}
+}
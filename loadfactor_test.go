@@ -0,0 +1,47 @@
+package ydb
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLoadFactorBalancer(t *testing.T) {
+	b := &loadFactorBalancer{
+		Source: rand.NewSource(0).(rand.Source64),
+	}
+	if c := b.Next(); c != nil {
+		t.Fatalf("unexpected connection from empty balancer")
+	}
+	var (
+		idle   = new(conn)
+		loaded = new(conn)
+	)
+	b.Insert(idle, connInfo{loadFactor: 0})
+	el := b.Insert(loaded, connInfo{loadFactor: 0.5})
+
+	const n = 3000
+	assertShare := func(exp float64) {
+		t.Helper()
+		var k int
+		for i := 0; i < n; i++ {
+			if b.Next() == loaded {
+				k++
+			}
+		}
+		if act := float64(k) / n; act < exp-0.05 || act > exp+0.05 {
+			t.Errorf("unexpected share of loaded endpoint: %.3f; want %.3f", act, exp)
+		}
+	}
+	assertShare(1.0 / 3)
+
+	// Overloaded endpoint must still be picked with the minimum weight.
+	b.Update(el, connInfo{loadFactor: 2})
+	assertShare(DefaultLoadFactorMinWeight / (1 + DefaultLoadFactorMinWeight))
+
+	b.Remove(el)
+	for i := 0; i < 10; i++ {
+		if c := b.Next(); c != idle {
+			t.Fatalf("unexpected connection: %p; want %p", c, idle)
+		}
+	}
+}
@@ -3,24 +3,31 @@ package ydb
 import (
 	"context"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc/metadata"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
 )
 
 const (
 	metaDatabase = "x-ydb-database"
 	metaTicket   = "x-ydb-auth-ticket"
+	metaTraceID  = "x-ydb-trace-id"
 )
 
 type meta struct {
 	trace       DriverTrace
+	events      *eventBus
 	credentials Credentials
 	database    string
+	clock       timeutil.Clock
 
-	once  sync.Once
-	mu    sync.RWMutex
-	token string
-	curr  metadata.MD
+	once    sync.Once
+	mu      sync.RWMutex
+	token   string
+	tokenAt time.Time // Time when token was received for the first time.
+	curr    metadata.MD
 }
 
 func (m *meta) init() {
@@ -38,11 +45,26 @@ func (m *meta) md(ctx context.Context) (md metadata.MD, _ error) {
 		return m.curr, nil
 	}
 
+	var (
+		start = m.now()
+		src   = m.credentials
+		token string
+		err   error
+	)
 	m.trace.getCredentialsStart(ctx)
-	token, err := m.credentials.Token(ctx)
+	if s, ok := m.credentials.(sourceCredentials); ok {
+		token, src, err = s.sourceToken(ctx)
+	} else {
+		token, err = m.credentials.Token(ctx)
+	}
+	latency := m.now().Sub(start)
 	defer func() {
 		_, withToken := md[metaTicket]
-		m.trace.getCredentialsDone(ctx, withToken, err)
+		var age, ttl time.Duration
+		if withToken {
+			age, ttl = m.tokenLifetime()
+		}
+		m.trace.getCredentialsDone(ctx, src, withToken, latency, age, ttl, err)
 	}()
 
 	switch err {
@@ -76,6 +98,10 @@ func (m *meta) md(ctx context.Context) (md metadata.MD, _ error) {
 		return m.curr, nil
 	}
 	m.token = token
+	m.tokenAt = m.now()
+	m.events.publish(Event{
+		Type: EventCredentialsRefreshed,
+	})
 
 	m.curr = make(metadata.MD, 2)
 	m.curr.Set(metaDatabase, m.database)
@@ -83,3 +109,42 @@ func (m *meta) md(ctx context.Context) (md metadata.MD, _ error) {
 
 	return m.curr, nil
 }
+
+func (m *meta) now() time.Time {
+	return timeutil.ClockOrDefault(m.clock).Now()
+}
+
+// tokenLifetime returns age of the current token and its remaining lifetime,
+// if credentials implement TokenExpirer.
+func (m *meta) tokenLifetime() (age, ttl time.Duration) {
+	now := m.now()
+	m.mu.RLock()
+	if !m.tokenAt.IsZero() {
+		age = now.Sub(m.tokenAt)
+	}
+	m.mu.RUnlock()
+	if e, ok := m.credentials.(TokenExpirer); ok {
+		if exp := e.TokenExpiresAt(); !exp.IsZero() {
+			ttl = exp.Sub(now)
+		}
+	}
+	return age, ttl
+}
+
+// invalidate makes credentials to obtain a new token on the next md() call.
+// It reports whether credentials support invalidation and there was a token
+// to invalidate.
+func (m *meta) invalidate() bool {
+	c, ok := m.credentials.(TokenInvalidator)
+	if !ok {
+		return false
+	}
+	m.mu.RLock()
+	token := m.token
+	m.mu.RUnlock()
+	if token == "" {
+		return false
+	}
+	c.InvalidateToken(token)
+	return true
+}
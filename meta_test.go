@@ -2,9 +2,14 @@ package ydb
 
 import (
 	"context"
+	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/metadata"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
 )
 
 func TestMetaErrDropToken(t *testing.T) {
@@ -78,3 +83,145 @@ func assertMetaHasNoToken(t *testing.T, md metadata.MD) {
 		t.Errorf("unexpected token info in meta")
 	}
 }
+
+type invalidatingCredentials struct {
+	token       int
+	invalidated []string
+}
+
+func (c *invalidatingCredentials) Token(context.Context) (string, error) {
+	return "token" + strconv.Itoa(c.token), nil
+}
+
+func (c *invalidatingCredentials) InvalidateToken(token string) {
+	c.invalidated = append(c.invalidated, token)
+	c.token++
+}
+
+func TestMetaInvalidate(t *testing.T) {
+	if (&meta{credentials: AuthTokenCredentials{AuthToken: "token"}}).invalidate() {
+		t.Fatalf("unexpected invalidation of static credentials")
+	}
+
+	c := new(invalidatingCredentials)
+	m := &meta{
+		database:    "database",
+		credentials: c,
+	}
+	if m.invalidate() {
+		t.Fatalf("unexpected invalidation before token obtained")
+	}
+	md1, err := m.md(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.invalidate() {
+		t.Fatalf("token is not invalidated")
+	}
+	if act, exp := c.invalidated, []string{"token0"}; !reflect.DeepEqual(act, exp) {
+		t.Fatalf("unexpected invalidated tokens: %v; want %v", act, exp)
+	}
+	md2, err := m.md(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := md1.Get(metaTicket), []string{"token0"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected first token: %v; want %v", act, exp)
+	}
+	if act, exp := md2.Get(metaTicket), []string{"token1"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected second token: %v; want %v", act, exp)
+	}
+}
+
+type expiringCredentials struct {
+	shift   func(time.Duration)
+	expires time.Time
+}
+
+func (c expiringCredentials) Token(context.Context) (string, error) {
+	c.shift(time.Second)
+	return "token", nil
+}
+
+func (c expiringCredentials) TokenExpiresAt() time.Time {
+	return c.expires
+}
+
+func TestMetaTokenLifetime(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := timetest.NewClock(start)
+
+	var info []GetCredentialsDoneInfo
+	m := &meta{
+		database: "database",
+		clock:    clock,
+		credentials: expiringCredentials{
+			shift:   clock.Shift,
+			expires: start.Add(time.Minute),
+		},
+		trace: DriverTrace{
+			GetCredentialsDone: func(x GetCredentialsDoneInfo) {
+				info = append(info, x)
+			},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := m.md(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		clock.Shift(10 * time.Second)
+	}
+	if n := len(info); n != 2 {
+		t.Fatalf("unexpected number of trace calls: %d", n)
+	}
+	for i, exp := range []struct {
+		age time.Duration
+		ttl time.Duration
+	}{
+		{0, 59 * time.Second},
+		{11 * time.Second, 48 * time.Second},
+	} {
+		x := info[i]
+		if x.Latency != time.Second {
+			t.Errorf("#%d: unexpected latency: %s", i, x.Latency)
+		}
+		if x.TokenAge != exp.age {
+			t.Errorf("#%d: unexpected token age: %s; want %s", i, x.TokenAge, exp.age)
+		}
+		if x.TokenTTL != exp.ttl {
+			t.Errorf("#%d: unexpected token ttl: %s; want %s", i, x.TokenTTL, exp.ttl)
+		}
+	}
+}
+
+func TestMetaCredentialsSource(t *testing.T) {
+	var (
+		failing = CredentialsFunc(func(context.Context) (string, error) {
+			return "", ErrCredentialsDropToken
+		})
+		static = AuthTokenCredentials{AuthToken: "token"}
+		info   []GetCredentialsDoneInfo
+	)
+	m := &meta{
+		database:    "database",
+		credentials: MultiCredentials(failing, static),
+		trace: DriverTrace{
+			GetCredentialsDone: func(x GetCredentialsDoneInfo) {
+				info = append(info, x)
+			},
+		},
+	}
+	md, err := m.md(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := md.Get(metaTicket), []string{"token"}; !reflect.DeepEqual(act, exp) {
+		t.Fatalf("unexpected ticket: %v; want %v", act, exp)
+	}
+	if n := len(info); n != 1 {
+		t.Fatalf("unexpected number of trace calls: %d", n)
+	}
+	if act, exp := info[0].Credentials, Credentials(static); act != exp {
+		t.Fatalf("unexpected source credentials: %#v; want %#v", act, exp)
+	}
+}
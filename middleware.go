@@ -0,0 +1,57 @@
+package ydb
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+// CallFunc is a function which performs unary operation as Driver.Call() does.
+type CallFunc func(context.Context, internal.Operation) error
+
+// StreamReadFunc is a function which performs streaming operation as
+// Driver.StreamRead() does.
+type StreamReadFunc func(context.Context, internal.StreamOperation) error
+
+// CallMiddleware wraps next CallFunc with some additional logic. It may
+// modify request or context (e.g. add outgoing gRPC metadata) before calling
+// next, inspect its result or not call next at all.
+type CallMiddleware func(next CallFunc) CallFunc
+
+// StreamReadMiddleware wraps next StreamReadFunc with some additional logic.
+// See CallMiddleware for details.
+type StreamReadMiddleware func(next StreamReadFunc) StreamReadFunc
+
+// OperationRequest returns method name and request message of unary operation.
+// Request message may be modified by CallMiddleware before calling next.
+func OperationRequest(op internal.Operation) (method string, req proto.Message) {
+	method, req, _ = internal.Unwrap(op)
+	return method, req
+}
+
+// StreamOperationRequest returns method name and request message of streaming
+// operation.
+// Request message may be modified by StreamReadMiddleware before calling next.
+func StreamOperationRequest(op internal.StreamOperation) (method string, req proto.Message) {
+	method, req, _, _ = internal.UnwrapStreamOperation(op)
+	return method, req
+}
+
+// chainCall returns CallFunc which calls given middlewares in order and then
+// calls f. That is, the first middleware is the outermost one.
+func chainCall(f CallFunc, ms []CallMiddleware) CallFunc {
+	for i := len(ms) - 1; i >= 0; i-- {
+		f = ms[i](f)
+	}
+	return f
+}
+
+// chainStreamRead is the same as chainCall but for streaming operations.
+func chainStreamRead(f StreamReadFunc, ms []StreamReadMiddleware) StreamReadFunc {
+	for i := len(ms) - 1; i >= 0; i-- {
+		f = ms[i](f)
+	}
+	return f
+}
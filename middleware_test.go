@@ -0,0 +1,51 @@
+package ydb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+func TestChainCall(t *testing.T) {
+	var calls []string
+	middleware := func(name string) CallMiddleware {
+		return func(next CallFunc) CallFunc {
+			return func(ctx context.Context, op internal.Operation) error {
+				calls = append(calls, name)
+				_, req := OperationRequest(op)
+				r := req.(*Ydb_Table.ExecuteDataQueryRequest)
+				r.SessionId += name
+				return next(ctx, op)
+			}
+		}
+	}
+	var sessionID string
+	f := chainCall(
+		func(ctx context.Context, op internal.Operation) error {
+			calls = append(calls, "call")
+			_, req := OperationRequest(op)
+			sessionID = req.(*Ydb_Table.ExecuteDataQueryRequest).SessionId
+			return nil
+		},
+		[]CallMiddleware{
+			middleware("a"),
+			middleware("b"),
+		},
+	)
+	req := Ydb_Table.ExecuteDataQueryRequest{
+		SessionId: "id-",
+	}
+	err := f(context.Background(), internal.Wrap("method", &req, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"a", "b", "call"}; !reflect.DeepEqual(calls, exp) {
+		t.Errorf("unexpected calls: %v; want %v", calls, exp)
+	}
+	if exp := "id-ab"; sessionID != exp {
+		t.Errorf("unexpected session id: %q; want %q", sessionID, exp)
+	}
+}
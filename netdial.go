@@ -0,0 +1,190 @@
+package ydb
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// IPFamily describes preferred family of IP addresses to connect to.
+type IPFamily uint
+
+const (
+	// IPFamilyAny means that addresses of the family of the first resolved
+	// address are tried first.
+	IPFamilyAny IPFamily = iota
+	IPFamilyIPv4
+	IPFamilyIPv6
+)
+
+// DefaultFallbackDelay is the default delay before the next connection
+// attempt is started while previous attempts are still in progress.
+const DefaultFallbackDelay = 300 * time.Millisecond
+
+// netDialer dials hosts which may resolve to multiple (possibly both IPv4 and
+// IPv6) addresses in a Happy Eyeballs manner (RFC 8305): addresses are
+// interleaved by family starting with the preferred one and connection
+// attempts are started one by one with fallbackDelay interval (or right after
+// failure of the previous attempt). The first established connection wins.
+//
+// It prevents hanging on unreachable routes (such as IPv6-only routes on hosts
+// without IPv6 connectivity) when no dial timeout is configured.
+type netDialer struct {
+	prefer        IPFamily
+	fallbackDelay time.Duration
+
+	// lookup and dial are optional replacements of default functions, used
+	// mostly for testing purposes.
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+	dial   func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func (d *netDialer) DialContext(ctx context.Context, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips = interleaveIPs(ips, d.prefer)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	var (
+		results = make(chan result, len(ips))
+		next    int
+		pending int
+		first   error
+	)
+	start := func() {
+		addr := net.JoinHostPort(ips[next].String(), port)
+		next++
+		pending++
+		go func() {
+			conn, err := d.dialFunc()(ctx, "tcp", addr)
+			results <- result{conn, err}
+		}()
+	}
+	delay := d.fallbackDelay
+	if delay <= 0 {
+		delay = DefaultFallbackDelay
+	}
+	start()
+	for pending > 0 {
+		var (
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		if next < len(ips) {
+			timer = time.NewTimer(delay)
+			timeout = timer.C
+		}
+		select {
+		case <-timeout:
+			start()
+
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				if timer != nil {
+					timer.Stop()
+				}
+				// Close connections which could be established by other
+				// attempts. Note that ctx is canceled by defer above.
+				go func(n int) {
+					for i := 0; i < n; i++ {
+						if r := <-results; r.conn != nil {
+							_ = r.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, nil
+			}
+			if first == nil {
+				first = r.err
+			}
+			if next < len(ips) {
+				start()
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	return nil, first
+}
+
+func (d *netDialer) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	lookup := d.lookup
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupIPAddr
+	}
+	addrs, err := lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{
+			Err:  "no such host",
+			Name: host,
+		}
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, a := range addrs {
+		ips[i] = a.IP
+	}
+	return ips, nil
+}
+
+func (d *netDialer) dialFunc() func(context.Context, string, string) (net.Conn, error) {
+	if d.dial != nil {
+		return d.dial
+	}
+	var nd net.Dialer
+	return nd.DialContext
+}
+
+// interleaveIPs returns ips ordered such that families alternate starting from
+// the preferred family. Order of addresses within the family is preserved.
+func interleaveIPs(ips []net.IP, prefer IPFamily) []net.IP {
+	var primary, fallback []net.IP
+	for _, ip := range ips {
+		if prefer == IPFamilyAny {
+			prefer = familyOf(ip)
+		}
+		if familyOf(ip) == prefer {
+			primary = append(primary, ip)
+		} else {
+			fallback = append(fallback, ip)
+		}
+	}
+	ret := make([]net.IP, 0, len(ips))
+	for len(primary) > 0 || len(fallback) > 0 {
+		if len(primary) > 0 {
+			ret = append(ret, primary[0])
+			primary = primary[1:]
+		}
+		if len(fallback) > 0 {
+			ret = append(ret, fallback[0])
+			fallback = fallback[1:]
+		}
+	}
+	return ret
+}
+
+func familyOf(ip net.IP) IPFamily {
+	if ip.To4() != nil {
+		return IPFamilyIPv4
+	}
+	return IPFamilyIPv6
+}
@@ -0,0 +1,112 @@
+package ydb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInterleaveIPs(t *testing.T) {
+	var (
+		v4a = net.ParseIP("10.0.0.1")
+		v4b = net.ParseIP("10.0.0.2")
+		v6a = net.ParseIP("::1")
+		v6b = net.ParseIP("::2")
+	)
+	for _, test := range []struct {
+		name   string
+		ips    []net.IP
+		prefer IPFamily
+		exp    []net.IP
+	}{
+		{
+			name: "any",
+			ips:  []net.IP{v6a, v6b, v4a, v4b},
+			exp:  []net.IP{v6a, v4a, v6b, v4b},
+		},
+		{
+			name:   "ipv4",
+			ips:    []net.IP{v6a, v6b, v4a, v4b},
+			prefer: IPFamilyIPv4,
+			exp:    []net.IP{v4a, v6a, v4b, v6b},
+		},
+		{
+			name:   "ipv6 only",
+			ips:    []net.IP{v6a, v6b},
+			prefer: IPFamilyIPv4,
+			exp:    []net.IP{v6a, v6b},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			act := interleaveIPs(test.ips, test.prefer)
+			if !reflect.DeepEqual(act, test.exp) {
+				t.Errorf("unexpected order: %v; want %v", act, test.exp)
+			}
+		})
+	}
+}
+
+func TestNetDialerFallback(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		dialed []string
+	)
+	d := netDialer{
+		prefer:        IPFamilyIPv6,
+		fallbackDelay: time.Millisecond,
+		lookup: func(context.Context, string) ([]net.IPAddr, error) {
+			return []net.IPAddr{
+				{IP: net.ParseIP("10.0.0.1")},
+				{IP: net.ParseIP("::1")},
+			}, nil
+		},
+		dial: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			mu.Lock()
+			dialed = append(dialed, addr)
+			mu.Unlock()
+			if addr == "[::1]:2135" {
+				// Unreachable route.
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			c, _ := net.Pipe()
+			return c, nil
+		},
+	}
+	conn, err := d.DialContext(context.Background(), "ydb:2135")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	exp := []string{"[::1]:2135", "10.0.0.1:2135"}
+	if !reflect.DeepEqual(dialed, exp) {
+		t.Errorf("unexpected dial attempts: %v; want %v", dialed, exp)
+	}
+}
+
+func TestNetDialerError(t *testing.T) {
+	errRefused := errors.New("refused")
+	d := netDialer{
+		fallbackDelay: time.Hour,
+		lookup: func(context.Context, string) ([]net.IPAddr, error) {
+			return []net.IPAddr{
+				{IP: net.ParseIP("10.0.0.1")},
+				{IP: net.ParseIP("10.0.0.2")},
+			}, nil
+		},
+		dial: func(context.Context, string, string) (net.Conn, error) {
+			return nil, errRefused
+		},
+	}
+	_, err := d.DialContext(context.Background(), "ydb:2135")
+	if err != errRefused {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
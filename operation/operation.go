@@ -0,0 +1,195 @@
+// Package operation contains client of the YDB Operation service. It is used
+// to poll, cancel and forget long-running operations (such as index builds or
+// exports) started in async mode.
+package operation
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/grpc/Ydb_Operation_V1"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// DefaultPollInterval is the default interval between GetOperation() calls
+// made by Client's Wait() method.
+const DefaultPollInterval = time.Second
+
+// Operation describes state of the YDB operation.
+type Operation struct {
+	ID string
+
+	// Ready reports whether operation is completed (successfully or not).
+	Ready bool
+
+	// Status is the status code of completed operation.
+	Status ydb.StatusCode
+	Issues ydb.IssueIterator
+
+	// Result and Metadata are operation specific messages. See
+	// UnmarshalResult() and UnmarshalMetadata().
+	Result   *any.Any
+	Metadata *any.Any
+}
+
+// Err returns non-nil *ydb.OpError if operation is completed unsuccessfully.
+// Issues of the operation are available via Issues field.
+func (o *Operation) Err() error {
+	if !o.Ready || o.Status == ydb.StatusSuccess {
+		return nil
+	}
+	return &ydb.OpError{
+		Reason: o.Status,
+	}
+}
+
+// UnmarshalResult unmarshals result of completed operation into m.
+func (o *Operation) UnmarshalResult(m proto.Message) error {
+	return ptypes.UnmarshalAny(o.Result, m)
+}
+
+// UnmarshalMetadata unmarshals operation metadata (e.g. progress of the
+// index build) into m.
+func (o *Operation) UnmarshalMetadata(m proto.Message) error {
+	return ptypes.UnmarshalAny(o.Metadata, m)
+}
+
+func (o *Operation) from(y *Ydb_Operations.Operation) {
+	*o = Operation{
+		ID:       y.Id,
+		Ready:    y.Ready,
+		Status:   ydb.StatusCode(y.Status),
+		Issues:   ydb.IssueIterator(y.Issues),
+		Result:   y.Result,
+		Metadata: y.Metadata,
+	}
+}
+
+// WithAsync returns a copy of parent in which YDB operations are started in
+// async mode (see ydb.WithOperationMode()). When operation is started and is
+// not completed yet, the driver call returns ydb.ErrOperationNotReady and the
+// id of the started operation is passed to the started function. The
+// operation then may be polled with Client.
+func WithAsync(parent context.Context, started func(id string)) context.Context {
+	ctx := ydb.WithOperationMode(parent, ydb.OperationModeAsync)
+	return ydb.WithDriverTrace(ctx, ydb.DriverTrace{
+		OperationDone: func(x ydb.OperationDoneInfo) {
+			if x.Error == ydb.ErrOperationNotReady && x.OpID != "" {
+				started(x.OpID)
+			}
+		},
+	})
+}
+
+// Client contains logic of interacting with the YDB Operation service.
+type Client struct {
+	Driver ydb.Driver
+
+	// PollInterval is the interval between GetOperation() calls made by
+	// Wait().
+	// If PollInterval is zero then the DefaultPollInterval is used.
+	PollInterval time.Duration
+}
+
+// GetOperation returns current state of the operation with given id.
+func (c *Client) GetOperation(ctx context.Context, id string) (op Operation, err error) {
+	var res Ydb_Operations.GetOperationResponse
+	req := Ydb_Operations.GetOperationRequest{
+		Id: id,
+	}
+	err = c.Driver.Call(ctx, internal.Wrap(
+		Ydb_Operation_V1.GetOperation, &req, &res,
+	))
+	if err == nil && res.Operation != nil {
+		op.from(res.Operation)
+	}
+	return op, err
+}
+
+// CancelOperation starts cancellation of the operation with given id.
+// Cancellation is asynchronous; operation state should be polled to find out
+// whether it is completed.
+func (c *Client) CancelOperation(ctx context.Context, id string) error {
+	var res Ydb_Operations.CancelOperationResponse
+	req := Ydb_Operations.CancelOperationRequest{
+		Id: id,
+	}
+	return c.Driver.Call(ctx, internal.Wrap(
+		Ydb_Operation_V1.CancelOperation, &req, &res,
+	))
+}
+
+// ForgetOperation makes server to forget the operation with given id. It
+// does not cancel running operation.
+func (c *Client) ForgetOperation(ctx context.Context, id string) error {
+	var res Ydb_Operations.ForgetOperationResponse
+	req := Ydb_Operations.ForgetOperationRequest{
+		Id: id,
+	}
+	return c.Driver.Call(ctx, internal.Wrap(
+		Ydb_Operation_V1.ForgetOperation, &req, &res,
+	))
+}
+
+// ListOperations returns a page of operations of given kind (e.g.
+// "buildindex" or "export"). Empty pageToken means the first page. The
+// returned next token is empty if there are no more pages.
+func (c *Client) ListOperations(
+	ctx context.Context, kind string, pageSize uint64, pageToken string,
+) (
+	ops []Operation, next string, err error,
+) {
+	var res Ydb_Operations.ListOperationsResponse
+	req := Ydb_Operations.ListOperationsRequest{
+		Kind:      kind,
+		PageSize:  pageSize,
+		PageToken: pageToken,
+	}
+	err = c.Driver.Call(ctx, internal.Wrap(
+		Ydb_Operation_V1.ListOperations, &req, &res,
+	))
+	if err != nil {
+		return nil, "", err
+	}
+	ops = make([]Operation, len(res.Operations))
+	for i, x := range res.Operations {
+		ops[i].from(x)
+	}
+	return ops, res.NextPageToken, nil
+}
+
+// Wait polls the operation with given id until it is completed or ctx is
+// done. It returns the completed operation and its error (see
+// Operation.Err()).
+func (c *Client) Wait(ctx context.Context, id string) (op Operation, err error) {
+	for {
+		op, err = c.GetOperation(ctx, id)
+		if err != nil {
+			return op, err
+		}
+		if op.Ready {
+			return op, op.Err()
+		}
+		t := timeutil.NewTimer(c.pollInterval())
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return op, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) pollInterval() time.Duration {
+	if c.PollInterval == 0 {
+		return DefaultPollInterval
+	}
+	return c.PollInterval
+}
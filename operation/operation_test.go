@@ -0,0 +1,123 @@
+package operation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/grpc/Ydb_Operation_V1"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+type driverFunc func(method string, req, res proto.Message) error
+
+func (f driverFunc) Call(_ context.Context, op internal.Operation) error {
+	return f(internal.Unwrap(op))
+}
+
+func (f driverFunc) StreamRead(context.Context, internal.StreamOperation) error {
+	panic("not implemented")
+}
+
+func (f driverFunc) Close() error {
+	return nil
+}
+
+func TestClientWait(t *testing.T) {
+	timerC := make(chan time.Time)
+	cleanup := timeutil.StubTestHookNewTimer(func(time.Duration) timeutil.Timer {
+		return timetest.Timer{Ch: timerC}
+	})
+	defer cleanup()
+
+	result, err := ptypes.MarshalAny(&Ydb_Operations.GetOperationRequest{
+		Id: "result",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	polls := 0
+	c := Client{
+		Driver: driverFunc(func(method string, req, res proto.Message) error {
+			if method != Ydb_Operation_V1.GetOperation {
+				t.Fatalf("unexpected method: %q", method)
+			}
+			if id := req.(*Ydb_Operations.GetOperationRequest).Id; id != "op" {
+				t.Fatalf("unexpected operation id: %q", id)
+			}
+			polls++
+			res.(*Ydb_Operations.GetOperationResponse).Operation = &Ydb_Operations.Operation{
+				Id:     "op",
+				Ready:  polls == 2,
+				Status: Ydb.StatusIds_SUCCESS,
+				Result: result,
+			}
+			return nil
+		}),
+	}
+
+	done := make(chan Operation)
+	go func() {
+		op, err := c.Wait(context.Background(), "op")
+		if err != nil {
+			t.Error(err)
+		}
+		done <- op
+	}()
+	timerC <- time.Now()
+
+	op := <-done
+	if polls != 2 {
+		t.Fatalf("unexpected number of polls: %d", polls)
+	}
+	var act Ydb_Operations.GetOperationRequest
+	if err := op.UnmarshalResult(&act); err != nil {
+		t.Fatal(err)
+	}
+	if act.Id != "result" {
+		t.Fatalf("unexpected result: %v", &act)
+	}
+}
+
+func TestOperationErr(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		op   Operation
+		exp  ydb.StatusCode
+	}{
+		{
+			name: "not ready",
+			op:   Operation{Status: ydb.StatusOverloaded},
+		},
+		{
+			name: "success",
+			op:   Operation{Ready: true, Status: ydb.StatusSuccess},
+		},
+		{
+			name: "failed",
+			op:   Operation{Ready: true, Status: ydb.StatusOverloaded},
+			exp:  ydb.StatusOverloaded,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.op.Err()
+			if test.exp == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !ydb.IsOpError(err, test.exp) {
+				t.Fatalf("unexpected error: %v; want %v", err, test.exp)
+			}
+		})
+	}
+}
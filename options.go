@@ -0,0 +1,232 @@
+package ydb
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/yandex-cloud/ydb-go-sdk/auth/iam"
+)
+
+// Option is an option of the driver construction used by New().
+type Option func(*options) error
+
+// options is a set of parameters collected by Option functions.
+type options struct {
+	endpoint string
+	dialer   Dialer
+	config   DriverConfig
+}
+
+// New dials the endpoint set by given options and initializes driver instance
+// on success. Options are applied in order, such that later options override
+// parameters set by earlier ones.
+func New(ctx context.Context, opts ...Option) (Driver, error) {
+	var o options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	if o.endpoint == "" {
+		return nil, errors.New("ydb: endpoint is not set")
+	}
+	d := o.dialer
+	d.DriverConfig = &o.config
+	return d.Dial(ctx, o.endpoint)
+}
+
+// Open dials addr and initializes driver instance configured by given options.
+// It is a shorthand for New() with WithEndpoint(addr) passed as the first
+// option:
+//
+//     db, err := ydb.Open(ctx, "grpcs://ydb.example.net",
+//         ydb.WithDatabase("/local"),
+//         ydb.WithCredentials(creds),
+//         ydb.WithBalancer(ydb.BalancingP2C, nil),
+//     )
+func Open(ctx context.Context, addr string, opts ...Option) (Driver, error) {
+	return New(ctx, append([]Option{WithEndpoint(addr)}, opts...)...)
+}
+
+// WithEndpoint returns Option which sets the address to dial. The addr format
+// is the same as for Dialer.Dial().
+func WithEndpoint(addr string) Option {
+	return func(o *options) error {
+		o.endpoint = addr
+		return nil
+	}
+}
+
+// WithDialer returns Option which sets dialer and driver parameters to ones of
+// d. The d and its DriverConfig are copied and not changed.
+func WithDialer(d *Dialer) Option {
+	return func(o *options) error {
+		o.dialer = *d
+		o.dialer.DriverConfig = nil
+		if c := d.DriverConfig; c != nil {
+			o.config = *c
+		} else {
+			o.config = DriverConfig{}
+		}
+		return nil
+	}
+}
+
+// WithDatabase returns Option which sets the database name.
+func WithDatabase(name string) Option {
+	return func(o *options) error {
+		o.config.Database = name
+		return nil
+	}
+}
+
+// WithCredentials returns Option which sets the client credentials.
+func WithCredentials(c Credentials) Option {
+	return func(o *options) error {
+		o.config.Credentials = c
+		return nil
+	}
+}
+
+// WithServiceAccountKeyFileCredentials returns Option which sets the client
+// credentials issuing IAM tokens of the service account. Tokens are obtained
+// by the exchange of JWT signed by the service account authorized key, which
+// is read from the JSON file with given name. Tokens are cached and renewed
+// well before their expiration. See iam.NewClientFromKeyFile() for details.
+func WithServiceAccountKeyFileCredentials(name string) Option {
+	return func(o *options) error {
+		c, err := iam.NewClientFromKeyFile(name)
+		if err != nil {
+			return fmt.Errorf("ydb: service account key file: %v", err)
+		}
+		o.config.Credentials = c
+		return nil
+	}
+}
+
+// WithBalancer returns Option which sets the balancing method and its
+// optional configuration. See DriverConfig's BalancingMethod and
+// BalancingConfig fields for details.
+func WithBalancer(m BalancingMethod, config interface{}) Option {
+	return func(o *options) error {
+		o.config.BalancingMethod = m
+		o.config.BalancingConfig = config
+		return nil
+	}
+}
+
+// WithCustomBalancer returns Option which makes driver to use given
+// user defined balancing algorithm. See DriverConfig's Balancer field for
+// details.
+func WithCustomBalancer(b Balancer) Option {
+	return func(o *options) error {
+		o.config.Balancer = b
+		return nil
+	}
+}
+
+// WithTLSConfig returns Option which makes driver to use TLS with given
+// configuration.
+func WithTLSConfig(c *tls.Config) Option {
+	return func(o *options) error {
+		o.dialer.TLSConfig = c
+		return nil
+	}
+}
+
+// WithDialTimeout returns Option which sets the maximum amount of time a dial
+// will wait for a connect to complete.
+func WithDialTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		o.dialer.Timeout = d
+		return nil
+	}
+}
+
+// WithTrace returns Option which composes given trace with the trace set by
+// previous options.
+func WithTrace(t DriverTrace) Option {
+	return func(o *options) error {
+		o.config.Trace = composeDriverTrace(o.config.Trace, t)
+		return nil
+	}
+}
+
+// WithRequestTimeout returns Option which sets the DriverConfig's
+// RequestTimeout field.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		o.config.RequestTimeout = d
+		return nil
+	}
+}
+
+// WithStreamTimeout returns Option which sets the DriverConfig's
+// StreamTimeout field.
+func WithStreamTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		o.config.StreamTimeout = d
+		return nil
+	}
+}
+
+// WithStreamIdleTimeout returns Option which sets the DriverConfig's
+// StreamIdleTimeout field.
+func WithStreamIdleTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		o.config.StreamIdleTimeout = d
+		return nil
+	}
+}
+
+// WithDiscoveryInterval returns Option which sets the DriverConfig's
+// DiscoveryInterval field.
+func WithDiscoveryInterval(d time.Duration) Option {
+	return func(o *options) error {
+		o.config.DiscoveryInterval = d
+		return nil
+	}
+}
+
+// WithEndpointsCache returns Option which sets the DriverConfig's
+// EndpointsCache field.
+func WithEndpointsCache(c EndpointsCache) Option {
+	return func(o *options) error {
+		o.config.EndpointsCache = c
+		return nil
+	}
+}
+
+// WithCallMiddlewares returns Option which appends given middlewares to ones
+// set by previous options.
+func WithCallMiddlewares(ms ...CallMiddleware) Option {
+	return func(o *options) error {
+		// Note that slice may be shared with the Dialer passed to WithDialer().
+		m := o.config.CallMiddlewares
+		o.config.CallMiddlewares = append(m[:len(m):len(m)], ms...)
+		return nil
+	}
+}
+
+// WithUserAgent returns Option which sets the user agent string sent with
+// every request made by the driver. It is prepended to the gRPC's own user
+// agent.
+func WithUserAgent(ua string) Option {
+	return WithGRPCDialOptions(grpc.WithUserAgent(ua))
+}
+
+// WithGRPCDialOptions returns Option which appends given options to the
+// Dialer's GRPCDialOptions set by previous options.
+func WithGRPCDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) error {
+		// Note that slice may be shared with the Dialer passed to WithDialer().
+		x := o.dialer.GRPCDialOptions
+		o.dialer.GRPCDialOptions = append(x[:len(x):len(x)], opts...)
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package ydb
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	var (
+		traced []string
+		tlsc   = new(tls.Config)
+		mw     = func(next CallFunc) CallFunc {
+			return next
+		}
+		base = &Dialer{
+			DriverConfig: &DriverConfig{
+				Database:        "/dialer",
+				CallMiddlewares: make([]CallMiddleware, 1, 2),
+				Trace: DriverTrace{
+					DialStart: func(DialStartInfo) {
+						traced = append(traced, "dialer")
+					},
+				},
+			},
+		}
+	)
+	var o options
+	for _, opt := range []Option{
+		WithEndpoint("localhost"),
+		WithDialer(base),
+		WithDatabase("/local"),
+		WithCredentials(AuthTokenCredentials{AuthToken: "token"}),
+		WithBalancer(BalancingRoundRobin, nil),
+		WithTLSConfig(tlsc),
+		WithDialTimeout(time.Second),
+		WithRequestTimeout(2 * time.Second),
+		WithStreamTimeout(3 * time.Second),
+		WithDiscoveryInterval(-1),
+		WithCallMiddlewares(mw),
+		WithTrace(DriverTrace{
+			DialStart: func(DialStartInfo) {
+				traced = append(traced, "option")
+			},
+		}),
+	} {
+		if err := opt(&o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if act, exp := o.config.Database, "/local"; act != exp {
+		t.Errorf("unexpected database: %q; want %q", act, exp)
+	}
+	if act, exp := o.config.Credentials, (AuthTokenCredentials{AuthToken: "token"}); act != exp {
+		t.Errorf("unexpected credentials: %#v; want %#v", act, exp)
+	}
+	if act, exp := o.config.BalancingMethod, BalancingRoundRobin; act != exp {
+		t.Errorf("unexpected balancing method: %v; want %v", act, exp)
+	}
+	if o.dialer.TLSConfig != tlsc {
+		t.Errorf("unexpected tls config")
+	}
+	if act, exp := o.dialer.Timeout, time.Second; act != exp {
+		t.Errorf("unexpected dial timeout: %v; want %v", act, exp)
+	}
+	if act, exp := o.config.RequestTimeout, 2*time.Second; act != exp {
+		t.Errorf("unexpected request timeout: %v; want %v", act, exp)
+	}
+	if act, exp := o.config.StreamTimeout, 3*time.Second; act != exp {
+		t.Errorf("unexpected stream timeout: %v; want %v", act, exp)
+	}
+	if act, exp := o.config.DiscoveryInterval, time.Duration(-1); act != exp {
+		t.Errorf("unexpected discovery interval: %v; want %v", act, exp)
+	}
+	if n := len(o.config.CallMiddlewares); n != 2 {
+		t.Errorf("unexpected number of call middlewares: %d; want 2", n)
+	}
+	if base.DriverConfig.CallMiddlewares[:2][1] != nil {
+		t.Errorf("dialer's call middlewares changed")
+	}
+	if base.DriverConfig.Database != "/dialer" {
+		t.Errorf("dialer's config changed")
+	}
+
+	o.config.Trace.dialStart(context.Background(), "")
+	if len(traced) != 2 || traced[0] != "dialer" || traced[1] != "option" {
+		t.Errorf("unexpected trace calls: %v", traced)
+	}
+}
+
+func TestOpenNoEndpoint(t *testing.T) {
+	_, err := Open(context.Background(), "", WithDatabase("/local"))
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestWithServiceAccountKeyFileCredentialsError(t *testing.T) {
+	var o options
+	err := WithServiceAccountKeyFileCredentials("/nonexistent/key.json")(&o)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if o.config.Credentials != nil {
+		t.Errorf("unexpected credentials: %#v", o.config.Credentials)
+	}
+}
@@ -0,0 +1,47 @@
+package ydb
+
+import (
+	"path"
+	"strings"
+)
+
+// JoinPath joins given path elements relative to the database path. Empty
+// elements are ignored. The result is cleaned (see path.Clean()).
+//
+// If the first non-empty element is an absolute path (that is, begins with
+// "/") then database is not used. If database is empty then the result is
+// relative (and is equal to path.Join(elems...)).
+//
+// For example, JoinPath("/ru/home/mydb", "users") returns
+// "/ru/home/mydb/users".
+func JoinPath(database string, elems ...string) string {
+	p := path.Join(elems...)
+	if isAbsPath(p) || database == "" {
+		return p
+	}
+	return path.Join(database, p)
+}
+
+// Path returns path of the entity with given path elements relative to the
+// database which driver d is dialed to. If d is not created by Dialer then
+// Path joins elements without the database prefix.
+//
+// See JoinPath() for details.
+func Path(d Driver, elems ...string) string {
+	return JoinPath(DriverDatabase(d), elems...)
+}
+
+// DriverDatabase returns the database which d is dialed to. It returns empty
+// string if d is not created by Dialer.
+func DriverDatabase(d Driver) string {
+	if x, ok := d.(interface {
+		Database() string
+	}); ok {
+		return x.Database()
+	}
+	return ""
+}
+
+func isAbsPath(p string) bool {
+	return strings.HasPrefix(p, "/")
+}
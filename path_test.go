@@ -0,0 +1,41 @@
+package ydb
+
+import "testing"
+
+func TestJoinPath(t *testing.T) {
+	for _, test := range []struct {
+		database string
+		elems    []string
+		exp      string
+	}{
+		{"/ru/home/mydb", []string{"users"}, "/ru/home/mydb/users"},
+		{"/ru/home/mydb/", []string{"dir/", "users"}, "/ru/home/mydb/dir/users"},
+		{"/ru/home/mydb", []string{"", "users"}, "/ru/home/mydb/users"},
+		{"/ru/home/mydb", []string{"/ru/home/other/users"}, "/ru/home/other/users"},
+		{"/ru/home/mydb", nil, "/ru/home/mydb"},
+		{"", []string{"dir", "users"}, "dir/users"},
+		{"", nil, ""},
+	} {
+		act := JoinPath(test.database, test.elems...)
+		if act != test.exp {
+			t.Errorf(
+				"JoinPath(%q, %q) = %q; want %q",
+				test.database, test.elems, act, test.exp,
+			)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	d := &driver{
+		meta: &meta{
+			database: "/ru/home/mydb",
+		},
+	}
+	if act, exp := Path(d, "users"), "/ru/home/mydb/users"; act != exp {
+		t.Errorf("unexpected path: %q; want %q", act, exp)
+	}
+	if act, exp := Path(nil, "users"), "users"; act != exp {
+		t.Errorf("unexpected path: %q; want %q", act, exp)
+	}
+}
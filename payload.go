@@ -0,0 +1,192 @@
+package ydb
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+// DefaultPayloadBuckets contains upper bounds (in bytes) of histogram buckets
+// used by PayloadStats by default.
+var DefaultPayloadBuckets = []int{
+	1 << 10,
+	4 << 10,
+	16 << 10,
+	64 << 10,
+	256 << 10,
+	1 << 20,
+	4 << 20,
+	16 << 20,
+	64 << 20,
+}
+
+// PayloadInfo describes sizes of messages of a single call or of a single
+// message received from the stream.
+type PayloadInfo struct {
+	Context context.Context
+	Method  string
+
+	// Stream reports whether payload belongs to the streaming operation. For
+	// streaming operations RequestSize is reported once along with the first
+	// response message, and zero for the next messages.
+	Stream bool
+
+	RequestSize  int
+	ResponseSize int
+}
+
+// PayloadHistogram contains distribution of message sizes of a method.
+type PayloadHistogram struct {
+	// Buckets contains upper bounds of histogram buckets in bytes.
+	Buckets []int
+
+	// Request and Response contain number of messages per bucket. They have
+	// an extra last element counting messages larger than the last bucket
+	// bound.
+	Request  []uint64
+	Response []uint64
+
+	// RequestBytes and ResponseBytes are total sizes of all messages.
+	RequestBytes  uint64
+	ResponseBytes uint64
+
+	// MaxResponse is the size of the largest response message.
+	MaxResponse int
+}
+
+// PayloadStats collects sizes of request and response messages per method.
+// It helps to find queries returning unexpectedly large results and to
+// correlate them with network egress costs.
+//
+// PayloadStats may be used as driver middleware (see CallMiddleware() and
+// StreamReadMiddleware()). Only successful calls are accounted.
+type PayloadStats struct {
+	// Buckets contains upper bounds of histogram buckets in bytes in
+	// ascending order.
+	// If Buckets is nil then the DefaultPayloadBuckets is used.
+	Buckets []int
+
+	// Observe is an optional callback called for each accounted payload. It
+	// may be used to export sizes to the external metrics system.
+	Observe func(PayloadInfo)
+
+	mu      sync.Mutex
+	methods map[string]*PayloadHistogram
+}
+
+// CallMiddleware returns driver middleware which accounts sizes of unary
+// operations messages.
+func (p *PayloadStats) CallMiddleware() CallMiddleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, op internal.Operation) error {
+			err := next(ctx, op)
+			if err != nil {
+				return err
+			}
+			method, req, res := internal.Unwrap(op)
+			x := PayloadInfo{
+				Context:     ctx,
+				Method:      method,
+				RequestSize: proto.Size(req),
+			}
+			if res != nil {
+				x.ResponseSize = proto.Size(res)
+			}
+			p.observe(x)
+			return nil
+		}
+	}
+}
+
+// StreamReadMiddleware returns driver middleware which accounts sizes of
+// streaming operations messages. Each received message is accounted
+// separately.
+func (p *PayloadStats) StreamReadMiddleware() StreamReadMiddleware {
+	return func(next StreamReadFunc) StreamReadFunc {
+		return func(ctx context.Context, op internal.StreamOperation) error {
+			method, req, resp, process := internal.UnwrapStreamOperation(op)
+			reqSize := proto.Size(req)
+			return next(ctx, internal.WrapStreamOperation(
+				method, req, resp,
+				func(err error) {
+					if m, ok := resp.(proto.Message); ok && err == nil {
+						p.observe(PayloadInfo{
+							Context:      ctx,
+							Method:       method,
+							Stream:       true,
+							RequestSize:  reqSize,
+							ResponseSize: proto.Size(m),
+						})
+						reqSize = 0
+					}
+					process(err)
+				},
+			))
+		}
+	}
+}
+
+// Stats returns copy of histograms collected so far by method name.
+func (p *PayloadStats) Stats() map[string]PayloadHistogram {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := make(map[string]PayloadHistogram, len(p.methods))
+	for method, h := range p.methods {
+		c := *h
+		c.Request = append([]uint64(nil), h.Request...)
+		c.Response = append([]uint64(nil), h.Response...)
+		m[method] = c
+	}
+	return m
+}
+
+// Reset drops all collected histograms.
+func (p *PayloadStats) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.methods = nil
+}
+
+func (p *PayloadStats) observe(x PayloadInfo) {
+	p.mu.Lock()
+	h := p.methods[x.Method]
+	if h == nil {
+		if p.methods == nil {
+			p.methods = make(map[string]*PayloadHistogram)
+		}
+		bs := p.Buckets
+		if bs == nil {
+			bs = DefaultPayloadBuckets
+		}
+		h = &PayloadHistogram{
+			Buckets:  bs,
+			Request:  make([]uint64, len(bs)+1),
+			Response: make([]uint64, len(bs)+1),
+		}
+		p.methods[x.Method] = h
+	}
+	if !x.Stream || x.RequestSize > 0 {
+		h.Request[bucketIndex(h.Buckets, x.RequestSize)]++
+		h.RequestBytes += uint64(x.RequestSize)
+	}
+	h.Response[bucketIndex(h.Buckets, x.ResponseSize)]++
+	h.ResponseBytes += uint64(x.ResponseSize)
+	if x.ResponseSize > h.MaxResponse {
+		h.MaxResponse = x.ResponseSize
+	}
+	p.mu.Unlock()
+
+	if f := p.Observe; f != nil {
+		f(x)
+	}
+}
+
+// bucketIndex returns index of the first bucket which upper bound is greater
+// than or equal to n.
+func bucketIndex(bs []int, n int) int {
+	return sort.SearchInts(bs, n)
+}
@@ -0,0 +1,88 @@
+package ydb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+func TestPayloadStats(t *testing.T) {
+	var observed []PayloadInfo
+	p := PayloadStats{
+		Buckets: []int{10, 100},
+		Observe: func(x PayloadInfo) {
+			observed = append(observed, x)
+		},
+	}
+	call := p.CallMiddleware()(func(ctx context.Context, op internal.Operation) error {
+		_, _, res := internal.Unwrap(op)
+		res.(*Ydb_Table.ExecuteQueryResult).QueryMeta = &Ydb_Table.QueryMeta{
+			Id: string(make([]byte, 50)),
+		}
+		return nil
+	})
+	req := &Ydb_Table.ExecuteDataQueryRequest{
+		SessionId: "session-id-0001",
+	}
+	for i := 0; i < 2; i++ {
+		res := new(Ydb_Table.ExecuteQueryResult)
+		err := call(context.Background(), internal.Wrap("method", req, res))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	read := p.StreamReadMiddleware()(func(ctx context.Context, op internal.StreamOperation) error {
+		_, _, _, process := internal.UnwrapStreamOperation(op)
+		for i := 0; i < 2; i++ {
+			process(nil)
+		}
+		return nil
+	})
+	err := read(context.Background(), internal.WrapStreamOperation(
+		"stream",
+		&Ydb_Table.ReadTableRequest{Path: "series"},
+		new(Ydb_Table.ReadTableResponse),
+		func(error) {},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := p.Stats()
+	reqSize := uint64(proto.Size(req))
+	resSize := uint64(proto.Size(&Ydb_Table.ExecuteQueryResult{
+		QueryMeta: &Ydb_Table.QueryMeta{
+			Id: string(make([]byte, 50)),
+		},
+	}))
+	if act, exp := stats["method"], (PayloadHistogram{
+		Buckets:       []int{10, 100},
+		Request:       []uint64{0, 2, 0},
+		Response:      []uint64{0, 2, 0},
+		RequestBytes:  2 * reqSize,
+		ResponseBytes: 2 * resSize,
+		MaxResponse:   int(resSize),
+	}); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected method stats: %+v; want %+v", act, exp)
+	}
+	if act, exp := stats["stream"], (PayloadHistogram{
+		Buckets:      []int{10, 100},
+		Request:      []uint64{1, 0, 0},
+		Response:     []uint64{2, 0, 0},
+		RequestBytes: uint64(proto.Size(&Ydb_Table.ReadTableRequest{Path: "series"})),
+	}); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected stream stats: %+v; want %+v", act, exp)
+	}
+	if n := len(observed); n != 4 {
+		t.Fatalf("unexpected number of observed payloads: %d", n)
+	}
+	if x := observed[2]; !x.Stream || x.Method != "stream" {
+		t.Errorf("unexpected stream payload: %+v", x)
+	}
+}
@@ -0,0 +1,104 @@
+package ydb
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// randomChoice implements balancing algorithm which picks connection
+// uniformly at random. Unlike p2c it does not depend on runtime stats, which
+// may be misleading for small clusters under bursty load.
+type randomChoice struct {
+	Source rand.Source64
+
+	once sync.Once
+	rand *rand.Rand
+
+	conns connList
+}
+
+func (r *randomChoice) init() {
+	r.once.Do(func() {
+		if r.Source == nil {
+			r.Source = rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+		}
+		r.rand = rand.New(&lockedSource{src: r.Source})
+	})
+}
+
+func (r *randomChoice) Next() *conn {
+	r.init()
+	n := len(r.conns)
+	if n == 0 {
+		return nil
+	}
+	return r.conns[r.rand.Intn(n)].conn
+}
+
+func (r *randomChoice) Insert(conn *conn, info connInfo) balancerElement {
+	return r.conns.Insert(conn, info)
+}
+
+func (r *randomChoice) Update(x balancerElement, info connInfo) {
+	x.(*connListElement).info = info
+}
+
+func (r *randomChoice) Remove(x balancerElement) {
+	r.conns.Remove(x.(*connListElement))
+}
+
+// LeastPendingConfig contains configuration of the BalancingLeastPending
+// balancing method.
+type LeastPendingConfig struct {
+	// PreferLocal reports whether balancer should prefer local endpoint when
+	// endpoints have the same number of pending operations.
+	PreferLocal bool
+}
+
+// leastPending implements balancing algorithm which picks connection with
+// the least number of operations in flight. Connections with the same number
+// of operations are picked in turn.
+type leastPending struct {
+	PreferLocal bool
+
+	next  uint32
+	conns connList
+}
+
+func (l *leastPending) Next() *conn {
+	n := len(l.conns)
+	if n == 0 {
+		return nil
+	}
+	var (
+		best    *connListElement
+		pending uint64
+		start   = int(atomic.AddUint32(&l.next, 1))
+	)
+	for i := 0; i < n; i++ {
+		x := l.conns[(start+i)%n]
+		p := x.conn.runtime.opPending()
+		switch {
+		case best == nil || p < pending:
+		case p == pending && l.PreferLocal && x.info.local && !best.info.local:
+		default:
+			continue
+		}
+		best, pending = x, p
+	}
+	return best.conn
+}
+
+func (l *leastPending) Insert(conn *conn, info connInfo) balancerElement {
+	return l.conns.Insert(conn, info)
+}
+
+func (l *leastPending) Update(x balancerElement, info connInfo) {
+	x.(*connListElement).info = info
+}
+
+func (l *leastPending) Remove(x balancerElement) {
+	l.conns.Remove(x.(*connListElement))
+}
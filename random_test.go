@@ -0,0 +1,73 @@
+package ydb
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomChoiceBalancer(t *testing.T) {
+	r := &randomChoice{
+		Source: rand.NewSource(0).(rand.Source64),
+	}
+	if c := r.Next(); c != nil {
+		t.Fatalf("unexpected connection from empty balancer")
+	}
+	var (
+		conns = []*conn{new(conn), new(conn), new(conn)}
+		elems = make([]balancerElement, len(conns))
+		dist  = map[*conn]int{}
+	)
+	for i, c := range conns {
+		elems[i] = r.Insert(c, connInfo{})
+	}
+	r.Remove(elems[2])
+	const n = 1000
+	for i := 0; i < n; i++ {
+		dist[r.Next()]++
+	}
+	if len(dist) != 2 || dist[conns[2]] != 0 {
+		t.Fatalf("unexpected distribution: %v", dist)
+	}
+	for _, c := range conns[:2] {
+		if x := dist[c]; x < n/2-n/10 || x > n/2+n/10 {
+			t.Errorf("unexpected number of choices: %d", x)
+		}
+	}
+}
+
+func TestLeastPendingBalancer(t *testing.T) {
+	l := &leastPending{
+		PreferLocal: true,
+	}
+	if c := l.Next(); c != nil {
+		t.Fatalf("unexpected connection from empty balancer")
+	}
+	var (
+		busy   = new(conn)
+		remote = new(conn)
+		local  = new(conn)
+	)
+	busy.runtime.opStarted = 2
+	remote.runtime.opStarted = 1
+	local.runtime.opStarted = 2
+	local.runtime.opSucceed = 1
+	l.Insert(busy, connInfo{})
+	l.Insert(remote, connInfo{})
+	l.Insert(local, connInfo{local: true})
+	for i := 0; i < 10; i++ {
+		if c := l.Next(); c != local {
+			t.Fatalf("unexpected connection: %p; want local %p", c, local)
+		}
+	}
+
+	// Connections with the same number of pending operations must be used in
+	// turn.
+	l.PreferLocal = false
+	dist := map[*conn]int{}
+	for i := 0; i < 10; i++ {
+		dist[l.Next()]++
+	}
+	if dist[busy] != 0 || dist[remote] == 0 || dist[local] == 0 {
+		t.Fatalf("unexpected distribution: %v", dist)
+	}
+}
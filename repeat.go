@@ -21,11 +21,16 @@ type repeater struct {
 	// Task is a function that must be executed periodically.
 	Task func(context.Context)
 
+	// Clock is an optional source of time.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
 	timer     timeutil.Timer
 	startOnce sync.Once
 	stopOnce  sync.Once
 	stop      chan struct{}
 	done      chan struct{}
+	force     chan struct{}
 	ctx       context.Context
 	cancel    context.CancelFunc
 }
@@ -36,9 +41,10 @@ func (r *repeater) Start() {
 		if r.Interval <= 0 {
 			panic("repeater: non-positive interval")
 		}
-		r.timer = timeutil.NewTimer(r.Interval)
+		r.timer = timeutil.ClockOrDefault(r.Clock).NewTimer(r.Interval)
 		r.stop = make(chan struct{})
 		r.done = make(chan struct{})
+		r.force = make(chan struct{}, 1)
 		r.ctx, r.cancel = context.WithCancel(context.Background())
 		go r.worker()
 	})
@@ -60,12 +66,30 @@ func (r *repeater) Stop() {
 	})
 }
 
+// Force makes repeater to execute its task immediately out of schedule. The
+// next execution is scheduled after the Interval since then.
+// It is a no-op if repeater is not started or task is already forced.
+func (r *repeater) Force() {
+	if r.force == nil {
+		return
+	}
+	select {
+	case r.force <- struct{}{}:
+	default:
+	}
+}
+
 func (r *repeater) worker() {
 	defer close(r.done)
 	for {
 		select {
 		case <-r.timer.C():
 			r.timer.Reset(r.Interval)
+		case <-r.force:
+			if !r.timer.Stop() {
+				<-r.timer.C()
+			}
+			r.timer.Reset(r.Interval)
 		case <-r.stop:
 			return
 		}
@@ -42,6 +42,99 @@ func TestRepeater(t *testing.T) {
 	assertNoRecv(t, 50*time.Millisecond, exec)
 }
 
+func TestRepeaterClock(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(0, 0))
+
+	exec := make(chan struct{}, 1)
+	r := repeater{
+		Interval: 42 * time.Second,
+		Clock:    clock,
+		Task: func(_ context.Context) {
+			exec <- struct{}{}
+		},
+	}
+	r.Start()
+	defer r.Stop()
+
+	clock.Shift(41 * time.Second)
+	assertNoRecv(t, 50*time.Millisecond, exec)
+
+	clock.Shift(time.Second)
+	assertRecv(t, 500*time.Millisecond, exec)
+
+	clock.Shift(41 * time.Second)
+	assertNoRecv(t, 50*time.Millisecond, exec)
+
+	clock.Shift(time.Second)
+	assertRecv(t, 500*time.Millisecond, exec)
+}
+
+func TestRepeaterForce(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(0, 0))
+
+	exec := make(chan struct{}, 1)
+	r := repeater{
+		Interval: 42 * time.Second,
+		Clock:    clock,
+		Task: func(_ context.Context) {
+			exec <- struct{}{}
+		},
+	}
+	r.Start()
+	defer r.Stop()
+
+	clock.Shift(40 * time.Second)
+	r.Force()
+	assertRecv(t, 500*time.Millisecond, exec)
+
+	// Schedule must be shifted after forced execution.
+	clock.Shift(2 * time.Second)
+	assertNoRecv(t, 50*time.Millisecond, exec)
+
+	clock.Shift(40 * time.Second)
+	assertRecv(t, 500*time.Millisecond, exec)
+}
+
+func TestFastDiscovery(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(0, 0))
+
+	var forced int
+	f := &fastDiscovery{
+		threshold:   3,
+		minInterval: time.Second,
+		clock:       clock,
+		force: func() {
+			forced++
+		},
+	}
+	assertForced := func(exp int) {
+		t.Helper()
+		if forced != exp {
+			t.Fatalf("unexpected number of forced discoveries: %d; want %d", forced, exp)
+		}
+	}
+	failure := &TransportError{Reason: TransportErrorDeadlineExceeded}
+	f.report(failure)
+	f.report(failure)
+	f.report(nil)
+	f.report(failure)
+	f.report(failure)
+	assertForced(0)
+	f.report(failure)
+	assertForced(1)
+
+	// Unavailable endpoint forces discovery immediately, but not more often
+	// than minInterval.
+	f.report(&TransportError{Reason: TransportErrorUnavailable})
+	assertForced(1)
+	clock.Shift(time.Second)
+	f.report(&TransportError{Reason: TransportErrorUnavailable})
+	assertForced(2)
+
+	var nilf *fastDiscovery
+	nilf.report(failure)
+}
+
 func TestRepeaterCancelation(t *testing.T) {
 	var (
 		timerC = make(chan time.Time)
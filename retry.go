@@ -5,6 +5,8 @@ import (
 	"math"
 	"math/rand"
 	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
 )
 
 // Default parameters used by Retry() functions within different sub packages.
@@ -18,8 +20,167 @@ var (
 		SlotDuration: time.Second,
 		Ceiling:      6,
 	}
+	// FastBackoff is a logarithmic backoff retry strategy for errors which
+	// are likely to be gone quickly.
+	FastBackoff = LogBackoff{
+		SlotDuration: 5 * time.Millisecond,
+		Ceiling:      6,
+	}
+	// SlowBackoff is a logarithmic backoff retry strategy for errors which
+	// mean that cluster needs time to recover.
+	SlowBackoff = LogBackoff{
+		SlotDuration: time.Second,
+		Ceiling:      6,
+	}
+	// DefaultBackoffPolicy contains no backoff overrides: errors are retried
+	// with the retryer's Backoff as their RetryMode requires. Only the retry
+	// delay suggested by the server is respected.
+	DefaultBackoffPolicy = BackoffPolicy{
+		ServerDelayLimit: DefaultServerDelayLimit,
+	}
+	// RecommendedBackoffPolicy contains backoff overrides similar to ones
+	// used by other YDB SDKs: overload errors are retried with SlowBackoff
+	// while unavailability and busy sessions are retried with FastBackoff.
+	// It must be set explicitly in the retryer's BackoffPolicy to be used.
+	RecommendedBackoffPolicy = BackoffPolicy{
+		Status: map[StatusCode]Backoff{
+			StatusOverloaded:  SlowBackoff,
+			StatusUnavailable: FastBackoff,
+			StatusSessionBusy: FastBackoff,
+		},
+		Transport: map[TransportErrorCode]Backoff{
+			TransportErrorResourceExhausted: SlowBackoff,
+		},
+		ServerDelayLimit: DefaultServerDelayLimit,
+	}
 )
 
+// DefaultServerDelayLimit is the limit of the retry delay suggested by the
+// server used by DefaultBackoffPolicy and RecommendedBackoffPolicy.
+const DefaultServerDelayLimit = 30 * time.Second
+
+// BackoffPolicy contains backoff overrides for particular errors.
+//
+// If there is an override for the error, it is used even if the error's
+// RetryMode does not require backoff.
+type BackoffPolicy struct {
+	// Status maps OpError status codes to backoff.
+	Status map[StatusCode]Backoff
+
+	// Transport maps TransportError codes to backoff.
+	Transport map[TransportErrorCode]Backoff
+
+	// ServerDelayLimit is the maximum retry delay suggested by the server
+	// (see ErrorRetryDelay()) which is used instead of the backoff. Greater
+	// delays are truncated to ServerDelayLimit.
+	// If ServerDelayLimit is zero then server suggestions are ignored.
+	ServerDelayLimit time.Duration
+
+	// OnServerDelay is an optional callback called when the delay suggested
+	// by the server is used instead of the backoff.
+	OnServerDelay func(ServerDelayInfo)
+
+	// Clock is a source of time used to wait for the delay suggested by the
+	// server.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+}
+
+// ServerDelayInfo describes retry delay suggested by the server.
+type ServerDelayInfo struct {
+	Error error
+
+	// Suggested is the delay suggested by the server.
+	Suggested time.Duration
+
+	// Delay is the actual delay with respect to the ServerDelayLimit.
+	Delay time.Duration
+}
+
+// ErrorRetryDelay returns the delay suggested by the server to wait before
+// retrying operation failed with err. Currently only TransportError may
+// contain such suggestion (see TransportError.RetryDelay()); operation
+// issues of the YDB API do not provide retry delays.
+func ErrorRetryDelay(err error) (time.Duration, bool) {
+	if e, ok := err.(*TransportError); ok {
+		return e.RetryDelay()
+	}
+	return 0, false
+}
+
+// Override returns backoff configured for err. It returns nil if there is no
+// override for err.
+func (p BackoffPolicy) Override(err error) Backoff {
+	switch e := err.(type) {
+	case *OpError:
+		return p.Status[e.Reason]
+	case *TransportError:
+		return p.Transport[e.Reason]
+	default:
+		return nil
+	}
+}
+
+// Backoff returns backoff which must be used before retrying an operation
+// failed with err with retry mode m. It returns delay suggested by the server
+// if any and ServerDelayLimit is set; otherwise it returns override for err
+// if any; otherwise if m requires backoff it returns b (or DefaultBackoff if
+// b is nil). If no backoff is needed, Backoff returns nil.
+func (p BackoffPolicy) Backoff(err error, m RetryMode, b Backoff) Backoff {
+	if x := p.serverDelay(err); x != nil {
+		return x
+	}
+	if x := p.Override(err); x != nil {
+		return x
+	}
+	if !m.MustBackoff() {
+		return nil
+	}
+	if b == nil {
+		return DefaultBackoff
+	}
+	return b
+}
+
+func (p BackoffPolicy) serverDelay(err error) Backoff {
+	if p.ServerDelayLimit <= 0 {
+		return nil
+	}
+	s, ok := ErrorRetryDelay(err)
+	if !ok {
+		return nil
+	}
+	d := s
+	if d > p.ServerDelayLimit {
+		d = p.ServerDelayLimit
+	}
+	if d < 0 {
+		d = 0
+	}
+	if f := p.OnServerDelay; f != nil {
+		f(ServerDelayInfo{
+			Error:     err,
+			Suggested: s,
+			Delay:     d,
+		})
+	}
+	return fixedBackoff{
+		delay: d,
+		clock: timeutil.ClockOrDefault(p.Clock),
+	}
+}
+
+// fixedBackoff is a Backoff with the same delay for every retry.
+type fixedBackoff struct {
+	delay time.Duration
+	clock timeutil.Clock
+}
+
+// Wait implements Backoff interface.
+func (b fixedBackoff) Wait(int) <-chan time.Time {
+	return b.clock.NewTimer(b.delay).C()
+}
+
 // RetryChecker contains options of checking errors returned by YDB for ability
 // to retry provoked operation.
 type RetryChecker struct {
@@ -54,6 +215,9 @@ func (r *RetryChecker) Check(err error) (m RetryMode) {
 		context.Canceled,
 		context.DeadlineExceeded:
 		return RetryCheckSession
+
+	case ErrRequestShed:
+		return RetryAvailable | RetryBackoff
 	}
 	switch e := err.(type) {
 	case *TransportError:
@@ -93,6 +257,82 @@ func (r *RetryChecker) Check(err error) (m RetryMode) {
 	return RetryAvailable | m
 }
 
+// CheckIdempotent is like Check() but also reports transport errors (except
+// cancelation) as retriable with backoff. That is, it must be used only for
+// idempotent operations, which may be safely repeated even if their previous
+// attempt was actually completed by the server.
+func (r *RetryChecker) CheckIdempotent(err error) (m RetryMode) {
+	m = r.Check(err)
+	if e, ok := err.(*TransportError); ok && !m.Retriable() && e.Reason != TransportErrorCanceled {
+		m |= RetryAvailable | RetryBackoff
+	}
+	return m
+}
+
+// Retryer contains logic of retrying operations which do not need a table
+// session, such as scheme or discovery operations. See table.Retryer for
+// operations over table sessions.
+type Retryer struct {
+	// MaxRetries is a number of maximum attempts to retry a failed operation.
+	// If MaxRetries is zero then no attempts will be made.
+	MaxRetries int
+
+	// RetryChecker contains options of mapping errors to retry mode.
+	RetryChecker RetryChecker
+
+	// Idempotent reports whether operation is idempotent and thus may be
+	// retried on transport errors. See RetryChecker.CheckIdempotent().
+	Idempotent bool
+
+	// Backoff is a selected backoff policy.
+	// If backoff is nil, then the DefaultBackoff is used.
+	Backoff Backoff
+
+	// BackoffPolicy contains backoff overrides for particular errors. When
+	// there is no override for an error, Backoff is used.
+	BackoffPolicy BackoffPolicy
+}
+
+// Retry calls Retryer.Do() configured with default values. Operation is
+// considered not idempotent.
+func Retry(ctx context.Context, op func(context.Context) error) error {
+	return (Retryer{
+		MaxRetries:    DefaultMaxRetries,
+		RetryChecker:  DefaultRetryChecker,
+		Backoff:       DefaultBackoff,
+		BackoffPolicy: DefaultBackoffPolicy,
+	}).Do(ctx, op)
+}
+
+// Do calls op until it returns nil or not retriable error.
+//
+// Note that op must not wrap ydb errors in order to leave the ability to
+// distinguish error type and make a decision about the next retry attempt.
+func (r Retryer) Do(ctx context.Context, op func(context.Context) error) (err error) {
+	for i := 0; i <= r.MaxRetries; i++ {
+		if err = op(ctx); err == nil {
+			return nil
+		}
+		var m RetryMode
+		if r.Idempotent {
+			m = r.RetryChecker.CheckIdempotent(err)
+		} else {
+			m = r.RetryChecker.Check(err)
+		}
+		if !m.Retriable() {
+			return err
+		}
+		if b := r.BackoffPolicy.Backoff(err, m, r.Backoff); b != nil {
+			if e := WaitBackoff(ctx, b, i); e != nil {
+				// Return original error to make it possible to lay on for the
+				// client.
+				return err
+			}
+		}
+	}
+	return err
+}
+
 // Backoff is the interface that contains logic of delaying operation retry.
 type Backoff interface {
 	// Wait maps index of the retry to a channel which fulfillment means that
@@ -145,10 +385,18 @@ type LogBackoff struct {
 	// where F is a result of multiplication of this value and calculated delay
 	// duration D; and R is a random sized part from [0,(D - F)].
 	JitterLimit float64
+
+	// Clock is an optional source of time used to wait for the backoff
+	// delay.
+	// If Clock is nil then the time package is used.
+	Clock timeutil.Clock
 }
 
 // Wait implements Backoff interface.
 func (b LogBackoff) Wait(n int) <-chan time.Time {
+	if c := b.Clock; c != nil {
+		return c.NewTimer(b.Delay(n)).C()
+	}
 	return time.After(b.Delay(n))
 }
 
@@ -1,9 +1,16 @@
 package ydb
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
 )
 
 func TestLogBackoff(t *testing.T) {
@@ -105,3 +112,159 @@ func TestLogBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestBackoffPolicy(t *testing.T) {
+	var (
+		fallback = LogBackoff{SlotDuration: time.Millisecond}
+		aborted  = LogBackoff{SlotDuration: time.Minute}
+		r        RetryChecker
+	)
+	p := BackoffPolicy{
+		Status: map[StatusCode]Backoff{
+			StatusAborted: aborted,
+		},
+	}
+	for _, test := range []struct {
+		err error
+		exp Backoff
+	}{
+		{
+			err: &OpError{Reason: StatusAborted},
+			exp: aborted,
+		},
+		{
+			err: &OpError{Reason: StatusOverloaded},
+			exp: fallback,
+		},
+		{
+			err: &OpError{Reason: StatusUnavailable},
+			exp: nil,
+		},
+	} {
+		act := p.Backoff(test.err, r.Check(test.err), fallback)
+		if act != test.exp {
+			t.Errorf("unexpected backoff for %v: %v; want %v", test.err, act, test.exp)
+		}
+	}
+
+	err := &TransportError{Reason: TransportErrorResourceExhausted}
+	if act := RecommendedBackoffPolicy.Backoff(err, r.Check(err), nil); act != SlowBackoff {
+		t.Errorf("unexpected recommended backoff for %v: %v", err, act)
+	}
+	if act := DefaultBackoffPolicy.Override(err); act != nil {
+		t.Errorf("unexpected default backoff override for %v: %v", err, act)
+	}
+}
+
+func TestBackoffPolicyServerDelay(t *testing.T) {
+	suggest := func(d time.Duration) error {
+		return &TransportError{
+			Reason: TransportErrorUnavailable,
+			statusDetails: []proto.Message{
+				&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(d)},
+			},
+		}
+	}
+	var info []ServerDelayInfo
+	clock := timetest.NewClock(time.Unix(0, 0))
+	p := BackoffPolicy{
+		ServerDelayLimit: 10 * time.Second,
+		OnServerDelay: func(x ServerDelayInfo) {
+			info = append(info, x)
+		},
+		Clock: clock,
+	}
+	var r RetryChecker
+	for _, test := range []struct {
+		err error
+		exp Backoff
+	}{
+		{
+			err: suggest(time.Second),
+			exp: fixedBackoff{time.Second, clock},
+		},
+		{
+			err: suggest(time.Minute),
+			exp: fixedBackoff{10 * time.Second, clock},
+		},
+		{
+			err: &TransportError{Reason: TransportErrorUnavailable},
+			exp: DefaultBackoff,
+		},
+	} {
+		act := p.Backoff(test.err, r.CheckIdempotent(test.err), nil)
+		if act != test.exp {
+			t.Errorf("unexpected backoff for %v: %v; want %v", test.err, act, test.exp)
+		}
+	}
+	if len(info) != 2 || info[1].Suggested != time.Minute || info[1].Delay != 10*time.Second {
+		t.Errorf("unexpected server delay info: %+v", info)
+	}
+
+	p.ServerDelayLimit = 0
+	err := suggest(time.Second)
+	if act, exp := p.Backoff(err, r.CheckIdempotent(err), nil), Backoff(DefaultBackoff); act != exp {
+		t.Errorf("unexpected backoff with server delays disabled: %v; want %v", act, exp)
+	}
+}
+
+func TestRetryerIdempotent(t *testing.T) {
+	zero := BackoffFunc(func(n int) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	})
+	for _, test := range []struct {
+		name       string
+		err        error
+		idempotent bool
+		attempts   int
+	}{
+		{
+			name:     "transport error",
+			err:      &TransportError{Reason: TransportErrorUnavailable},
+			attempts: 1,
+		},
+		{
+			name:       "idempotent transport error",
+			err:        &TransportError{Reason: TransportErrorUnavailable},
+			idempotent: true,
+			attempts:   3,
+		},
+		{
+			name:       "idempotent canceled",
+			err:        &TransportError{Reason: TransportErrorCanceled},
+			idempotent: true,
+			attempts:   1,
+		},
+		{
+			name:     "overloaded",
+			err:      &OpError{Reason: StatusOverloaded},
+			attempts: 3,
+		},
+		{
+			name:       "non retriable",
+			err:        &OpError{Reason: StatusSchemeError},
+			idempotent: true,
+			attempts:   1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var n int
+			err := (Retryer{
+				MaxRetries: 2,
+				Idempotent: test.idempotent,
+				Backoff:    zero,
+			}).Do(context.Background(), func(context.Context) error {
+				n++
+				return test.err
+			})
+			if err != test.err {
+				t.Errorf("unexpected error: %v; want %v", err, test.err)
+			}
+			if n != test.attempts {
+				t.Errorf("unexpected number of attempts: %d; want %d", n, test.attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// Package router contains a facade for services which talk to several YDB
+// databases (on the same or on different clusters).
+package router
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/table"
+)
+
+// ErrUnknownDatabase is returned when database name is not configured in the
+// Router.
+var ErrUnknownDatabase = errors.New("ydb: router: unknown database")
+
+// Database describes how to dial a single database.
+type Database struct {
+	// Addr is the address of the database endpoint. See ydb.Dialer's Dial()
+	// for the format.
+	Addr string
+
+	// Database is the database path, e.g. "/ru/home/user/mydb". It may be
+	// empty if Addr contains the "database" query parameter.
+	Database string
+
+	// Credentials are the database specific credentials.
+	// If Credentials is nil then the Router's Credentials are used.
+	Credentials ydb.Credentials
+
+	// Dialer is an optional database specific dialer.
+	// If Dialer is nil then the Router's Dialer is used.
+	Dialer *ydb.Dialer
+}
+
+// Router manages drivers for multiple databases keyed by name. Drivers are
+// dialed lazily on the first use and are shared by all clients of the same
+// database.
+//
+// Router must not be copied after the first use.
+type Router struct {
+	// Databases contains databases by name. It must not be modified after the
+	// first use of the Router.
+	Databases map[string]Database
+
+	// Dialer is the default dialer used to dial databases. Database and
+	// Credentials fields of its DriverConfig are overridden by the Database
+	// settings.
+	// If Dialer is nil then zero ydb.Dialer is used.
+	Dialer *ydb.Dialer
+
+	// Credentials are the default credentials shared by all databases.
+	Credentials ydb.Credentials
+
+	mu      sync.Mutex
+	drivers map[string]*lazyDriver
+	closed  bool
+
+	// testHookDial replaces dialing of the database in tests.
+	testHookDial func(context.Context, ydb.Dialer, string) (ydb.Driver, error)
+}
+
+// Driver returns driver of the database with given name. The driver dials
+// the database on the first call made through it. Dial errors are returned
+// from that call; next calls try to dial again.
+//
+// Returned driver must not be closed; use Router's Close() instead.
+func (r *Router) Driver(name string) (ydb.Driver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ydb.ErrClosed
+	}
+	if d, ok := r.drivers[name]; ok {
+		return d, nil
+	}
+	db, ok := r.Databases[name]
+	if !ok {
+		return nil, ErrUnknownDatabase
+	}
+	if r.drivers == nil {
+		r.drivers = make(map[string]*lazyDriver)
+	}
+	d := &lazyDriver{
+		router: r,
+		db:     db,
+	}
+	r.drivers[name] = d
+	return d, nil
+}
+
+// Dial returns driver of the database with given name as Driver() does, but
+// dials it immediately if it is not dialed yet.
+func (r *Router) Dial(ctx context.Context, name string) (ydb.Driver, error) {
+	d, err := r.Driver(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.(*lazyDriver).get(ctx); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Table returns table client of the database with given name. If database
+// is not configured, every call of the returned client fails with
+// ErrUnknownDatabase.
+func (r *Router) Table(name string) *table.Client {
+	d, err := r.Driver(name)
+	if err != nil {
+		d = errDriver{err}
+	}
+	return &table.Client{
+		Driver: d,
+	}
+}
+
+// Names returns sorted names of configured databases.
+func (r *Router) Names() []string {
+	names := make([]string, 0, len(r.Databases))
+	for name := range r.Databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes all dialed drivers. Drivers returned by the Router become
+// unusable after Close().
+func (r *Router) Close() (err error) {
+	r.mu.Lock()
+	drivers := r.drivers
+	r.drivers = nil
+	r.closed = true
+	r.mu.Unlock()
+
+	for _, d := range drivers {
+		if e := d.close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (r *Router) dial(ctx context.Context, db Database) (ydb.Driver, error) {
+	dialer := r.dialer(db)
+	if f := r.testHookDial; f != nil {
+		return f(ctx, dialer, db.Addr)
+	}
+	return dialer.Dial(ctx, db.Addr)
+}
+
+// dialer returns dialer of the given database.
+func (r *Router) dialer(db Database) (dialer ydb.Dialer) {
+	switch {
+	case db.Dialer != nil:
+		dialer = *db.Dialer
+	case r.Dialer != nil:
+		dialer = *r.Dialer
+	}
+	var config ydb.DriverConfig
+	if dialer.DriverConfig != nil {
+		config = *dialer.DriverConfig
+	}
+	if db.Database != "" {
+		config.Database = db.Database
+	}
+	switch {
+	case db.Credentials != nil:
+		config.Credentials = db.Credentials
+	case r.Credentials != nil:
+		config.Credentials = r.Credentials
+	}
+	dialer.DriverConfig = &config
+	return dialer
+}
+
+// lazyDriver is a driver which dials the database on the first use.
+type lazyDriver struct {
+	router *Router
+	db     Database
+
+	mu      sync.Mutex
+	driver  ydb.Driver
+	dialing *dialCall
+	closed  bool
+}
+
+// dialCall is an in-flight dialing of the database.
+type dialCall struct {
+	done   chan struct{}
+	driver ydb.Driver
+	err    error
+}
+
+// get returns dialed driver. Concurrent callers share the single dialing,
+// which is made outside of the lock; each of them waits for it no longer
+// than its ctx allows.
+func (d *lazyDriver) get(ctx context.Context) (ydb.Driver, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, ydb.ErrClosed
+	}
+	if d.driver != nil {
+		d.mu.Unlock()
+		return d.driver, nil
+	}
+	if call := d.dialing; call != nil {
+		d.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.driver, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &dialCall{
+		done: make(chan struct{}),
+	}
+	d.dialing = call
+	d.mu.Unlock()
+
+	x, err := d.router.dial(ctx, d.db)
+
+	d.mu.Lock()
+	d.dialing = nil
+	switch {
+	case err != nil:
+	case d.closed:
+		_ = x.Close()
+		x, err = nil, ydb.ErrClosed
+	default:
+		d.driver = x
+	}
+	d.mu.Unlock()
+
+	call.driver, call.err = x, err
+	close(call.done)
+
+	return x, err
+}
+
+func (d *lazyDriver) close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	if d.driver == nil {
+		// Driver which is being dialed is closed right after dialing.
+		return nil
+	}
+	return d.driver.Close()
+}
+
+func (d *lazyDriver) Call(ctx context.Context, op internal.Operation) error {
+	x, err := d.get(ctx)
+	if err != nil {
+		return err
+	}
+	return x.Call(ctx, op)
+}
+
+func (d *lazyDriver) StreamRead(ctx context.Context, op internal.StreamOperation) error {
+	x, err := d.get(ctx)
+	if err != nil {
+		return err
+	}
+	return x.StreamRead(ctx, op)
+}
+
+// Close does nothing. Drivers are closed by the Router's Close().
+func (d *lazyDriver) Close() error {
+	return nil
+}
+
+// Database returns the configured database path. It makes ydb.Path() work
+// before the driver is dialed.
+func (d *lazyDriver) Database() string {
+	d.mu.Lock()
+	x := d.driver
+	d.mu.Unlock()
+	if x != nil {
+		return ydb.DriverDatabase(x)
+	}
+	return d.db.Database
+}
+
+type errDriver struct {
+	err error
+}
+
+func (d errDriver) Call(context.Context, internal.Operation) error {
+	return d.err
+}
+
+func (d errDriver) StreamRead(context.Context, internal.StreamOperation) error {
+	return d.err
+}
+
+func (d errDriver) Close() error {
+	return nil
+}
@@ -0,0 +1,174 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+type stubDriver struct {
+	database string
+	calls    int
+	closed   bool
+}
+
+func (d *stubDriver) Call(context.Context, internal.Operation) error {
+	d.calls++
+	return nil
+}
+
+func (d *stubDriver) StreamRead(context.Context, internal.StreamOperation) error {
+	return nil
+}
+
+func (d *stubDriver) Close() error {
+	d.closed = true
+	return nil
+}
+
+func (d *stubDriver) Database() string {
+	return d.database
+}
+
+func TestRouter(t *testing.T) {
+	var (
+		shared  = ydb.AuthTokenCredentials{AuthToken: "shared"}
+		special = ydb.AuthTokenCredentials{AuthToken: "special"}
+		dialErr = errors.New("dial error")
+	)
+	var (
+		dialed  = make(map[string]*stubDriver)
+		failing = true
+	)
+	r := Router{
+		Databases: map[string]Database{
+			"main": {
+				Addr:     "main:2135",
+				Database: "/local/main",
+			},
+			"analytics": {
+				Addr:        "analytics:2135",
+				Database:    "/local/analytics",
+				Credentials: special,
+			},
+		},
+		Credentials: shared,
+		testHookDial: func(_ context.Context, d ydb.Dialer, addr string) (ydb.Driver, error) {
+			c := d.DriverConfig
+			switch {
+			case addr == "main:2135" && c.Credentials != shared:
+				t.Errorf("unexpected credentials of %q: %v", addr, c.Credentials)
+			case addr == "analytics:2135" && c.Credentials != special:
+				t.Errorf("unexpected credentials of %q: %v", addr, c.Credentials)
+			}
+			if addr == "analytics:2135" && failing {
+				failing = false
+				return nil, dialErr
+			}
+			x := &stubDriver{database: c.Database}
+			dialed[addr] = x
+			return x, nil
+		},
+	}
+	ctx := context.Background()
+
+	main := r.Table("main")
+	if len(dialed) != 0 {
+		t.Fatalf("driver dialed before the first call")
+	}
+	if act, exp := main.Path("series"), "/local/main/series"; act != exp {
+		t.Errorf("unexpected path: %q; want %q", act, exp)
+	}
+	if err := main.Driver.Call(ctx, internal.Operation{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := main.Driver.Call(ctx, internal.Operation{}); err != nil {
+		t.Fatal(err)
+	}
+	if d := dialed["main:2135"]; d == nil || d.calls != 2 {
+		t.Fatalf("unexpected main driver: %+v", d)
+	}
+
+	if _, err := r.Dial(ctx, "analytics"); err != dialErr {
+		t.Fatalf("unexpected error: %v; want %v", err, dialErr)
+	}
+	if _, err := r.Dial(ctx, "analytics"); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := r.Table("unknown")
+	if err := unknown.Driver.Call(ctx, internal.Operation{}); err != ErrUnknownDatabase {
+		t.Fatalf("unexpected error: %v; want %v", err, ErrUnknownDatabase)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for addr, d := range dialed {
+		if !d.closed {
+			t.Errorf("driver of %q is not closed", addr)
+		}
+	}
+	if err := main.Driver.Call(ctx, internal.Operation{}); err != ydb.ErrClosed {
+		t.Fatalf("unexpected error: %v; want %v", err, ydb.ErrClosed)
+	}
+}
+
+func TestRouterDialOutsideOfLock(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		dials   int
+	)
+	r := Router{
+		Databases: map[string]Database{
+			"main": {
+				Addr:     "main:2135",
+				Database: "/local/main",
+			},
+		},
+		testHookDial: func(_ context.Context, d ydb.Dialer, addr string) (ydb.Driver, error) {
+			dials++
+			close(started)
+			<-release
+			return &stubDriver{database: d.DriverConfig.Database}, nil
+		},
+	}
+	defer r.Close()
+
+	done := make(chan error)
+	go func() {
+		_, err := r.Dial(context.Background(), "main")
+		done <- err
+	}()
+	<-started
+
+	d, err := r.Driver("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Database() must not wait for dialing.
+	if act, exp := ydb.DriverDatabase(d), "/local/main"; act != exp {
+		t.Errorf("unexpected database: %q; want %q", act, exp)
+	}
+	// Waiting for the dialing must respect the caller's context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.Call(ctx, internal.Operation{}); err != context.Canceled {
+		t.Fatalf("unexpected error: %v; want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Call(context.Background(), internal.Operation{}); err != nil {
+		t.Fatal(err)
+	}
+	if dials != 1 {
+		t.Fatalf("unexpected number of dials: %d", dials)
+	}
+}
@@ -0,0 +1,199 @@
+package ydb
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// TraceEventClass describes a group of related DriverTrace events.
+type TraceEventClass uint
+
+const (
+	TraceEventUnknown TraceEventClass = iota
+	TraceEventDial
+	TraceEventGetConn
+	TraceEventTrackConn
+	TraceEventGetCredentials
+	TraceEventDiscovery
+	TraceEventOperation
+	TraceEventStream
+)
+
+// TraceSampler contains sampling policy of DriverTrace events. It makes
+// possible to keep detailed tracing enabled in production without
+// overwhelming the tracing backend.
+//
+// Events reporting an error are always passed. Other events are passed with
+// probability configured per event class.
+//
+// Note that start and done events are sampled independently. That is, done
+// event of failed operation is passed even if its start event was dropped.
+type TraceSampler struct {
+	// Rate is a probability in range [0, 1] of passing successful events of
+	// classes not listed in Rates.
+	Rate float64
+
+	// Rates contains probabilities of passing successful events per event
+	// class.
+	Rates map[TraceEventClass]float64
+
+	// Source is an optional source of random numbers.
+	Source rand.Source64
+
+	once sync.Once
+	rand *rand.Rand
+}
+
+// DriverTrace returns DriverTrace which passes sampled events to t.
+func (s *TraceSampler) DriverTrace(t DriverTrace) (r DriverTrace) {
+	if f := t.DialStart; f != nil {
+		r.DialStart = func(x DialStartInfo) {
+			if s.sample(TraceEventDial, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.DialDone; f != nil {
+		r.DialDone = func(x DialDoneInfo) {
+			if s.sample(TraceEventDial, x.Error) {
+				f(x)
+			}
+		}
+	}
+	if f := t.GetConnStart; f != nil {
+		r.GetConnStart = func(x GetConnStartInfo) {
+			if s.sample(TraceEventGetConn, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.GetConnDone; f != nil {
+		r.GetConnDone = func(x GetConnDoneInfo) {
+			if s.sample(TraceEventGetConn, x.Error) {
+				f(x)
+			}
+		}
+	}
+	if f := t.TrackConnStart; f != nil {
+		r.TrackConnStart = func(x TrackConnStartInfo) {
+			if s.sample(TraceEventTrackConn, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.TrackConnDone; f != nil {
+		r.TrackConnDone = func(x TrackConnDoneInfo) {
+			if s.sample(TraceEventTrackConn, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.GetCredentialsStart; f != nil {
+		r.GetCredentialsStart = func(x GetCredentialsStartInfo) {
+			if s.sample(TraceEventGetCredentials, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.GetCredentialsDone; f != nil {
+		r.GetCredentialsDone = func(x GetCredentialsDoneInfo) {
+			if s.sample(TraceEventGetCredentials, x.Error) {
+				f(x)
+			}
+		}
+	}
+	if f := t.DiscoveryStart; f != nil {
+		r.DiscoveryStart = func(x DiscoveryStartInfo) {
+			if s.sample(TraceEventDiscovery, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.DiscoveryDone; f != nil {
+		r.DiscoveryDone = func(x DiscoveryDoneInfo) {
+			if s.sample(TraceEventDiscovery, x.Error) {
+				f(x)
+			}
+		}
+	}
+	// DiscoveryDiff is rare and meaningful event, thus it is never dropped.
+	r.DiscoveryDiff = t.DiscoveryDiff
+	if f := t.OperationStart; f != nil {
+		r.OperationStart = func(x OperationStartInfo) {
+			if s.sample(TraceEventOperation, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.OperationWait; f != nil {
+		r.OperationWait = func(x OperationWaitInfo) {
+			if s.sample(TraceEventOperation, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.OperationDone; f != nil {
+		r.OperationDone = func(x OperationDoneInfo) {
+			if s.sample(TraceEventOperation, x.Error) {
+				f(x)
+			}
+		}
+	}
+	if f := t.StreamStart; f != nil {
+		r.StreamStart = func(x StreamStartInfo) {
+			if s.sample(TraceEventStream, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.StreamRecvStart; f != nil {
+		r.StreamRecvStart = func(x StreamRecvStartInfo) {
+			if s.sample(TraceEventStream, nil) {
+				f(x)
+			}
+		}
+	}
+	if f := t.StreamRecvDone; f != nil {
+		r.StreamRecvDone = func(x StreamRecvDoneInfo) {
+			if s.sample(TraceEventStream, x.Error) {
+				f(x)
+			}
+		}
+	}
+	if f := t.StreamDone; f != nil {
+		r.StreamDone = func(x StreamDoneInfo) {
+			if s.sample(TraceEventStream, x.Error) {
+				f(x)
+			}
+		}
+	}
+	return r
+}
+
+func (s *TraceSampler) init() {
+	s.once.Do(func() {
+		if s.Source == nil {
+			s.Source = rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+		}
+		s.rand = rand.New(&lockedSource{src: s.Source})
+	})
+}
+
+func (s *TraceSampler) sample(c TraceEventClass, err error) bool {
+	if err != nil {
+		return true
+	}
+	rate, ok := s.Rates[c]
+	if !ok {
+		rate = s.Rate
+	}
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	}
+	s.init()
+	return s.rand.Float64() < rate
+}
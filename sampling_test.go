@@ -0,0 +1,61 @@
+package ydb
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestTraceSampler(t *testing.T) {
+	s := TraceSampler{
+		Rate: 0.1,
+		Rates: map[TraceEventClass]float64{
+			TraceEventDial:    1,
+			TraceEventGetConn: 0,
+		},
+		Source: rand.NewSource(0).(rand.Source64),
+	}
+	var (
+		dials    int
+		getConns int
+		ops      int
+		failed   int
+	)
+	trace := s.DriverTrace(DriverTrace{
+		DialDone: func(DialDoneInfo) {
+			dials++
+		},
+		GetConnDone: func(x GetConnDoneInfo) {
+			if x.Error != nil {
+				failed++
+				return
+			}
+			getConns++
+		},
+		OperationDone: func(OperationDoneInfo) {
+			ops++
+		},
+	})
+	if trace.StreamDone != nil {
+		t.Fatalf("unexpected non-nil callback")
+	}
+	const n = 1000
+	for i := 0; i < n; i++ {
+		trace.DialDone(DialDoneInfo{})
+		trace.GetConnDone(GetConnDoneInfo{})
+		trace.GetConnDone(GetConnDoneInfo{Error: errors.New("test")})
+		trace.OperationDone(OperationDoneInfo{})
+	}
+	if dials != n {
+		t.Errorf("unexpected number of dial events: %d; want %d", dials, n)
+	}
+	if getConns != 0 {
+		t.Errorf("unexpected number of get conn events: %d; want 0", getConns)
+	}
+	if failed != n {
+		t.Errorf("unexpected number of failed events: %d; want %d", failed, n)
+	}
+	if ops < n/20 || ops > n/5 {
+		t.Errorf("unexpected number of operation events: %d", ops)
+	}
+}
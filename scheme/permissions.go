@@ -0,0 +1,99 @@
+package scheme
+
+import (
+	"context"
+	"sort"
+)
+
+// DiffPermissions returns options which turn current permissions into the
+// desired ones when passed to ModifyPermissions(). That is, it grants
+// permission names which are not present in current but are in desired, and
+// revokes permission names which are present in current but not in desired.
+// Subjects which are not mentioned in desired lose all their permissions.
+//
+// Options are ordered by subject name; revocations go before grants. If
+// current and desired permissions are equal, DiffPermissions returns nil.
+func DiffPermissions(current, desired []Permissions) (opts []PermissionsOption) {
+	var (
+		curr = permissionsSet(current)
+		want = permissionsSet(desired)
+	)
+	subjects := make([]string, 0, len(curr)+len(want))
+	for s := range curr {
+		subjects = append(subjects, s)
+	}
+	for s := range want {
+		if _, has := curr[s]; !has {
+			subjects = append(subjects, s)
+		}
+	}
+	sort.Strings(subjects)
+
+	for _, s := range subjects {
+		revoke := difference(curr[s], want[s])
+		if len(revoke) > 0 {
+			opts = append(opts, WithRevokePermissions(Permissions{
+				Subject:         s,
+				PermissionNames: revoke,
+			}))
+		}
+		grant := difference(want[s], curr[s])
+		if len(grant) > 0 {
+			opts = append(opts, WithGrantPermissions(Permissions{
+				Subject:         s,
+				PermissionNames: grant,
+			}))
+		}
+	}
+	return opts
+}
+
+// ApplyPermissions makes permissions of the given path equal to desired ones.
+// It describes current permissions of path and modifies only the difference
+// between them and desired permissions (see DiffPermissions()). It is
+// intended to be used for idempotent permissions management.
+//
+// Note that only permissions set directly on path are compared; inherited
+// (effective) permissions are not taken into account.
+//
+// It returns true if permissions were modified.
+func (c *Client) ApplyPermissions(ctx context.Context, path string, desired []Permissions) (changed bool, err error) {
+	e, err := c.DescribePath(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	opts := DiffPermissions(e.Permissions, desired)
+	if len(opts) == 0 {
+		return false, nil
+	}
+	if err := c.ModifyPermissions(ctx, path, opts...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func permissionsSet(ps []Permissions) map[string]map[string]struct{} {
+	m := make(map[string]map[string]struct{}, len(ps))
+	for _, p := range ps {
+		names := m[p.Subject]
+		if names == nil {
+			names = make(map[string]struct{}, len(p.PermissionNames))
+			m[p.Subject] = names
+		}
+		for _, name := range p.PermissionNames {
+			names[name] = struct{}{}
+		}
+	}
+	return m
+}
+
+// difference returns sorted names which are present in a but not in b.
+func difference(a, b map[string]struct{}) (ret []string) {
+	for name := range a {
+		if _, has := b[name]; !has {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,96 @@
+package scheme
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Scheme"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	current := []Permissions{
+		{Subject: "alice", PermissionNames: []string{"read", "write"}},
+		{Subject: "bob", PermissionNames: []string{"read"}},
+		{Subject: "eve", PermissionNames: []string{"read"}},
+	}
+	desired := []Permissions{
+		{Subject: "alice", PermissionNames: []string{"write", "read"}},
+		{Subject: "bob", PermissionNames: []string{"write"}},
+		{Subject: "carol", PermissionNames: []string{"read"}},
+	}
+	var desc permissionsDesc
+	for _, opt := range DiffPermissions(current, desired) {
+		opt(&desc)
+	}
+	var act []string
+	for _, a := range desc.actions {
+		switch a := a.Action.(type) {
+		case *Ydb_Scheme.PermissionsAction_Grant:
+			act = append(act, "grant "+a.Grant.Subject+" "+a.Grant.PermissionNames[0])
+		case *Ydb_Scheme.PermissionsAction_Revoke:
+			act = append(act, "revoke "+a.Revoke.Subject+" "+a.Revoke.PermissionNames[0])
+		default:
+			t.Errorf("unexpected action: %v", a)
+		}
+	}
+	exp := []string{
+		"revoke bob read",
+		"grant bob write",
+		"grant carol read",
+		"revoke eve read",
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected actions: %v; want %v", act, exp)
+	}
+	if opts := DiffPermissions(current, current); opts != nil {
+		t.Errorf("unexpected options for equal permissions: %d", len(opts))
+	}
+}
+
+func TestApplyPermissions(t *testing.T) {
+	var modified int
+	client := Client{
+		Driver: &testutil.Driver{
+			OnCall: func(ctx context.Context, _ testutil.MethodCode, req, res interface{}) error {
+				switch req.(type) {
+				case *Ydb_Scheme.DescribePathRequest:
+					res.(*Ydb_Scheme.DescribePathResult).Self = &Ydb_Scheme.Entry{
+						Permissions: []*Ydb_Scheme.Permissions{
+							{Subject: "alice", PermissionNames: []string{"read"}},
+						},
+					}
+				case *Ydb_Scheme.ModifyPermissionsRequest:
+					modified++
+				}
+				return nil
+			},
+		},
+	}
+	for _, test := range []struct {
+		desired []Permissions
+		changed bool
+	}{
+		{
+			desired: []Permissions{{Subject: "alice", PermissionNames: []string{"read"}}},
+			changed: false,
+		},
+		{
+			desired: []Permissions{{Subject: "alice", PermissionNames: []string{"write"}}},
+			changed: true,
+		},
+	} {
+		modified = 0
+		changed, err := client.ApplyPermissions(context.Background(), "/db/t", test.desired)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != test.changed || changed != (modified == 1) {
+			t.Errorf(
+				"unexpected result: changed %t, modified %d times; want %t",
+				changed, modified, test.changed,
+			)
+		}
+	}
+}
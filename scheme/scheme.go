@@ -56,25 +56,60 @@ type Entry struct {
 	EffectivePermissions []Permissions
 }
 
+// IsDirectory reports whether entry is a directory (or a database, which is
+// also able to contain other entries).
+func (e *Entry) IsDirectory() bool {
+	return e.Type == EntryDirectory || e.Type == EntryDatabase
+}
+
+// IsTable reports whether entry is a table.
+func (e *Entry) IsTable() bool {
+	return e.Type == EntryTable
+}
+
+// IsTopic reports whether entry is a topic (persistent queue group).
+func (e *Entry) IsTopic() bool {
+	return e.Type == EntryPersQueueGroup
+}
+
 type Directory struct {
 	Entry
 	Children []Entry
 }
 
+// Filter returns children of the directory for which fn returns true.
+func (d *Directory) Filter(fn func(*Entry) bool) (es []Entry) {
+	for i := range d.Children {
+		if fn(&d.Children[i]) {
+			es = append(es, d.Children[i])
+		}
+	}
+	return es
+}
+
 type Client struct {
 	Driver ydb.Driver
 }
 
+// Path returns path of the entry with given path elements relative to the
+// database which Driver is dialed to. Relative paths passed to the Client
+// methods are resolved the same way.
+//
+// See ydb.JoinPath() for details.
+func (c *Client) Path(elems ...string) string {
+	return ydb.Path(c.Driver, elems...)
+}
+
 func (c *Client) MakeDirectory(ctx context.Context, path string) error {
 	req := Ydb_Scheme.MakeDirectoryRequest{
-		Path: path,
+		Path: c.Path(path),
 	}
 	return c.Driver.Call(ctx, internal.Wrap(Ydb_Scheme_V1.MakeDirectory, &req, nil))
 }
 
 func (c *Client) RemoveDirectory(ctx context.Context, path string) error {
 	req := Ydb_Scheme.RemoveDirectoryRequest{
-		Path: path,
+		Path: c.Path(path),
 	}
 	return c.Driver.Call(ctx, internal.Wrap(
 		Ydb_Scheme_V1.RemoveDirectory, &req, nil,
@@ -84,7 +119,7 @@ func (c *Client) RemoveDirectory(ctx context.Context, path string) error {
 func (c *Client) ListDirectory(ctx context.Context, path string) (d Directory, err error) {
 	var res Ydb_Scheme.ListDirectoryResult
 	req := Ydb_Scheme.ListDirectoryRequest{
-		Path: path,
+		Path: c.Path(path),
 	}
 	err = c.Driver.Call(ctx, internal.Wrap(
 		Ydb_Scheme_V1.ListDirectory, &req, &res,
@@ -101,7 +136,7 @@ func (c *Client) ListDirectory(ctx context.Context, path string) (d Directory, e
 func (c *Client) DescribePath(ctx context.Context, path string) (e Entry, err error) {
 	var res Ydb_Scheme.DescribePathResult
 	req := Ydb_Scheme.DescribePathRequest{
-		Path: path,
+		Path: c.Path(path),
 	}
 	err = c.Driver.Call(ctx, internal.Wrap(
 		Ydb_Scheme_V1.DescribePath, &req, &res,
@@ -118,7 +153,7 @@ func (c *Client) ModifyPermissions(ctx context.Context, path string, opts ...Per
 		opt(&desc)
 	}
 	req := Ydb_Scheme.ModifyPermissionsRequest{
-		Path:             path,
+		Path:             c.Path(path),
 		Actions:          desc.actions,
 		ClearPermissions: desc.clear,
 	}
@@ -140,7 +175,7 @@ func (e *Entry) from(y *Ydb_Scheme.Entry) {
 		Owner:                y.Owner,
 		Type:                 entryType(y.Type),
 		Permissions:          p[0:n],
-		EffectivePermissions: p[n:m],
+		EffectivePermissions: p[n:],
 	}
 }
 
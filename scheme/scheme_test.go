@@ -0,0 +1,36 @@
+package scheme
+
+import "testing"
+
+func TestDirectoryFilter(t *testing.T) {
+	d := Directory{
+		Children: []Entry{
+			{Name: "dir", Type: EntryDirectory},
+			{Name: "table", Type: EntryTable},
+			{Name: "topic", Type: EntryPersQueueGroup},
+			{Name: "db", Type: EntryDatabase},
+			{Name: "node", Type: EntryCoordinationNode},
+		},
+	}
+	for _, test := range []struct {
+		name string
+		fn   func(*Entry) bool
+		exp  []string
+	}{
+		{"directories", (*Entry).IsDirectory, []string{"dir", "db"}},
+		{"tables", (*Entry).IsTable, []string{"table"}},
+		{"topics", (*Entry).IsTopic, []string{"topic"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			es := d.Filter(test.fn)
+			if len(es) != len(test.exp) {
+				t.Fatalf("unexpected entries: %v; want %v", es, test.exp)
+			}
+			for i, e := range es {
+				if e.Name != test.exp[i] {
+					t.Errorf("unexpected entry #%d: %q; want %q", i, e.Name, test.exp[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package scheme
+
+import (
+	"context"
+	"errors"
+	"path"
+)
+
+// SkipDir is used as a return value from WalkFunc to indicate that the
+// directory passed to the call is to be skipped. It is not returned as an
+// error by Walk().
+var SkipDir = errors.New("ydb: scheme: skip this directory")
+
+// WalkFunc is the type of the function called by Walk() for each visited
+// entry. The p argument contains full path of the entry.
+//
+// If WalkFunc returns SkipDir when invoked on a directory, Walk() skips the
+// directory's contents. If it returns any other non-nil error, Walk() stops
+// entirely and returns that error.
+type WalkFunc func(p string, e Entry) error
+
+// Walk walks the scheme tree rooted at root directory, calling fn for each
+// entry in the tree, including root. Entries are visited in the order
+// returned by ListDirectory(); directory is visited before its children.
+//
+// Directories and databases are listed recursively. Note that root must be a
+// directory or database path. Relative root is resolved against the database
+// (see Path()).
+func (c *Client) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	err := c.walk(ctx, c.Path(root), fn)
+	if err == SkipDir {
+		return nil
+	}
+	return err
+}
+
+func (c *Client) walk(ctx context.Context, p string, fn WalkFunc) error {
+	d, err := c.ListDirectory(ctx, p)
+	if err != nil {
+		return err
+	}
+	if err := fn(p, d.Entry); err != nil {
+		return err
+	}
+	for _, child := range d.Children {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cp := path.Join(p, child.Name)
+		if !child.isDir() {
+			if err := fn(cp, child); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.walk(ctx, cp, fn); err != nil && err != SkipDir {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e Entry) isDir() bool {
+	return e.Type == EntryDirectory || e.Type == EntryDatabase
+}
+
+// Node is a node of the scheme tree returned by Tree().
+type Node struct {
+	Entry
+
+	// Path is a full path of the entry.
+	Path string
+
+	// Children contains child nodes of the directory entry.
+	Children []*Node
+}
+
+// Tree returns snapshot of the whole scheme tree rooted at root directory.
+// See Walk() for details.
+func (c *Client) Tree(ctx context.Context, root string) (*Node, error) {
+	var (
+		top   *Node
+		index = make(map[string]*Node)
+	)
+	err := c.Walk(ctx, root, func(p string, e Entry) error {
+		n := &Node{
+			Entry: e,
+			Path:  p,
+		}
+		if top == nil {
+			top = n
+		} else if parent := index[path.Dir(p)]; parent != nil {
+			parent.Children = append(parent.Children, n)
+		}
+		if e.isDir() {
+			index[path.Clean(p)] = n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return top, nil
+}
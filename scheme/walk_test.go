@@ -0,0 +1,89 @@
+package scheme
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Scheme"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestWalk(t *testing.T) {
+	tree := map[string][]*Ydb_Scheme.Entry{
+		"/db": {
+			{Name: "a", Type: Ydb_Scheme.Entry_DIRECTORY},
+			{Name: "t1", Type: Ydb_Scheme.Entry_TABLE},
+			{Name: "skip", Type: Ydb_Scheme.Entry_DIRECTORY},
+		},
+		"/db/a": {
+			{Name: "t2", Type: Ydb_Scheme.Entry_TABLE},
+			{Name: "b", Type: Ydb_Scheme.Entry_DIRECTORY},
+		},
+		"/db/a/b": nil,
+		"/db/skip": {
+			{Name: "t3", Type: Ydb_Scheme.Entry_TABLE},
+		},
+	}
+	client := Client{
+		Driver: &testutil.Driver{
+			OnCall: func(ctx context.Context, _ testutil.MethodCode, req, res interface{}) error {
+				p := req.(*Ydb_Scheme.ListDirectoryRequest).Path
+				r := res.(*Ydb_Scheme.ListDirectoryResult)
+				r.Self = &Ydb_Scheme.Entry{
+					Name: path.Base(p),
+					Type: Ydb_Scheme.Entry_DIRECTORY,
+					Permissions: []*Ydb_Scheme.Permissions{
+						{Subject: "root"},
+					},
+				}
+				r.Children = tree[p]
+				return nil
+			},
+		},
+	}
+
+	var visited []string
+	err := client.Walk(context.Background(), "/db", func(p string, e Entry) error {
+		visited = append(visited, p)
+		if p == "/db/skip" {
+			return SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{
+		"/db",
+		"/db/a",
+		"/db/a/t2",
+		"/db/a/b",
+		"/db/t1",
+		"/db/skip",
+	}
+	if !reflect.DeepEqual(visited, exp) {
+		t.Errorf("unexpected visited paths: %v; want %v", visited, exp)
+	}
+
+	root, err := client.Tree(context.Background(), "/db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump func(*Node) []string
+	dump = func(n *Node) []string {
+		ret := []string{n.Path}
+		for _, c := range n.Children {
+			ret = append(ret, dump(c)...)
+		}
+		return ret
+	}
+	exp = append(exp, "/db/skip/t3")
+	if act := dump(root); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected tree: %v; want %v", act, exp)
+	}
+	if n := len(root.Permissions); n != 1 {
+		t.Errorf("unexpected number of root permissions: %d", n)
+	}
+}
@@ -0,0 +1,235 @@
+package ydb
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// ErrRequestShed is returned by the Shedder when request is rejected on the
+// client side to let overloaded cluster recover.
+var ErrRequestShed = errors.New("ydb: request is shed due to cluster overload")
+
+// Default parameters used by Shedder.
+const (
+	DefaultShedThreshold   = 10
+	DefaultShedWindow      = time.Second
+	DefaultShedCoolDown    = 5 * time.Second
+	DefaultShedProbability = 0.5
+)
+
+// ShedderTrace contains options for tracing Shedder activity.
+type ShedderTrace struct {
+	// ShedStart is called when Shedder engages the shed mode.
+	ShedStart func(ShedStartInfo)
+
+	// ShedDone is called when the shed mode cool-down window is over.
+	ShedDone func(ShedDoneInfo)
+
+	// Reject is called when request is rejected.
+	Reject func(ShedRejectInfo)
+}
+
+type (
+	ShedStartInfo struct {
+		Until time.Time
+	}
+	ShedDoneInfo struct {
+		Rejected uint64
+	}
+	ShedRejectInfo struct {
+		Context context.Context
+	}
+)
+
+// Shedder contains logic of client-side requests shedding. When server
+// responds with StatusOverloaded (or gRPC RESOURCE_EXHAUSTED) at least
+// Threshold times within the Window, Shedder engages the shed mode for the
+// CoolDown duration. In the shed mode new requests are rejected with
+// ErrRequestShed error with given Probability.
+//
+// Shedder may be used as a driver middleware (see CallMiddleware()) or
+// manually via Allow() and Observe() calls.
+//
+// Note that ErrRequestShed is treated by the RetryChecker as retriable with
+// backoff.
+type Shedder struct {
+	// Threshold is a number of overload errors within Window which engages
+	// the shed mode.
+	// If Threshold is zero then the DefaultShedThreshold is used.
+	Threshold int
+
+	// Window is a duration of overload errors accounting.
+	// If Window is zero then the DefaultShedWindow is used.
+	Window time.Duration
+
+	// CoolDown is a duration of the shed mode.
+	// If CoolDown is zero then the DefaultShedCoolDown is used.
+	CoolDown time.Duration
+
+	// Probability is a probability of request rejection in the shed mode.
+	// Its value can be in range (0, 1].
+	// If Probability is zero then the DefaultShedProbability is used.
+	Probability float64
+
+	// Trace contains shedder tracing options.
+	Trace ShedderTrace
+
+	// Clock is a source of time used by the shedder.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	// Source is an optional source of random numbers used to reject
+	// requests. It is useful mostly for testing purposes.
+	// If Source is nil then the rand.NewSource(time.Now().UnixNano()) is
+	// used.
+	Source rand.Source
+
+	once sync.Once
+	mu   sync.Mutex
+	rand *rand.Rand
+
+	windowStart time.Time
+	errors      int
+
+	shedUntil time.Time
+	rejected  uint64
+}
+
+func (s *Shedder) init() {
+	s.once.Do(func() {
+		if s.Threshold <= 0 {
+			s.Threshold = DefaultShedThreshold
+		}
+		if s.Window <= 0 {
+			s.Window = DefaultShedWindow
+		}
+		if s.CoolDown <= 0 {
+			s.CoolDown = DefaultShedCoolDown
+		}
+		if s.Probability <= 0 {
+			s.Probability = DefaultShedProbability
+		}
+		s.Clock = timeutil.ClockOrDefault(s.Clock)
+		if s.Source == nil {
+			s.Source = rand.NewSource(time.Now().UnixNano())
+		}
+		s.rand = rand.New(s.Source)
+	})
+}
+
+// Shedding reports whether the shed mode is engaged.
+func (s *Shedder) Shedding() bool {
+	s.init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shedding(s.Clock.Now())
+}
+
+// Allow returns ErrRequestShed if request must be rejected.
+func (s *Shedder) Allow(ctx context.Context) error {
+	s.init()
+	s.mu.Lock()
+	reject := s.shedding(s.Clock.Now()) && s.rand.Float64() < s.Probability
+	if reject {
+		s.rejected++
+	}
+	s.mu.Unlock()
+	if reject {
+		if f := s.Trace.Reject; f != nil {
+			f(ShedRejectInfo{
+				Context: ctx,
+			})
+		}
+		return ErrRequestShed
+	}
+	return nil
+}
+
+// Observe accounts result of the request.
+func (s *Shedder) Observe(err error) {
+	if !isOverloadError(err) {
+		return
+	}
+	s.init()
+	now := s.Clock.Now()
+
+	s.mu.Lock()
+	if s.shedding(now) {
+		s.mu.Unlock()
+		return
+	}
+	if now.Sub(s.windowStart) >= s.Window {
+		s.windowStart = now
+		s.errors = 0
+	}
+	s.errors++
+	engage := s.errors >= s.Threshold
+	if engage {
+		s.errors = 0
+		s.shedUntil = now.Add(s.CoolDown)
+	}
+	until := s.shedUntil
+	s.mu.Unlock()
+
+	if engage {
+		if f := s.Trace.ShedStart; f != nil {
+			f(ShedStartInfo{
+				Until: until,
+			})
+		}
+	}
+}
+
+// CallMiddleware returns driver middleware which rejects calls in the shed
+// mode and observes results of other calls.
+func (s *Shedder) CallMiddleware() CallMiddleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, op internal.Operation) error {
+			if err := s.Allow(ctx); err != nil {
+				return err
+			}
+			err := next(ctx, op)
+			s.Observe(err)
+			return err
+		}
+	}
+}
+
+// shedding reports whether the shed mode is engaged at the moment of now.
+// It is also responsible for leaving the shed mode.
+// s.mu must be held.
+func (s *Shedder) shedding(now time.Time) bool {
+	if s.shedUntil.IsZero() {
+		return false
+	}
+	if now.Before(s.shedUntil) {
+		return true
+	}
+	rejected := s.rejected
+	s.shedUntil = time.Time{}
+	s.rejected = 0
+	s.windowStart = time.Time{}
+	if f := s.Trace.ShedDone; f != nil {
+		// NOTE: ShedDone is called under the lock to keep events ordered.
+		f(ShedDoneInfo{
+			Rejected: rejected,
+		})
+	}
+	return false
+}
+
+func isOverloadError(err error) bool {
+	if IsOpError(err, StatusOverloaded) {
+		return true
+	}
+	if e, ok := err.(*TransportError); ok {
+		return e.Reason == TransportErrorResourceExhausted
+	}
+	return false
+}
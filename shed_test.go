@@ -0,0 +1,84 @@
+package ydb
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestShedder(t *testing.T) {
+	var (
+		clock = timetest.NewClock(time.Unix(0, 0))
+		start int
+		done  []uint64
+	)
+	s := Shedder{
+		Threshold:   3,
+		Window:      time.Second,
+		CoolDown:    5 * time.Second,
+		Probability: 1,
+		Clock:       clock,
+		Source:      rand.NewSource(0),
+		Trace: ShedderTrace{
+			ShedStart: func(ShedStartInfo) {
+				start++
+			},
+			ShedDone: func(info ShedDoneInfo) {
+				done = append(done, info.Rejected)
+			},
+		},
+	}
+	overloaded := &OpError{Reason: StatusOverloaded}
+	var fail bool
+	call := s.CallMiddleware()(func(context.Context, internal.Operation) error {
+		if fail {
+			return overloaded
+		}
+		return nil
+	})
+	do := func() error {
+		return call(context.Background(), internal.Operation{})
+	}
+
+	// Errors out of window must not engage shedding.
+	fail = true
+	for i := 0; i < 4; i++ {
+		if err := do(); err != overloaded {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clock.Shift(time.Second / 2)
+	}
+	if s.Shedding() {
+		t.Fatalf("unexpected shed mode")
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = do()
+	}
+	if !s.Shedding() || start != 1 {
+		t.Fatalf("shed mode is not engaged")
+	}
+	fail = false
+	for i := 0; i < 2; i++ {
+		if err := do(); err != ErrRequestShed {
+			t.Fatalf("unexpected error: %v; want %v", err, ErrRequestShed)
+		}
+	}
+
+	clock.Shift(5 * time.Second)
+	if err := do(); err != nil {
+		t.Fatalf("unexpected error after cool-down: %v", err)
+	}
+	if len(done) != 1 || done[0] != 2 {
+		t.Fatalf("unexpected shed done events: %v", done)
+	}
+
+	var r RetryChecker
+	if m := r.Check(ErrRequestShed); !m.Retriable() || !m.MustBackoff() {
+		t.Errorf("unexpected retry mode for shed error: %v", m)
+	}
+}
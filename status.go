@@ -0,0 +1,150 @@
+package ydb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+)
+
+// StatusCode reports unsuccessful operation status code.
+type StatusCode int32
+
+func (e StatusCode) String() string {
+	if s, ok := Ydb.StatusIds_StatusCode_name[int32(e)]; ok {
+		return s
+	}
+	return "STATUS_CODE(" + strconv.Itoa(int(e)) + ")"
+}
+
+// Class returns retry class of the status code. It matches the default
+// behavior of the RetryChecker.
+func (e StatusCode) Class() StatusClass {
+	if c, ok := statusClasses[e]; ok {
+		return c
+	}
+	return StatusClassNonRetryable
+}
+
+// Retryable reports whether operation failed with the status code may be
+// retried.
+func (e StatusCode) Retryable() bool {
+	c := e.Class()
+	return c == StatusClassRetryable || c == StatusClassRetryableBackoff
+}
+
+// Errors describing unsusccessful operation status.
+const (
+	StatusUnknownStatus      = StatusCode(Ydb.StatusIds_STATUS_CODE_UNSPECIFIED)
+	StatusSuccess            = StatusCode(Ydb.StatusIds_SUCCESS)
+	StatusBadRequest         = StatusCode(Ydb.StatusIds_BAD_REQUEST)
+	StatusUnauthorized       = StatusCode(Ydb.StatusIds_UNAUTHORIZED)
+	StatusInternalError      = StatusCode(Ydb.StatusIds_INTERNAL_ERROR)
+	StatusAborted            = StatusCode(Ydb.StatusIds_ABORTED)
+	StatusUnavailable        = StatusCode(Ydb.StatusIds_UNAVAILABLE)
+	StatusOverloaded         = StatusCode(Ydb.StatusIds_OVERLOADED)
+	StatusSchemeError        = StatusCode(Ydb.StatusIds_SCHEME_ERROR)
+	StatusGenericError       = StatusCode(Ydb.StatusIds_GENERIC_ERROR)
+	StatusTimeout            = StatusCode(Ydb.StatusIds_TIMEOUT)
+	StatusBadSession         = StatusCode(Ydb.StatusIds_BAD_SESSION)
+	StatusPreconditionFailed = StatusCode(Ydb.StatusIds_PRECONDITION_FAILED)
+	StatusAlreadyExists      = StatusCode(Ydb.StatusIds_ALREADY_EXISTS)
+	StatusNotFound           = StatusCode(Ydb.StatusIds_NOT_FOUND)
+	StatusSessionExpired     = StatusCode(Ydb.StatusIds_SESSION_EXPIRED)
+	StatusCancelled          = StatusCode(Ydb.StatusIds_CANCELLED)
+	StatusUndetermined       = StatusCode(Ydb.StatusIds_UNDETERMINED)
+	StatusUnsupported        = StatusCode(Ydb.StatusIds_UNSUPPORTED)
+	StatusSessionBusy        = StatusCode(Ydb.StatusIds_SESSION_BUSY)
+)
+
+// StatusCodes returns all known status codes in ascending order.
+func StatusCodes() []StatusCode {
+	return []StatusCode{
+		StatusUnknownStatus,
+		StatusSuccess,
+		StatusBadRequest,
+		StatusUnauthorized,
+		StatusInternalError,
+		StatusAborted,
+		StatusUnavailable,
+		StatusOverloaded,
+		StatusSchemeError,
+		StatusGenericError,
+		StatusTimeout,
+		StatusBadSession,
+		StatusPreconditionFailed,
+		StatusAlreadyExists,
+		StatusNotFound,
+		StatusSessionExpired,
+		StatusCancelled,
+		StatusUndetermined,
+		StatusUnsupported,
+		StatusSessionBusy,
+	}
+}
+
+// ParseStatusCode returns status code by its name (e.g. "OVERLOADED") or its
+// numeric value (e.g. "400060"). Names are case insensitive.
+func ParseStatusCode(s string) (StatusCode, error) {
+	if v, ok := Ydb.StatusIds_StatusCode_value[strings.ToUpper(s)]; ok {
+		return StatusCode(v), nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+		if _, ok := Ydb.StatusIds_StatusCode_name[int32(n)]; ok {
+			return StatusCode(n), nil
+		}
+	}
+	return StatusUnknownStatus, fmt.Errorf("ydb: unknown status code: %q", s)
+}
+
+// StatusClass describes whether operation failed with some status code may
+// be retried.
+type StatusClass uint8
+
+const (
+	// StatusClassNonRetryable means that operation must not be retried.
+	StatusClassNonRetryable StatusClass = iota
+
+	// StatusClassSuccess means that operation succeed.
+	StatusClassSuccess
+
+	// StatusClassRetryable means that operation may be retried immediately
+	// (probably with another session).
+	StatusClassRetryable
+
+	// StatusClassRetryableBackoff means that operation may be retried after
+	// backoff.
+	StatusClassRetryableBackoff
+)
+
+func (c StatusClass) String() string {
+	switch c {
+	case StatusClassNonRetryable:
+		return "non-retryable"
+	case StatusClassSuccess:
+		return "success"
+	case StatusClassRetryable:
+		return "retryable"
+	case StatusClassRetryableBackoff:
+		return "retryable-backoff"
+	default:
+		return "unknown"
+	}
+}
+
+var statusClasses = map[StatusCode]StatusClass{
+	StatusSuccess:     StatusClassSuccess,
+	StatusAborted:     StatusClassRetryable,
+	StatusUnavailable: StatusClassRetryable,
+	StatusBadSession:  StatusClassRetryable,
+	StatusSessionBusy: StatusClassRetryable,
+	StatusOverloaded:  StatusClassRetryableBackoff,
+}
+
+func statusCode(s Ydb.StatusIds_StatusCode) StatusCode {
+	if _, ok := Ydb.StatusIds_StatusCode_name[int32(s)]; ok {
+		return StatusCode(s)
+	}
+	return StatusUnknownStatus
+}
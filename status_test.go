@@ -0,0 +1,62 @@
+package ydb
+
+import (
+	"testing"
+)
+
+func TestStatusCodes(t *testing.T) {
+	var r RetryChecker
+	for _, code := range StatusCodes() {
+		parsed, err := ParseStatusCode(code.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != code {
+			t.Errorf("unexpected parsed code: %v; want %v", parsed, code)
+		}
+		if code == StatusSuccess {
+			continue
+		}
+		// Status class must match the default retry checker behavior.
+		m := r.Check(&OpError{Reason: code})
+		if act, exp := code.Retryable(), m.Retriable(); act != exp {
+			t.Errorf("%v: unexpected retryable: %t; want %t", code, act, exp)
+		}
+		if act, exp := code.Class() == StatusClassRetryableBackoff, m.MustBackoff(); act != exp {
+			t.Errorf("%v: unexpected backoff class: %t; want %t", code, act, exp)
+		}
+	}
+}
+
+func TestParseStatusCode(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		code StatusCode
+		fail bool
+	}{
+		{in: "OVERLOADED", code: StatusOverloaded},
+		{in: "session_busy", code: StatusSessionBusy},
+		{in: "400100", code: StatusBadSession},
+		{in: "400001", fail: true},
+		{in: "foo", fail: true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			code, err := ParseStatusCode(test.in)
+			if test.fail {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != test.code {
+				t.Fatalf("unexpected code: %v; want %v", code, test.code)
+			}
+		})
+	}
+	if act, exp := StatusCode(1).String(), "STATUS_CODE(1)"; act != exp {
+		t.Fatalf("unexpected string: %q; want %q", act, exp)
+	}
+}
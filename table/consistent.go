@@ -0,0 +1,237 @@
+package table
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+// DefaultConsistentReadPageSize is the default number of rows read by single
+// query of ConsistentRead().
+var DefaultConsistentReadPageSize = 1000
+
+type (
+	consistentReadDesc struct {
+		pageSize int
+	}
+	ConsistentReadOption func(*consistentReadDesc)
+)
+
+// WithConsistentReadPageSize sets the maximum number of rows read by single
+// query of ConsistentRead(). Note that the server may return less rows than
+// requested (see Ydb.ResultSet's truncated flag); it is handled properly.
+func WithConsistentReadPageSize(n int) ConsistentReadOption {
+	return func(d *consistentReadDesc) {
+		d.pageSize = n
+	}
+}
+
+// ConsistentRead reads all rows of the tables at given paths from the same
+// consistent snapshot of the data. It is intended for application-level
+// logical backups of the related tables when the Export is not available.
+//
+// Tables are read one by one by pages of rows ordered by the primary key.
+// For each page f is called with the table path and the page result, which
+// contains single result set of all table columns. The result is closed
+// after f returns. If f returns error then reading stops and the error is
+// returned.
+//
+// All pages are read within single serializable transaction which is
+// committed at the end. The commit fails if some of the read rows were
+// modified concurrently; in that case the data passed to f is not
+// consistent and the whole read should be repeated (e.g. by Retry()).
+// Thus f should not publish the data until ConsistentRead() returns nil.
+//
+// Note that rows with NULL values in the primary key columns are not read
+// after the first page.
+func (s *Session) ConsistentRead(
+	ctx context.Context, paths []string,
+	f func(path string, res *Result) error,
+	opts ...ConsistentReadOption,
+) (err error) {
+	d := consistentReadDesc{
+		pageSize: DefaultConsistentReadPageSize,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if d.pageSize <= 0 {
+		d.pageSize = DefaultConsistentReadPageSize
+	}
+	// Describe tables before the transaction begins to keep it as short as
+	// possible.
+	descs := make([]Description, len(paths))
+	for i, path := range paths {
+		descs[i], err = s.DescribeTable(ctx, path)
+		if err != nil {
+			return err
+		}
+	}
+	tx, err := s.BeginTransaction(ctx, TxSettings(
+		WithSerializableReadWrite(),
+	))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+	for i, path := range paths {
+		if err = readTablePages(ctx, tx, path, s.c.Path(path), descs[i], d.pageSize, f); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func readTablePages(
+	ctx context.Context, tx *Transaction,
+	path, fullPath string, desc Description, pageSize int,
+	f func(path string, res *Result) error,
+) error {
+	keys := make([]Column, len(desc.PrimaryKey))
+	for i, name := range desc.PrimaryKey {
+		c, ok := findColumn(desc.Columns, name)
+		if !ok {
+			return fmt.Errorf(
+				"ydb: table: primary key column %q of %q is not described",
+				name, path,
+			)
+		}
+		keys[i] = c
+	}
+	var last []ydb.Value
+	for {
+		query := pageQuery(fullPath, keys, last != nil, pageSize)
+		params := NewQueryParameters()
+		for i, v := range last {
+			params.Add(ValueParam(keyParam(i), v))
+		}
+		res, err := tx.Execute(ctx, query, params,
+			WithQueryCachePolicy(WithQueryCachePolicyKeepInCache()),
+		)
+		if err != nil {
+			return err
+		}
+		var set *Ydb.ResultSet
+		if len(res.sets) > 0 {
+			set = res.sets[0]
+		}
+		if len(set.GetRows()) == 0 {
+			_ = res.Close()
+			return nil
+		}
+		more := len(set.Rows) >= pageSize || set.Truncated
+		if more {
+			if last, err = lastKey(set, keys); err != nil {
+				_ = res.Close()
+				return err
+			}
+		}
+		err = f(path, res)
+		_ = res.Close()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// pageQuery returns the text of query which selects the next page of rows
+// ordered by the key columns. If after is true then rows with keys greater
+// than ones passed as keyParam() parameters are selected.
+func pageQuery(path string, keys []Column, after bool, limit int) string {
+	var buf bytes.Buffer
+	if after {
+		for i, c := range keys {
+			fmt.Fprintf(&buf, "DECLARE %s AS ", keyParam(i))
+			internal.WriteTypeStringTo(&buf, c.Type)
+			buf.WriteString(";\n")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("SELECT * FROM ")
+	writeIdent(&buf, path)
+	if after {
+		// Lexicographical comparison of the keys, that is:
+		// (k0 > $k0) OR (k0 = $k0 AND k1 > $k1) OR ...
+		buf.WriteString(" WHERE ")
+		for i := range keys {
+			if i > 0 {
+				buf.WriteString(" OR ")
+			}
+			buf.WriteString("(")
+			for j := 0; j <= i; j++ {
+				if j > 0 {
+					buf.WriteString(" AND ")
+				}
+				writeIdent(&buf, keys[j].Name)
+				if j < i {
+					buf.WriteString(" = ")
+				} else {
+					buf.WriteString(" > ")
+				}
+				buf.WriteString(keyParam(j))
+			}
+			buf.WriteString(")")
+		}
+	}
+	if len(keys) > 0 {
+		buf.WriteString(" ORDER BY ")
+		for i, c := range keys {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeIdent(&buf, c.Name)
+		}
+	}
+	buf.WriteString(" LIMIT ")
+	buf.WriteString(strconv.Itoa(limit))
+	buf.WriteString(";")
+	return buf.String()
+}
+
+// lastKey returns values of the key columns of the last row of the set.
+func lastKey(set *Ydb.ResultSet, keys []Column) ([]ydb.Value, error) {
+	row := set.Rows[len(set.Rows)-1]
+	vs := make([]ydb.Value, len(keys))
+	for i, k := range keys {
+		j := -1
+		for n, c := range set.Columns {
+			if c.Name == k.Name {
+				j = n
+				break
+			}
+		}
+		if j < 0 || j >= len(row.Items) {
+			return nil, fmt.Errorf(
+				"ydb: table: no key column %q in the result set",
+				k.Name,
+			)
+		}
+		vs[i] = internal.ValueFromYDB(set.Columns[j].Type, row.Items[j])
+	}
+	return vs, nil
+}
+
+func findColumn(cs []Column, name string) (Column, bool) {
+	for _, c := range cs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func keyParam(i int) string {
+	return "$k" + strconv.Itoa(i)
+}
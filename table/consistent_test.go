@@ -0,0 +1,138 @@
+package table
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Scheme"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestSessionConsistentRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	idType := internal.TypeToYDB(ydb.Optional(ydb.TypeUint64))
+	page := func(ids ...uint64) *Ydb.ResultSet {
+		set := &Ydb.ResultSet{
+			Columns: []*Ydb.Column{
+				{Name: "id", Type: idType},
+			},
+		}
+		for _, id := range ids {
+			v := internal.ValueToYDB(ydb.OptionalValue(ydb.Uint64Value(id)))
+			set.Rows = append(set.Rows, &Ydb.Value{
+				Items: []*Ydb.Value{v.Value},
+			})
+		}
+		return set
+	}
+	pages := []*Ydb.ResultSet{
+		page(1, 2),
+		page(3),
+	}
+	var (
+		queries   []string
+		params    []map[string]*Ydb.TypedValue
+		committed bool
+	)
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TableDescribeTable: func(req, res interface{}) error {
+				*res.(*Ydb_Table.DescribeTableResult) = Ydb_Table.DescribeTableResult{
+					Self: &Ydb_Scheme.Entry{
+						Name: "series",
+					},
+					Columns: []*Ydb_Table.ColumnMeta{
+						{Name: "id", Type: idType},
+					},
+					PrimaryKey: []string{"id"},
+				}
+				return nil
+			},
+			testutil.TableBeginTransaction: func(req, res interface{}) error {
+				testutil.TableBeginTransactionResult{R: res}.SetTransactionID("tx")
+				return nil
+			},
+			testutil.TableExecuteDataQuery: func(req, res interface{}) error {
+				q := req.(*Ydb_Table.ExecuteDataQueryRequest)
+				queries = append(queries, q.Query.GetYqlText())
+				params = append(params, q.Parameters)
+
+				r := res.(*Ydb_Table.ExecuteQueryResult)
+				r.TxMeta = &Ydb_Table.TransactionMeta{Id: "tx"}
+				r.ResultSets = []*Ydb.ResultSet{pages[0]}
+				pages = pages[1:]
+				return nil
+			},
+			testutil.TableCommitTransaction: func(req, res interface{}) error {
+				committed = true
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []int
+	err = s.ConsistentRead(ctx, []string{"series"},
+		func(path string, res *Result) error {
+			if path != "series" {
+				t.Errorf("unexpected path: %q", path)
+			}
+			rows = append(rows, res.RowCount())
+			return nil
+		},
+		WithConsistentReadPageSize(2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !committed {
+		t.Fatalf("transaction is not committed")
+	}
+	if exp := []int{2, 1}; !reflect.DeepEqual(rows, exp) {
+		t.Fatalf("unexpected page rows: %v; want %v", rows, exp)
+	}
+	if n := len(queries); n != 2 {
+		t.Fatalf("unexpected number of queries: %d", n)
+	}
+	if q, exp := queries[0], "SELECT * FROM `series` ORDER BY `id` LIMIT 2;"; q != exp {
+		t.Errorf("unexpected first query: %q; want %q", q, exp)
+	}
+	if q := queries[1]; !strings.Contains(q, "WHERE (`id` > $k0) ORDER BY `id` LIMIT 2;") {
+		t.Errorf("unexpected second query: %q", q)
+	}
+	k := params[1]["$k0"]
+	act := fmt.Sprint(internal.ValueFromYDB(k.Type, k.Value))
+	if exp := fmt.Sprint(ydb.OptionalValue(ydb.Uint64Value(2))); act != exp {
+		t.Errorf("unexpected key param: %s; want %s", act, exp)
+	}
+}
+
+func TestPageQuery(t *testing.T) {
+	keys := []Column{
+		{Name: "a", Type: ydb.Optional(ydb.TypeUint64)},
+		{Name: "b", Type: ydb.TypeUTF8},
+	}
+	act := pageQuery("/db/t", keys, true, 10)
+	exp := strings.Join([]string{
+		"DECLARE $k0 AS Optional<Uint64>;",
+		"DECLARE $k1 AS Utf8;",
+		"",
+		"SELECT * FROM `/db/t` WHERE (`a` > $k0) OR (`a` = $k0 AND `b` > $k1) ORDER BY `a`, `b` LIMIT 10;",
+	}, "\n")
+	if act != exp {
+		t.Errorf("unexpected query:\n%s\nwant:\n%s", act, exp)
+	}
+}
@@ -1 +1,44 @@
+// Package table contains the high-level client of the YDB table service.
+//
+// Client creates sessions over the ydb.Driver. Session provides methods for
+// schema management, data queries execution, prepared statements and
+// transactions. Query results are read with the Result type, which has typed
+// accessors for the values of current row:
+//
+//     c := table.Client{Driver: driver}
+//     s, err := c.CreateSession(ctx)
+//     if err != nil {
+//         return err
+//     }
+//     defer s.Close(ctx)
+//
+//     stmt, err := s.Prepare(ctx, `
+//         DECLARE $id AS Uint64;
+//         SELECT title FROM series WHERE series_id = $id;
+//     `)
+//     if err != nil {
+//         return err
+//     }
+//     _, res, err := stmt.Execute(ctx,
+//         table.TxControl(
+//             table.BeginTx(table.WithSerializableReadWrite()),
+//             table.CommitTx(),
+//         ),
+//         table.NewQueryParameters(
+//             table.ValueParam("$id", ydb.Uint64Value(1)),
+//         ),
+//     )
+//     if err != nil {
+//         return err
+//     }
+//     for res.NextSet() {
+//         for res.NextRow() {
+//             res.SeekItem("title")
+//             fmt.Println(res.OUTF8())
+//         }
+//     }
+//     return res.Err()
+//
+// Sessions are usually reused through SessionPool and Retry() helper, which
+// retries operations on retryable errors.
 package table
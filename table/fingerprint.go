@@ -0,0 +1,195 @@
+package table
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// NormalizeQuery returns normalized form of the given YQL text.
+//
+// Normalization strips comments, collapses whitespace into single spaces and
+// replaces string and numeric literals with the "?" placeholder. Comma
+// separated lists of literals (such as in "IN (1, 2, 3)") are collapsed into a
+// single placeholder. Identifiers, parameter names and keywords are left
+// untouched.
+//
+// Queries which differ only in literal values have the same normalized form.
+// That is, normalized text is suitable for grouping similar queries in logs
+// and metrics.
+func NormalizeQuery(yql string) string {
+	var (
+		n queryNormalizer
+		s = yql
+	)
+	for len(s) > 0 {
+		s = n.next(s)
+	}
+	return n.b.String()
+}
+
+// QueryFingerprint returns stable short hash of the normalized YQL text. See
+// NormalizeQuery() for the details of normalization.
+func QueryFingerprint(yql string) string {
+	h := sha256.Sum256([]byte(NormalizeQuery(yql)))
+	return hex.EncodeToString(h[:8])
+}
+
+type queryToken uint8
+
+const (
+	tokenNone queryToken = iota
+	tokenLiteral
+	tokenComma
+	tokenOther
+)
+
+type queryNormalizer struct {
+	b     strings.Builder
+	space bool
+
+	// last and prev are the last two tokens written to the buffer.
+	last queryToken
+	prev queryToken
+}
+
+func (n *queryNormalizer) next(s string) string {
+	c := s[0]
+	switch {
+	case isQuerySpace(c):
+		n.space = true
+		return s[1:]
+
+	case strings.HasPrefix(s, "--"):
+		n.space = true
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			return s[i+1:]
+		}
+		return ""
+
+	case strings.HasPrefix(s, "/*"):
+		n.space = true
+		if i := strings.Index(s[2:], "*/"); i >= 0 {
+			return s[2+i+2:]
+		}
+		return ""
+
+	case strings.HasPrefix(s, "@@"):
+		// Multiline string literal where "@@@@" is an escaped "@@".
+		i := 2
+		for {
+			j := strings.Index(s[i:], "@@")
+			if j < 0 {
+				return n.literal("")
+			}
+			i += j + 2
+			if !strings.HasPrefix(s[i:], "@@") {
+				return n.literal(s[i:])
+			}
+			i += 2
+		}
+
+	case c == '\'' || c == '"':
+		i := 1
+		for i < len(s) && s[i] != c {
+			if s[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(s) {
+			return n.literal("")
+		}
+		return n.literal(s[i+1:])
+
+	case c == '`':
+		// Quoted identifier.
+		i := strings.IndexByte(s[1:], '`')
+		if i < 0 {
+			return n.other(s, len(s))
+		}
+		return n.other(s, i+2)
+
+	case c == '-' && len(s) > 1 && isQueryDigit(s[1]) && n.signed():
+		return n.literal(s[1+queryWordLen(s[1:]):])
+
+	case isQueryDigit(c):
+		return n.literal(s[queryWordLen(s):])
+
+	case c == '$' || isQueryWord(c):
+		return n.other(s, 1+queryWordLen(s[1:]))
+
+	case c == ',':
+		return n.write(tokenComma, s, 1)
+
+	default:
+		return n.other(s, 1)
+	}
+}
+
+// signed reports whether minus sign at current position is a sign of numeric
+// literal rather than a binary operator.
+func (n *queryNormalizer) signed() bool {
+	if n.last != tokenOther {
+		return true
+	}
+	str := n.b.String()
+	switch str[len(str)-1] {
+	case '(', '=', '<', '>', '[', '{', '+', '-', '*', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (n *queryNormalizer) literal(rest string) string {
+	if n.last == tokenComma && n.prev == tokenLiteral {
+		// Collapse list of literals into single placeholder.
+		str := strings.TrimRight(n.b.String(), ", ")
+		n.b.Reset()
+		n.b.WriteString(str)
+		n.last, n.prev = tokenLiteral, tokenNone
+		n.space = false
+		return rest
+	}
+	if n.last == tokenLiteral {
+		n.space = false
+		return rest
+	}
+	n.write(tokenLiteral, "?", 1)
+	return rest
+}
+
+func (n *queryNormalizer) other(s string, size int) string {
+	return n.write(tokenOther, s, size)
+}
+
+func (n *queryNormalizer) write(t queryToken, s string, size int) string {
+	if n.space && n.last != tokenNone {
+		n.b.WriteByte(' ')
+	}
+	n.space = false
+	n.b.WriteString(s[:size])
+	n.prev, n.last = n.last, t
+	return s[size:]
+}
+
+func queryWordLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		if !isQueryWord(s[i]) && !isQueryDigit(s[i]) && s[i] != '.' {
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isQuerySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isQueryDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isQueryWord(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
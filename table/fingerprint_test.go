@@ -0,0 +1,67 @@
+package table
+
+import "testing"
+
+func TestNormalizeQuery(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		exp   string
+	}{
+		{
+			query: "SELECT 1",
+			exp:   "SELECT ?",
+		},
+		{
+			query: "  SELECT\n\t*  FROM `my/table`\r\n WHERE id = 42;  ",
+			exp:   "SELECT * FROM `my/table` WHERE id = ?;",
+		},
+		{
+			query: "SELECT * FROM t WHERE name = 'it\\'s' AND title = \"x\"",
+			exp:   "SELECT * FROM t WHERE name = ? AND title = ?",
+		},
+		{
+			query: "SELECT @@multi\nline @@@@ string@@ AS s",
+			exp:   "SELECT ? AS s",
+		},
+		{
+			query: "SELECT * FROM t WHERE id IN (1, 2u, 3.5, -4)",
+			exp:   "SELECT * FROM t WHERE id IN (?)",
+		},
+		{
+			query: "SELECT a - 1, b1, $p2 FROM t2 WHERE x > -10",
+			exp:   "SELECT a - ?, b1, $p2 FROM t2 WHERE x > ?",
+		},
+		{
+			query: "-- comment\nSELECT /* inline\ncomment */ Date('2020-01-01') -- tail",
+			exp:   "SELECT Date(?)",
+		},
+		{
+			query: "DECLARE $id AS Uint64;\nSELECT * FROM t WHERE id = $id LIMIT 10",
+			exp:   "DECLARE $id AS Uint64; SELECT * FROM t WHERE id = $id LIMIT ?",
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			if act := NormalizeQuery(test.query); act != test.exp {
+				t.Errorf(
+					"unexpected normalized query:\n%q\nwant:\n%q",
+					act, test.exp,
+				)
+			}
+		})
+	}
+}
+
+func TestQueryFingerprint(t *testing.T) {
+	a := QueryFingerprint("SELECT * FROM t WHERE id IN (1, 2, 3)")
+	b := QueryFingerprint("select_placeholder")
+	c := QueryFingerprint("  SELECT *\nFROM t WHERE id IN (42)")
+	if a != c {
+		t.Errorf("fingerprints of similar queries differ: %q vs %q", a, c)
+	}
+	if a == b {
+		t.Errorf("fingerprints of different queries are equal: %q", a)
+	}
+	if n := len(a); n != 16 {
+		t.Errorf("unexpected fingerprint length: %d", n)
+	}
+}
@@ -0,0 +1,65 @@
+package table
+
+import (
+	"context"
+	"errors"
+	"runtime"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal/cache/lru"
+)
+
+// ErrSessionDetached is returned by Session.Detach() to indicate that session
+// is already closed or detached.
+var ErrSessionDetached = errors.New("ydb: table: session is closed or detached")
+
+// Detach detaches the server side session from s and returns its id, which
+// then may be passed to Client.AttachSession() of this or another process.
+//
+// After Detach() the s must not be used. Its Close() does not delete the
+// session on the server and its OnClose() callbacks are called immediately.
+// That is, session received from SessionPool is removed from the pool.
+// Session's prepared statements are not transferred.
+//
+// Detach is experimental. It is useful for hand-off of the stateful workers
+// during deploys. Note that server deletes session after being idle for a
+// while, so the session must be attached again before the server idle timeout
+// expires.
+func (s *Session) Detach() (id string, err error) {
+	if s.closed {
+		return "", ErrSessionDetached
+	}
+	s.closed = true
+	runtime.SetFinalizer(s, nil)
+	for _, cb := range s.onClose {
+		cb()
+	}
+	return s.ID, nil
+}
+
+// AttachSession returns session instance for the existing server side session
+// with given id, such as one returned by Session.Detach(). It checks that
+// session is still alive by KeepAlive() call.
+//
+// Note that the attached session does not belong to any SessionPool. Like
+// sessions created by CreateSession(), it must be destroyed by Close() call
+// or detached again.
+//
+// AttachSession is experimental. See Session.Detach() for details.
+func (t *Client) AttachSession(ctx context.Context, id string) (s *Session, err error) {
+	s = &Session{
+		ID: id,
+		c:  *t,
+		qcache: lru.Cache{
+			MaxSize: t.cacheSize(),
+		},
+	}
+	if _, err = s.KeepAlive(ctx); err != nil {
+		return nil, err
+	}
+	runtime.SetFinalizer(s, func(s *Session) {
+		go func() {
+			_ = s.Close(context.Background())
+		}()
+	})
+	return s, nil
+}
@@ -0,0 +1,79 @@
+package table
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestSessionDetachAttach(t *testing.T) {
+	var (
+		calls []testutil.MethodCode
+		ids   []string
+		fail  error
+	)
+	c := Client{
+		Driver: &testutil.Driver{
+			OnCall: func(_ context.Context, m testutil.MethodCode, req, res interface{}) error {
+				calls = append(calls, m)
+				switch m {
+				case testutil.TableCreateSession:
+					res.(*Ydb_Table.CreateSessionResult).SessionId = "session"
+				case testutil.TableKeepAlive:
+					ids = append(ids, req.(*Ydb_Table.KeepAliveRequest).SessionId)
+					res.(*Ydb_Table.KeepAliveResult).SessionStatus = Ydb_Table.KeepAliveResult_SESSION_STATUS_READY
+					return fail
+				}
+				return nil
+			},
+		},
+	}
+	ctx := context.Background()
+
+	s, err := c.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var closed bool
+	s.OnClose(func() {
+		closed = true
+	})
+	id, err := s.Detach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "session" {
+		t.Fatalf("unexpected session id: %q", id)
+	}
+	if !closed {
+		t.Fatalf("OnClose() callback is not called")
+	}
+	if _, err := s.Detach(); err != ErrSessionDetached {
+		t.Fatalf("unexpected error: %v; want %v", err, ErrSessionDetached)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(calls); n != 1 {
+		t.Fatalf("unexpected calls after detach: %v", calls[1:])
+	}
+
+	x, err := c.AttachSession(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.ID != id {
+		t.Fatalf("unexpected attached session id: %q", x.ID)
+	}
+	if len(ids) != 1 || ids[0] != id {
+		t.Fatalf("unexpected keep alive requests: %v", ids)
+	}
+
+	fail = errors.New("bad session")
+	if _, err := c.AttachSession(ctx, "unknown"); err != fail {
+		t.Fatalf("unexpected error: %v; want %v", err, fail)
+	}
+}
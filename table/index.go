@@ -0,0 +1,79 @@
+package table
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+)
+
+var errNoIndexColumns = errors.New("ydb: table: no index columns")
+
+// indexDescription converts index description received from the server.
+func indexDescription(x *Ydb_Table.TableIndex) IndexDescription {
+	d := IndexDescription{
+		Name:         x.Name,
+		IndexColumns: x.IndexColumns,
+	}
+	switch x.Type.(type) {
+	case *Ydb_Table.TableIndex_GlobalIndex:
+		d.Type = globalIndex{}
+	}
+	return d
+}
+
+// addIndexQuery returns the text of scheme query which adds index to the
+// table at given (full) path.
+//
+// Note that AlterTable request has no fields to manage indexes, thus they are
+// altered by the scheme queries.
+func addIndexQuery(path, name string, opts ...IndexOption) (string, error) {
+	d := indexDesc{
+		Name: name,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if len(d.IndexColumns) == 0 {
+		return "", errNoIndexColumns
+	}
+	var buf bytes.Buffer
+	buf.WriteString("ALTER TABLE ")
+	writeIdent(&buf, path)
+	buf.WriteString(" ADD INDEX ")
+	writeIdent(&buf, name)
+	// GLOBAL is the only index type supported by now; it is also used when
+	// no type is given.
+	buf.WriteString(" GLOBAL ON (")
+	for i, c := range d.IndexColumns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeIdent(&buf, c)
+	}
+	buf.WriteString(");")
+	return buf.String(), nil
+}
+
+// dropIndexQuery returns the text of scheme query which drops index of the
+// table at given (full) path.
+func dropIndexQuery(path, name string) string {
+	var buf bytes.Buffer
+	buf.WriteString("ALTER TABLE ")
+	writeIdent(&buf, path)
+	buf.WriteString(" DROP INDEX ")
+	writeIdent(&buf, name)
+	buf.WriteString(";")
+	return buf.String()
+}
+
+func writeIdent(buf *bytes.Buffer, name string) {
+	buf.WriteByte('`')
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c == '`' || c == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(name[i])
+	}
+	buf.WriteByte('`')
+}
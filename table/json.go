@@ -0,0 +1,124 @@
+package table
+
+import (
+	"bytes"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/internal/result"
+)
+
+// Row is a snapshot of the result row. It implements fmt.Stringer and
+// json.Marshaler interfaces.
+//
+// Row is marshaled as JSON object with column names as keys. Numeric values
+// are marshaled as numbers (Interval as number of microseconds), optional
+// values as underlying value or null, Date, Datetime and Timestamp values as
+// RFC3339 strings, String values as base64 strings, Json values as is,
+// Decimal and Uuid values as strings.
+type Row struct {
+	cols []*Ydb.Column
+	row  *Ydb.Value
+}
+
+// String returns human-readable representation of the row.
+func (r Row) String() string {
+	if r.row == nil {
+		return "{}"
+	}
+	return internal.FormatRow(r.cols, r.row)
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (r Row) MarshalJSON() ([]byte, error) {
+	if r.row == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	if err := internal.WriteRowJSON(&buf, r.cols, r.row); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ResultSet is a snapshot of the result set. It implements fmt.Stringer and
+// json.Marshaler interfaces.
+//
+// ResultSet is marshaled as JSON array of rows (see Row for details).
+type ResultSet struct {
+	set *Ydb.ResultSet
+}
+
+// Rows returns rows of the result set.
+func (s ResultSet) Rows() []Row {
+	if s.set == nil {
+		return nil
+	}
+	rs := make([]Row, len(s.set.Rows))
+	for i, row := range s.set.Rows {
+		rs[i] = Row{
+			cols: s.set.Columns,
+			row:  row,
+		}
+	}
+	return rs
+}
+
+// String returns human-readable representation of the result set.
+func (s ResultSet) String() string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, r := range s.Rows() {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(r.String())
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (s ResultSet) MarshalJSON() ([]byte, error) {
+	if s.set == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range s.set.Rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := internal.WriteRowJSON(&buf, s.set.Columns, row); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// CurrentRow returns snapshot of the current row. It may be then marshaled
+// to JSON or printed.
+//
+// Note that it does not affect the scanning position within the row.
+func (r *Result) CurrentRow() Row {
+	set, row := result.Current(&r.Scanner)
+	if set == nil {
+		return Row{}
+	}
+	return Row{
+		cols: set.Columns,
+		row:  row,
+	}
+}
+
+// CurrentSet returns snapshot of the current result set. It may be then
+// marshaled to JSON or printed.
+//
+// Note that it does not affect the scanning position within the set.
+func (r *Result) CurrentSet() ResultSet {
+	set, _ := result.Current(&r.Scanner)
+	return ResultSet{
+		set: set,
+	}
+}
@@ -0,0 +1,75 @@
+package table
+
+import (
+	"encoding/json"
+	"testing"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+)
+
+func TestResultJSON(t *testing.T) {
+	res := NewResult(
+		NewResultSet(
+			WithColumns(
+				Column{Name: "id", Type: ydb.TypeUint64},
+				Column{Name: "name", Type: ydb.Optional(ydb.TypeUTF8)},
+				Column{Name: "tags", Type: ydb.List(ydb.TypeUTF8)},
+				Column{Name: "data", Type: ydb.Optional(ydb.TypeJSON)},
+				Column{Name: "created", Type: ydb.TypeTimestamp},
+			),
+			WithValues(
+				ydb.Uint64Value(1),
+				ydb.OptionalValue(ydb.UTF8Value("foo")),
+				ydb.ListValue(ydb.UTF8Value("a"), ydb.UTF8Value("b")),
+				ydb.OptionalValue(ydb.JSONValue(`{"x": [1, 2]}`)),
+				ydb.TimestampValue(1500000),
+
+				ydb.Uint64Value(2),
+				ydb.NullValue(ydb.TypeUTF8),
+				ydb.ZeroValue(ydb.List(ydb.TypeUTF8)),
+				ydb.NullValue(ydb.TypeJSON),
+				ydb.TimestampValue(0),
+			),
+		),
+	)
+	if !res.NextSet() {
+		t.Fatal("no result set")
+	}
+	if !res.NextRow() {
+		t.Fatal("no result row")
+	}
+
+	p, err := json.Marshal(res.CurrentRow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(p), `{"id":1,"name":"foo","tags":["a","b"],`+
+		`"data":{"x":[1,2]},"created":"1970-01-01T00:00:01.5Z"}`; act != exp {
+		t.Errorf("unexpected row JSON:\n%s\nwant:\n%s", act, exp)
+	}
+	if act, exp := res.CurrentRow().String(), `{id: 1, name: "foo", tags: ["a", "b"], `+
+		`data: {"x": [1, 2]}, created: 1970-01-01T00:00:01.5Z}`; act != exp {
+		t.Errorf("unexpected row string:\n%s\nwant:\n%s", act, exp)
+	}
+
+	p, err = json.Marshal(res.CurrentSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(p, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(rows); n != 2 {
+		t.Fatalf("unexpected number of rows: %d", n)
+	}
+	if name := rows[1]["name"]; name != nil {
+		t.Errorf("unexpected name of the second row: %v; want null", name)
+	}
+
+	// Ensure that scanning position is not affected.
+	res.NextItem()
+	if id := res.Uint64(); id != 1 {
+		t.Errorf("unexpected id: %d", id)
+	}
+}
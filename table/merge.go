@@ -0,0 +1,322 @@
+package table
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/internal/result"
+)
+
+// ResultMerger merges rows of multiple results ordered by the same key into a
+// single ordered sequence of rows.
+//
+// It is useful when a query is split into parallel per-shard or
+// per-partition queries (or reads), each of which returns rows ordered by the
+// key:
+//
+//     m := table.NewResultMerger([]string{"id"}, res1, res2, res3)
+//     defer m.Close()
+//     for m.NextRow(ctx) {
+//         res := m.Result()
+//         res.SeekItem("id")
+//         id := res.OUint64()
+//         res.SeekItem("name")
+//         name := res.OUTF8()
+//     }
+//     if err := m.Err(); err != nil {
+//         // handle error
+//     }
+//
+// Results may be either results of regular or streaming operations. Result
+// sets of each result are read in turn.
+//
+// Key columns must be of the same primitive (or optional primitive or
+// decimal) types among all results. Rows with equal keys are emitted in order
+// of results passed to NewResultMerger().
+type ResultMerger struct {
+	key  []string
+	keyT []*Ydb.Type
+
+	cursors []*mergeCursor
+	heap    mergeHeap
+	current *mergeCursor
+
+	started bool
+	err     error
+}
+
+// NewResultMerger creates new merger of rows of given results ordered by the
+// given key columns.
+func NewResultMerger(key []string, rs ...*Result) *ResultMerger {
+	m := &ResultMerger{
+		key:     key,
+		keyT:    make([]*Ydb.Type, len(key)),
+		cursors: make([]*mergeCursor, len(rs)),
+	}
+	for i, r := range rs {
+		m.cursors[i] = &mergeCursor{
+			index: i,
+			res:   r,
+			key:   make([]*Ydb.Value, len(key)),
+		}
+	}
+	m.heap.keyT = m.keyT
+	return m
+}
+
+// NextRow selects next row in key order among all results.
+// It returns false if there are no more rows or an error occurred.
+func (m *ResultMerger) NextRow(ctx context.Context) bool {
+	if m.err != nil {
+		return false
+	}
+	if !m.started {
+		m.started = true
+		for _, c := range m.cursors {
+			m.advance(ctx, c)
+		}
+	} else if c := m.current; c != nil {
+		m.advance(ctx, c)
+	}
+	m.current = nil
+	if m.err != nil || m.heap.Len() == 0 {
+		return false
+	}
+	m.current = heap.Pop(&m.heap).(*mergeCursor)
+	return true
+}
+
+// Result returns the result positioned at the row selected by last call
+// to NextRow().
+// Note that position of items within selected row is kept untouched.
+func (m *ResultMerger) Result() *Result {
+	if m.current == nil {
+		return nil
+	}
+	return m.current.res
+}
+
+// Err returns error encountered during merge.
+func (m *ResultMerger) Err() error {
+	return m.err
+}
+
+// Close closes all merged results.
+func (m *ResultMerger) Close() error {
+	for _, c := range m.cursors {
+		_ = c.res.Close()
+	}
+	return nil
+}
+
+func (m *ResultMerger) advance(ctx context.Context, c *mergeCursor) {
+	r := c.res
+	for !r.NextRow() {
+		var ok bool
+		if r.setCh != nil {
+			ok = r.NextStreamSet(ctx)
+		} else {
+			ok = r.NextSet()
+		}
+		if !ok {
+			if err := r.Err(); err != nil {
+				m.err = err
+			} else if err := ctx.Err(); err != nil {
+				m.err = err
+			}
+			return
+		}
+	}
+	for i, name := range m.key {
+		t, v, ok := result.Item(&r.Scanner, name)
+		if !ok {
+			m.err = fmt.Errorf(
+				"ydb: table: no key column %q in result #%d",
+				name, c.index,
+			)
+			return
+		}
+		if err := m.checkKeyType(i, t); err != nil {
+			m.err = fmt.Errorf(
+				"ydb: table: key column %q in result #%d: %v",
+				name, c.index, err,
+			)
+			return
+		}
+		c.key[i] = v
+	}
+	heap.Push(&m.heap, c)
+}
+
+func (m *ResultMerger) checkKeyType(i int, t *Ydb.Type) error {
+	if p := m.keyT[i]; p != nil {
+		if !internal.TypesEqual(internal.TypeFromYDB(p), internal.TypeFromYDB(t)) {
+			return fmt.Errorf(
+				"type mismatch: %s; want %s",
+				internal.TypeFromYDB(t), internal.TypeFromYDB(p),
+			)
+		}
+		return nil
+	}
+	if !comparableType(t) {
+		return fmt.Errorf("type is not comparable: %s", internal.TypeFromYDB(t))
+	}
+	m.keyT[i] = t
+	return nil
+}
+
+type mergeCursor struct {
+	index int
+	res   *Result
+	key   []*Ydb.Value
+}
+
+type mergeHeap struct {
+	keyT    []*Ydb.Type
+	cursors []*mergeCursor
+}
+
+func (h mergeHeap) Len() int      { return len(h.cursors) }
+func (h mergeHeap) Swap(i, j int) { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+
+func (h mergeHeap) Less(i, j int) bool {
+	a := h.cursors[i]
+	b := h.cursors[j]
+	for k, t := range h.keyT {
+		if c := compareValues(t, a.key[k], b.key[k]); c != 0 {
+			return c < 0
+		}
+	}
+	return a.index < b.index
+}
+
+func (h *mergeHeap) Push(x interface{}) {
+	h.cursors = append(h.cursors, x.(*mergeCursor))
+}
+
+func (h *mergeHeap) Pop() interface{} {
+	p := h.cursors
+	n := len(p)
+	x := p[n-1]
+	h.cursors = p[:n-1]
+	return x
+}
+
+func comparableType(t *Ydb.Type) bool {
+	switch x := t.Type.(type) {
+	case *Ydb.Type_TypeId, *Ydb.Type_DecimalType:
+		return true
+	case *Ydb.Type_OptionalType:
+		return comparableType(x.OptionalType.Item)
+	default:
+		return false
+	}
+}
+
+// compareValues compares a and b of type t.
+// Null values are less than any other values.
+// t must be comparable (see comparableType()).
+func compareValues(t *Ydb.Type, a, b *Ydb.Value) int {
+	switch x := t.Type.(type) {
+	case *Ydb.Type_OptionalType:
+		an := isNullValue(a)
+		bn := isNullValue(b)
+		switch {
+		case an && bn:
+			return 0
+		case an:
+			return -1
+		case bn:
+			return 1
+		}
+		return compareValues(x.OptionalType.Item, unwrapValue(a), unwrapValue(b))
+
+	case *Ydb.Type_DecimalType:
+		if ah, bh := int64(a.High_128), int64(b.High_128); ah != bh {
+			return compareOrdered(ah < bh)
+		}
+		return compareUint64(a.GetLow_128(), b.GetLow_128())
+	}
+
+	switch av := a.Value.(type) {
+	case *Ydb.Value_BoolValue:
+		bv := b.GetBoolValue()
+		if av.BoolValue == bv {
+			return 0
+		}
+		return compareOrdered(!av.BoolValue)
+	case *Ydb.Value_Int32Value:
+		return compareInt64(int64(av.Int32Value), int64(b.GetInt32Value()))
+	case *Ydb.Value_Uint32Value:
+		return compareUint64(uint64(av.Uint32Value), uint64(b.GetUint32Value()))
+	case *Ydb.Value_Int64Value:
+		return compareInt64(av.Int64Value, b.GetInt64Value())
+	case *Ydb.Value_Uint64Value:
+		return compareUint64(av.Uint64Value, b.GetUint64Value())
+	case *Ydb.Value_FloatValue:
+		return compareFloat64(float64(av.FloatValue), float64(b.GetFloatValue()))
+	case *Ydb.Value_DoubleValue:
+		return compareFloat64(av.DoubleValue, b.GetDoubleValue())
+	case *Ydb.Value_BytesValue:
+		return compareString(string(av.BytesValue), string(b.GetBytesValue()))
+	case *Ydb.Value_TextValue:
+		return compareString(av.TextValue, b.GetTextValue())
+	case *Ydb.Value_Low_128:
+		if a.High_128 != b.High_128 {
+			return compareOrdered(a.High_128 < b.High_128)
+		}
+		return compareUint64(av.Low_128, b.GetLow_128())
+	default:
+		return 0
+	}
+}
+
+func isNullValue(v *Ydb.Value) bool {
+	_, ok := v.Value.(*Ydb.Value_NullFlagValue)
+	return ok
+}
+
+func unwrapValue(v *Ydb.Value) *Ydb.Value {
+	if x, ok := v.Value.(*Ydb.Value_NestedValue); ok {
+		return x.NestedValue
+	}
+	return v
+}
+
+func compareOrdered(less bool) int {
+	if less {
+		return -1
+	}
+	return 1
+}
+
+func compareInt64(a, b int64) int {
+	if a == b {
+		return 0
+	}
+	return compareOrdered(a < b)
+}
+
+func compareUint64(a, b uint64) int {
+	if a == b {
+		return 0
+	}
+	return compareOrdered(a < b)
+}
+
+func compareFloat64(a, b float64) int {
+	if a == b {
+		return 0
+	}
+	return compareOrdered(a < b)
+}
+
+func compareString(a, b string) int {
+	if a == b {
+		return 0
+	}
+	return compareOrdered(a < b)
+}
@@ -0,0 +1,90 @@
+package table
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+)
+
+func TestResultMerger(t *testing.T) {
+	columns := WithColumns(
+		Column{Name: "id", Type: ydb.Optional(ydb.TypeUint64)},
+		Column{Name: "src", Type: ydb.TypeUTF8},
+	)
+	row := func(id uint64, src string) []ydb.Value {
+		return []ydb.Value{
+			ydb.OptionalValue(ydb.Uint64Value(id)),
+			ydb.UTF8Value(src),
+		}
+	}
+	rows := func(rs ...[]ydb.Value) (vs []ydb.Value) {
+		for _, r := range rs {
+			vs = append(vs, r...)
+		}
+		return vs
+	}
+	m := NewResultMerger([]string{"id"},
+		NewResult(
+			NewResultSet(columns, WithValues(rows(
+				[]ydb.Value{ydb.NullValue(ydb.TypeUint64), ydb.UTF8Value("a")},
+				row(1, "a"),
+				row(4, "a"),
+			)...)),
+			NewResultSet(columns, WithValues(rows(
+				row(5, "a"),
+			)...)),
+		),
+		NewResult(),
+		NewResult(
+			NewResultSet(columns, WithValues(rows(
+				row(2, "c"),
+				row(4, "c"),
+				row(6, "c"),
+			)...)),
+		),
+	)
+	defer m.Close()
+
+	var act []string
+	for m.NextRow(context.Background()) {
+		res := m.Result()
+		res.SeekItem("id")
+		id := "NULL"
+		if !res.IsNull() {
+			id = string(rune('0' + res.OUint64()))
+		}
+		res.SeekItem("src")
+		act = append(act, id+res.UTF8())
+		if err := res.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Err(); err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"NULLa", "1a", "2c", "4a", "4c", "5a", "6c"}
+	if !reflect.DeepEqual(act, exp) {
+		t.Fatalf("unexpected rows order: %v; want %v", act, exp)
+	}
+}
+
+func TestResultMergerKeyTypeMismatch(t *testing.T) {
+	m := NewResultMerger([]string{"id"},
+		NewResult(NewResultSet(
+			WithColumns(Column{Name: "id", Type: ydb.TypeUint64}),
+			WithValues(ydb.Uint64Value(1)),
+		)),
+		NewResult(NewResultSet(
+			WithColumns(Column{Name: "id", Type: ydb.TypeInt64}),
+			WithValues(ydb.Int64Value(1)),
+		)),
+	)
+	if m.NextRow(context.Background()) {
+		t.Fatalf("unexpected row")
+	}
+	if m.Err() == nil {
+		t.Fatalf("expected error")
+	}
+}
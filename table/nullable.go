@@ -0,0 +1,75 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+// NullParameterError is returned by the prepared statement execution when
+// NULL value is passed as a parameter which is declared by the query as
+// non-nullable (that is, of non-optional type such as Uint64 rather than
+// Optional<Uint64>). Without this check such request is rejected by the
+// server with a GENERIC_ERROR.
+type NullParameterError struct {
+	// Name is the name of the parameter, e.g. "$id".
+	Name string
+
+	// Type is the declared type of the parameter.
+	Type string
+}
+
+func (e *NullParameterError) Error() string {
+	return fmt.Sprintf(
+		"ydb: table: NULL value of non-nullable parameter %s of type %s",
+		e.Name, e.Type,
+	)
+}
+
+// checkNullParams returns *NullParameterError if some of params is NULL while
+// its declared type is not optional. Parameters which are not declared are
+// not checked.
+func checkNullParams(types map[string]*Ydb.Type, params queryParams) error {
+	if len(types) == 0 || len(params) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t, ok := types[name]
+		if !ok || t.GetOptionalType() != nil || !isNull(params[name]) {
+			continue
+		}
+		var buf bytes.Buffer
+		internal.WriteTypeStringTo(&buf, internal.TypeFromYDB(t))
+		return &NullParameterError{
+			Name: name,
+			Type: buf.String(),
+		}
+	}
+	return nil
+}
+
+// isNull reports whether v is NULL value of optional type.
+func isNull(v *Ydb.TypedValue) bool {
+	if v.GetType().GetOptionalType() == nil {
+		return false
+	}
+	x := v.GetValue()
+	for {
+		switch y := x.GetValue().(type) {
+		case *Ydb.Value_NullFlagValue:
+			return true
+		case *Ydb.Value_NestedValue:
+			x = y.NestedValue
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+func TestCheckNullParams(t *testing.T) {
+	types := map[string]*Ydb.Type{
+		"$id":   internal.TypeToYDB(ydb.TypeUint64),
+		"$name": internal.TypeToYDB(ydb.Optional(ydb.TypeUTF8)),
+	}
+	for _, test := range []struct {
+		name   string
+		params *QueryParameters
+		err    bool
+	}{
+		{
+			name: "not null",
+			params: NewQueryParameters(
+				ValueParam("$id", ydb.Uint64Value(1)),
+				ValueParam("$name", ydb.NullValue(ydb.TypeUTF8)),
+			),
+		},
+		{
+			name: "null",
+			params: NewQueryParameters(
+				ValueParam("$id", ydb.NullValue(ydb.TypeUint64)),
+			),
+			err: true,
+		},
+		{
+			name: "optional not null",
+			params: NewQueryParameters(
+				ValueParam("$id", ydb.OptionalValue(ydb.Uint64Value(1))),
+			),
+		},
+		{
+			name: "undeclared",
+			params: NewQueryParameters(
+				ValueParam("$other", ydb.NullValue(ydb.TypeUint64)),
+			),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkNullParams(types, test.params.params())
+			if !test.err {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			e, ok := err.(*NullParameterError)
+			if !ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.Name != "$id" || e.Type != "Uint64" {
+				t.Errorf("unexpected error: %+v", e)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package table
 
 import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
 	"github.com/yandex-cloud/ydb-go-sdk"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
 	"github.com/yandex-cloud/ydb-go-sdk/internal"
 )
@@ -35,11 +40,37 @@ type Column struct {
 	Type ydb.Type
 }
 
+// Description describes table returned by DescribeTable().
 type Description struct {
 	Name       string
 	Columns    []Column
 	PrimaryKey []string
-	KeyRanges  []KeyRange
+
+	// KeyRanges contains key ranges of the table partitions. Shard bounds are
+	// returned by the server only if WithShardKeyBounds() option is passed to
+	// DescribeTable(); otherwise KeyRanges contains single unbounded range.
+	KeyRanges []KeyRange
+
+	// Indexes contains secondary indexes of the table.
+	Indexes []IndexDescription
+
+	// ColumnFamilies maps names of the columns to the names of their column
+	// families. Columns of the default family are not present.
+	ColumnFamilies map[string]string
+
+	// Stats contains table statistics. It is nil unless WithTableStats() or
+	// WithPartitionStats() option is passed to DescribeTable().
+	Stats *TableStats
+}
+
+// IndexDescription describes secondary index of the table.
+type IndexDescription struct {
+	Name         string
+	IndexColumns []string
+
+	// Type is the type of the index. It is nil if the index type is not
+	// known to this package.
+	Type IndexType
 }
 
 type (
@@ -70,6 +101,19 @@ func WithColumn(name string, typ ydb.Type) CreateTableOption {
 	}
 }
 
+// WithFamilyColumn returns CreateTableOption which adds column with given
+// name and type to the column family with given name. Column families are
+// configured via WithStoragePolicyColumnFamily().
+func WithFamilyColumn(name string, typ ydb.Type, family string) CreateTableOption {
+	return func(d *createTableDesc) {
+		d.Columns = append(d.Columns, &Ydb_Table.ColumnMeta{
+			Name:   name,
+			Type:   internal.TypeToYDB(typ),
+			Family: family,
+		})
+	}
+}
+
 func WithPrimaryKeyColumn(columns ...string) CreateTableOption {
 	return func(d *createTableDesc) {
 		d.PrimaryKey = append(d.PrimaryKey, columns...)
@@ -239,6 +283,66 @@ func WithStoragePolicyKeepInMemory(flag ydb.FeatureFlag) StoragePolicyOption {
 	}
 }
 
+// WithStoragePolicyColumnFamily returns StoragePolicyOption which configures
+// column family with given name. The "default" family contains primary key
+// columns and columns without explicit family.
+func WithStoragePolicyColumnFamily(name string, opts ...ColumnFamilyPolicyOption) StoragePolicyOption {
+	return func(s *storagePolicy) {
+		x := &Ydb_Table.ColumnFamilyPolicy{
+			Name: name,
+		}
+		for _, opt := range opts {
+			opt((*columnFamilyPolicy)(x))
+		}
+		s.ColumnFamilies = append(s.ColumnFamilies, x)
+	}
+}
+
+type (
+	columnFamilyPolicy       Ydb_Table.ColumnFamilyPolicy
+	ColumnFamilyPolicyOption func(*columnFamilyPolicy)
+)
+
+type ColumnFamilyCompression uint
+
+func (c ColumnFamilyCompression) toYDB() Ydb_Table.ColumnFamilyPolicy_Compression {
+	switch c {
+	case ColumnFamilyCompressionNone:
+		return Ydb_Table.ColumnFamilyPolicy_UNCOMPRESSED
+	case ColumnFamilyCompressionEnabled:
+		return Ydb_Table.ColumnFamilyPolicy_COMPRESSED
+	default:
+		return Ydb_Table.ColumnFamilyPolicy_COMPRESSION_UNSPECIFIED
+	}
+}
+
+const (
+	ColumnFamilyCompressionUnknown ColumnFamilyCompression = iota
+	ColumnFamilyCompressionNone
+	ColumnFamilyCompressionEnabled
+)
+
+func WithColumnFamilyPolicyData(kind string) ColumnFamilyPolicyOption {
+	return func(c *columnFamilyPolicy) {
+		c.Data = &Ydb_Table.StorageSettings{StorageKind: kind}
+	}
+}
+func WithColumnFamilyPolicyExternal(kind string) ColumnFamilyPolicyOption {
+	return func(c *columnFamilyPolicy) {
+		c.External = &Ydb_Table.StorageSettings{StorageKind: kind}
+	}
+}
+func WithColumnFamilyPolicyKeepInMemory(flag ydb.FeatureFlag) ColumnFamilyPolicyOption {
+	return func(c *columnFamilyPolicy) {
+		c.KeepInMemory = internal.FeatureFlagToYDB(flag)
+	}
+}
+func WithColumnFamilyPolicyCompression(compression ColumnFamilyCompression) ColumnFamilyPolicyOption {
+	return func(c *columnFamilyPolicy) {
+		c.Compression = compression.toYDB()
+	}
+}
+
 func WithCompactionPolicyPreset(name string) CompactionPolicyOption {
 	return func(c *compactionPolicy) { c.PresetName = name }
 }
@@ -329,6 +433,35 @@ func WithCachingPolicyPreset(name string) CachingPolicyOption {
 	}
 }
 
+type (
+	describeTableDesc   Ydb_Table.DescribeTableRequest
+	DescribeTableOption func(*describeTableDesc)
+)
+
+// WithShardKeyBounds makes DescribeTable() to return key ranges of the table
+// partitions.
+func WithShardKeyBounds() DescribeTableOption {
+	return func(d *describeTableDesc) {
+		d.IncludeShardKeyBounds = true
+	}
+}
+
+// WithTableStats makes DescribeTable() to return table statistics.
+func WithTableStats() DescribeTableOption {
+	return func(d *describeTableDesc) {
+		d.IncludeTableStats = true
+	}
+}
+
+// WithPartitionStats makes DescribeTable() to return table statistics along
+// with statistics of each table partition.
+func WithPartitionStats() DescribeTableOption {
+	return func(d *describeTableDesc) {
+		d.IncludeTableStats = true
+		d.IncludePartitionStats = true
+	}
+}
+
 type (
 	dropTableDesc   Ydb_Table.DropTableRequest
 	DropTableOption func(*dropTableDesc)
@@ -348,6 +481,29 @@ func WithAddColumn(name string, typ ydb.Type) AlterTableOption {
 	}
 }
 
+// WithAddFamilyColumn returns AlterTableOption which adds column with given
+// name and type to the column family with given name.
+func WithAddFamilyColumn(name string, typ ydb.Type, family string) AlterTableOption {
+	return func(d *alterTableDesc) {
+		d.AddColumns = append(d.AddColumns, &Ydb_Table.ColumnMeta{
+			Name:   name,
+			Type:   internal.TypeToYDB(typ),
+			Family: family,
+		})
+	}
+}
+
+// WithAlterColumnFamily returns AlterTableOption which moves existing column
+// with given name to the column family with given name.
+func WithAlterColumnFamily(name, family string) AlterTableOption {
+	return func(d *alterTableDesc) {
+		d.AlterColumns = append(d.AlterColumns, &Ydb_Table.ColumnMeta{
+			Name:   name,
+			Family: family,
+		})
+	}
+}
+
 func WithDropColumn(name string) AlterTableOption {
 	return func(d *alterTableDesc) {
 		d.DropColumns = append(d.DropColumns, name)
@@ -359,6 +515,50 @@ type (
 	CopyTableOption func(*copyTableDesc)
 )
 
+// WithCopyTableOperationTimeout sets the server side timeout of the copy
+// operation. Note that operation parameters passed within the context (such
+// as ydb.WithOperationTimeout()) take precedence over this option.
+func WithCopyTableOperationTimeout(timeout time.Duration) CopyTableOption {
+	return func(d *copyTableDesc) {
+		d.OperationParams = operationTimeoutParams(timeout)
+	}
+}
+
+type (
+	copyTablesDesc   Ydb_Table.CopyTablesRequest
+	CopyTablesOption func(*copyTablesDesc)
+)
+
+// WithCopyTablesItem adds copy of the table at src path to dst path to the
+// CopyTables() call. If omitIndexes is true then secondary indexes of the
+// table are not copied.
+func WithCopyTablesItem(dst, src string, omitIndexes bool) CopyTablesOption {
+	return func(d *copyTablesDesc) {
+		d.Tables = append(d.Tables, &Ydb_Table.CopyTableItem{
+			SourcePath:      src,
+			DestinationPath: dst,
+			OmitIndexes:     omitIndexes,
+		})
+	}
+}
+
+// WithCopyTablesOperationTimeout is the same as WithCopyTableOperationTimeout
+// but for CopyTables() call.
+func WithCopyTablesOperationTimeout(timeout time.Duration) CopyTablesOption {
+	return func(d *copyTablesDesc) {
+		d.OperationParams = operationTimeoutParams(timeout)
+	}
+}
+
+func operationTimeoutParams(d time.Duration) *Ydb_Operations.OperationParams {
+	if d <= 0 {
+		return nil
+	}
+	return &Ydb_Operations.OperationParams{
+		OperationTimeout: ptypes.DurationProto(d),
+	}
+}
+
 type (
 	txDesc   Ydb_Table.TransactionSettings
 	TxOption func(*txDesc)
@@ -408,18 +608,30 @@ var (
 	}
 )
 
+// WithSerializableReadWrite returns TxOption which sets the strictest
+// transaction mode: all reads and writes within transaction are serializable
+// and see the most recent committed data.
 func WithSerializableReadWrite() TxOption {
 	return func(d *txDesc) {
 		d.TxMode = serializableReadWrite
 	}
 }
 
+// WithStaleReadOnly returns TxOption which sets read-only transaction mode
+// in which reads may return data that is stale by a fraction of a second (the
+// exact bound is defined by the server). Each read returns consistent data
+// though. This mode is the cheapest one for consistent reads when freshness is
+// not critical.
 func WithStaleReadOnly() TxOption {
 	return func(d *txDesc) {
 		d.TxMode = staleReadOnly
 	}
 }
 
+// WithOnlineReadOnly returns TxOption which sets read-only transaction mode
+// in which each read returns the most recent committed data. Each read
+// returns consistent data unless WithInconsistentReads() option is given, but
+// there is no consistency guaranteed between different reads.
 func WithOnlineReadOnly(opts ...TxOnlineReadOnlyOption) TxOption {
 	return func(d *txDesc) {
 		var ro txOnlineReadOnly
@@ -436,6 +648,9 @@ type txOnlineReadOnly Ydb_Table.OnlineModeSettings
 
 type TxOnlineReadOnlyOption func(*txOnlineReadOnly)
 
+// WithInconsistentReads returns TxOnlineReadOnlyOption which allows reads to
+// return inconsistent data, such as rows of the different versions of a
+// multi-row update. It is the cheapest mode for reads.
 func WithInconsistentReads() TxOnlineReadOnlyOption {
 	return func(d *txOnlineReadOnly) {
 		d.AllowInconsistentReads = true
@@ -466,6 +681,49 @@ func TxControl(opts ...TxControlOption) *TransactionControl {
 	return c
 }
 
+// StrongRead returns transaction control which executes query in a single
+// serializable read-write transaction. It guarantees that query sees the most
+// recent committed data consistently.
+func StrongRead() *TransactionControl {
+	return TxControl(
+		BeginTx(WithSerializableReadWrite()),
+		CommitTx(),
+	)
+}
+
+// OnlineRead returns transaction control which executes query in a single
+// online read-only transaction with consistent reads. See
+// WithOnlineReadOnly() for details.
+func OnlineRead() *TransactionControl {
+	return TxControl(
+		BeginTx(WithOnlineReadOnly()),
+		CommitTx(),
+	)
+}
+
+// StaleRead returns transaction control which executes query in a single
+// stale read-only transaction. Query sees consistent data which may be stale
+// by a fraction of a second. See WithStaleReadOnly() for details.
+//
+// Note that the staleness bound is not configurable by the client.
+func StaleRead() *TransactionControl {
+	return TxControl(
+		BeginTx(WithStaleReadOnly()),
+		CommitTx(),
+	)
+}
+
+// InconsistentRead returns transaction control which executes query in a
+// single online read-only transaction with inconsistent reads allowed. That
+// is, query may see partially applied transactions. See
+// WithInconsistentReads() for details.
+func InconsistentRead() *TransactionControl {
+	return TxControl(
+		BeginTx(WithOnlineReadOnly(WithInconsistentReads())),
+		CommitTx(),
+	)
+}
+
 type TableOptionsDescription struct {
 	TableProfilePresets       []TableProfileDescription
 	StoragePolicyPresets      []StoragePolicyDescription
@@ -581,11 +839,24 @@ func (d *readTableDesc) initKeyRange() {
 	}
 }
 
+// ReadColumn returns ReadTableOption which adds column with given name to the
+// list of columns to read. If no columns are given, all columns are read.
 func ReadColumn(name string) ReadTableOption {
 	return func(desc *readTableDesc) {
 		desc.Columns = append(desc.Columns, name)
 	}
 }
+
+// ReadColumns returns ReadTableOption which adds columns with given names to
+// the list of columns to read.
+func ReadColumns(names ...string) ReadTableOption {
+	return func(desc *readTableDesc) {
+		desc.Columns = append(desc.Columns, names...)
+	}
+}
+
+// ReadOrdered returns ReadTableOption which makes ReadTable return rows in
+// order of the primary key.
 func ReadOrdered() ReadTableOption {
 	return func(desc *readTableDesc) {
 		desc.Ordered = true
@@ -612,6 +883,11 @@ func ReadKeyRange(x KeyRange) ReadTableOption {
 	}
 }
 
+// ReadGreater returns ReadTableOption which makes ReadTable read values with
+// primary key greater than x. The same goes for ReadGreaterOrEqual(),
+// ReadLess() and ReadLessOrEqual().
+//
+// Note that x may be a tuple of the primary key prefix.
 func ReadGreater(x ydb.Value) ReadTableOption {
 	return func(desc *readTableDesc) {
 		desc.initKeyRange()
@@ -644,6 +920,8 @@ func ReadLessOrEqual(x ydb.Value) ReadTableOption {
 		}
 	}
 }
+
+// ReadRowLimit returns ReadTableOption which limits number of rows to read.
 func ReadRowLimit(n uint64) ReadTableOption {
 	return func(desc *readTableDesc) {
 		desc.RowLimit = n
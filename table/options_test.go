@@ -133,5 +133,115 @@ func TestSessionOptionsProfile(t *testing.T) {
 			t.Errorf("Caching policy is not as expected")
 		}
 	}
+	{
+		opt := WithProfile(
+			WithStoragePolicy(
+				WithStoragePolicyColumnFamily("default"),
+				WithStoragePolicyColumnFamily("cold",
+					WithColumnFamilyPolicyData("hdd"),
+					WithColumnFamilyPolicyExternal("hdd"),
+					WithColumnFamilyPolicyKeepInMemory(ydb.FeatureDisabled),
+					WithColumnFamilyPolicyCompression(ColumnFamilyCompressionEnabled),
+				),
+			),
+		)
+		req := Ydb_Table.CreateTableRequest{}
+		opt((*createTableDesc)(&req))
+		fs := req.Profile.StoragePolicy.ColumnFamilies
+		if len(fs) != 2 || fs[0].Name != "default" {
+			t.Fatalf("Column families are not as expected")
+		}
+		if f := fs[1]; f.Name != "cold" ||
+			f.Data.StorageKind != "hdd" ||
+			f.External.StorageKind != "hdd" ||
+			f.KeepInMemory != Ydb.FeatureFlag_DISABLED ||
+			f.Compression != Ydb_Table.ColumnFamilyPolicy_COMPRESSED {
+			t.Errorf("Column family policy is not as expected")
+		}
+	}
+	{
+		opt := WithFamilyColumn("payload", ydb.TypeString, "cold")
+		req := Ydb_Table.CreateTableRequest{}
+		opt((*createTableDesc)(&req))
+		if c := req.Columns[0]; c.Name != "payload" || c.Family != "cold" {
+			t.Errorf("Family column is not as expected")
+		}
+	}
 
 }
+
+func TestAlterTableOptionsFamily(t *testing.T) {
+	req := Ydb_Table.AlterTableRequest{}
+	for _, opt := range []AlterTableOption{
+		WithAddFamilyColumn("payload", ydb.TypeString, "cold"),
+		WithAlterColumnFamily("title", "cold"),
+	} {
+		opt((*alterTableDesc)(&req))
+	}
+	if c := req.AddColumns[0]; c.Name != "payload" || c.Family != "cold" {
+		t.Errorf("unexpected added column: %v", c)
+	}
+	if c := req.AlterColumns[0]; c.Name != "title" || c.Family != "cold" || c.Type != nil {
+		t.Errorf("unexpected altered column: %v", c)
+	}
+}
+
+func TestReadPresets(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		tx   *TransactionControl
+		exp  *Ydb_Table.TransactionSettings
+	}{
+		{
+			name: "strong",
+			tx:   StrongRead(),
+			exp: &Ydb_Table.TransactionSettings{
+				TxMode: &Ydb_Table.TransactionSettings_SerializableReadWrite{
+					SerializableReadWrite: &Ydb_Table.SerializableModeSettings{},
+				},
+			},
+		},
+		{
+			name: "online",
+			tx:   OnlineRead(),
+			exp: &Ydb_Table.TransactionSettings{
+				TxMode: &Ydb_Table.TransactionSettings_OnlineReadOnly{
+					OnlineReadOnly: &Ydb_Table.OnlineModeSettings{},
+				},
+			},
+		},
+		{
+			name: "stale",
+			tx:   StaleRead(),
+			exp: &Ydb_Table.TransactionSettings{
+				TxMode: &Ydb_Table.TransactionSettings_StaleReadOnly{
+					StaleReadOnly: &Ydb_Table.StaleModeSettings{},
+				},
+			},
+		},
+		{
+			name: "inconsistent",
+			tx:   InconsistentRead(),
+			exp: &Ydb_Table.TransactionSettings{
+				TxMode: &Ydb_Table.TransactionSettings_OnlineReadOnly{
+					OnlineReadOnly: &Ydb_Table.OnlineModeSettings{
+						AllowInconsistentReads: true,
+					},
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if !test.tx.desc.CommitTx {
+				t.Errorf("transaction is not committed")
+			}
+			begin, ok := test.tx.desc.TxSelector.(*Ydb_Table.TransactionControl_BeginTx)
+			if !ok {
+				t.Fatalf("unexpected tx selector: %T", test.tx.desc.TxSelector)
+			}
+			if act := begin.BeginTx; !reflect.DeepEqual(act, test.exp) {
+				t.Errorf("unexpected tx settings: %v; want %v", act, test.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package table
+
+import (
+	"context"
+	"sync"
+)
+
+type workloadContextKey struct{}
+
+// WithWorkload returns a copy of parent context with given workload tag.
+// The tag is used by the PartitionedSessionPool to select the pool partition.
+func WithWorkload(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, workloadContextKey{}, tag)
+}
+
+// ContextWorkload returns workload tag stored in the context by
+// WithWorkload(). It returns empty string if no tag is set.
+func ContextWorkload(ctx context.Context) string {
+	tag, _ := ctx.Value(workloadContextKey{}).(string)
+	return tag
+}
+
+// PartitionedSessionPool is a set of named SessionPool partitions over one
+// driver. Each partition has its own size limit, timeouts and keep alive
+// options, such that one workload (e.g. "batch") can not starve other one
+// (e.g. "interactive") of sessions.
+//
+// Partition is selected per request by the workload tag of the context (see
+// WithWorkload()). Requests without tag or with unknown tag use the Default
+// pool.
+//
+// PartitionedSessionPool implements SessionProvider and may be used with
+// Retryer.
+// A PartitionedSessionPool is safe for use by multiple goroutines
+// simultaneously.
+type PartitionedSessionPool struct {
+	// Default is a pool used for requests which workload tag is not listed in
+	// Partitions. It must not be nil.
+	Default *SessionPool
+
+	// Partitions maps workload tag to the pool partition. Pools usually
+	// share the same Builder.
+	// Partitions must not be modified after first use.
+	Partitions map[string]*SessionPool
+
+	mu    sync.Mutex
+	owner map[*Session]*SessionPool // Forgotten when session is closed.
+}
+
+// Get returns session from the partition selected by the workload tag of
+// ctx. See SessionPool's Get() for details.
+func (p *PartitionedSessionPool) Get(ctx context.Context) (*Session, error) {
+	pool := p.partition(ContextWorkload(ctx))
+	s, err := pool.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.owner == nil {
+		p.owner = make(map[*Session]*SessionPool)
+	}
+	if _, has := p.owner[s]; !has {
+		p.owner[s] = pool
+		// Session may be closed outside of Put() and PutBusy(), e.g. by the
+		// partition itself when it is idle for too long.
+		s.OnClose(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			delete(p.owner, s)
+		})
+	}
+	return s, nil
+}
+
+// Put returns session into the partition it was received from. See
+// SessionPool's Put() for details.
+func (p *PartitionedSessionPool) Put(ctx context.Context, s *Session) error {
+	return p.release(ctx, s).Put(ctx, s)
+}
+
+// PutBusy returns session into the partition it was received from. See
+// SessionPool's PutBusy() for details.
+func (p *PartitionedSessionPool) PutBusy(ctx context.Context, s *Session) error {
+	return p.release(ctx, s).PutBusy(ctx, s)
+}
+
+// Stats returns stats of each partition by workload tag. Stats of the
+// Default pool are stored by empty tag.
+func (p *PartitionedSessionPool) Stats() map[string]SessionPoolStats {
+	m := make(map[string]SessionPoolStats, len(p.Partitions)+1)
+	m[""] = p.Default.Stats()
+	for tag, pool := range p.Partitions {
+		m[tag] = pool.Stats()
+	}
+	return m
+}
+
+// Close closes all partitions. It returns first error occured during
+// partitions closing.
+func (p *PartitionedSessionPool) Close(ctx context.Context) (err error) {
+	if e := p.Default.Close(ctx); e != nil {
+		err = e
+	}
+	for _, pool := range p.Partitions {
+		if e := pool.Close(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (p *PartitionedSessionPool) partition(tag string) *SessionPool {
+	if pool, ok := p.Partitions[tag]; ok && tag != "" {
+		return pool
+	}
+	return p.Default
+}
+
+// release returns the owner of s. If s was not received by Get() then the
+// partition is selected by the workload tag of ctx.
+func (p *PartitionedSessionPool) release(ctx context.Context, s *Session) *SessionPool {
+	p.mu.Lock()
+	pool, ok := p.owner[s]
+	p.mu.Unlock()
+	if !ok {
+		pool = p.partition(ContextWorkload(ctx))
+	}
+	return pool
+}
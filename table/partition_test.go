@@ -0,0 +1,99 @@
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestPartitionedSessionPool(t *testing.T) {
+	newPool := func(limit int) *SessionPool {
+		return &SessionPool{
+			SizeLimit:         limit,
+			IdleThreshold:     -1,
+			BusyCheckInterval: -1,
+			Builder: &StubBuilder{
+				T:     t,
+				Limit: limit,
+				Handler: methodHandlers{
+					testutil.TableDeleteSession: okHandler,
+				},
+			},
+		}
+	}
+	p := &PartitionedSessionPool{
+		Default: newPool(2),
+		Partitions: map[string]*SessionPool{
+			"batch": newPool(1),
+		},
+	}
+	defer p.Close(context.Background())
+
+	batch := WithWorkload(context.Background(), "batch")
+	s1, err := p.Get(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Batch partition is exhausted, but interactive requests still must get
+	// sessions.
+	s2, err := p.Get(WithWorkload(context.Background(), "interactive"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := p.Stats()
+	if act, exp := stats["batch"].InUse(), 1; act != exp {
+		t.Fatalf("unexpected batch sessions in use: %d; want %d", act, exp)
+	}
+	if act, exp := stats[""].InUse(), 1; act != exp {
+		t.Fatalf("unexpected default sessions in use: %d; want %d", act, exp)
+	}
+
+	// Sessions must be returned into partitions they were received from
+	// regardless of the context.
+	if err := p.Put(context.Background(), s1); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Put(batch, s2); err != nil {
+		t.Fatal(err)
+	}
+	stats = p.Stats()
+	if act, exp := stats["batch"].Idle, 1; act != exp {
+		t.Fatalf("unexpected batch idle sessions: %d; want %d", act, exp)
+	}
+	if act, exp := stats[""].Idle, 1; act != exp {
+		t.Fatalf("unexpected default idle sessions: %d; want %d", act, exp)
+	}
+}
+
+func TestPartitionedSessionPoolForgetsClosedSessions(t *testing.T) {
+	p := &PartitionedSessionPool{
+		Default: &SessionPool{
+			SizeLimit:         1,
+			IdleThreshold:     -1,
+			BusyCheckInterval: -1,
+			Builder: &StubBuilder{
+				T:     t,
+				Limit: 1,
+				Handler: methodHandlers{
+					testutil.TableDeleteSession: okHandler,
+				},
+			},
+		},
+	}
+	defer p.Close(context.Background())
+
+	s, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	p.mu.Lock()
+	n := len(p.owner)
+	p.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("closed session is still tracked")
+	}
+}
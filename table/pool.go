@@ -4,6 +4,9 @@ import (
 	"container/list"
 	"context"
 	"errors"
+	"math"
+	"math/rand"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -16,6 +19,7 @@ var (
 	DefaultSessionPoolIdleThreshold     = 5 * time.Second
 	DefaultSessionPoolBusyCheckInterval = 1 * time.Second
 	DefaultSessionPoolSizeLimit         = 50
+	DefaultSessionPoolDeleteConcurrency = 10
 )
 
 var (
@@ -70,6 +74,19 @@ type SessionPool struct {
 	// is used.
 	IdleThreshold time.Duration
 
+	// IdleTTL is a maximum duration session may stay in the pool without being
+	// used. After that session is deleted and the pool shrinks toward the
+	// MinSize. Note that expiration is checked by the keep alive routine,
+	// that is, it is not working if keep alive is disabled.
+	//
+	// If IdleTTL is less than or equal to zero then idle sessions are not
+	// deleted.
+	IdleTTL time.Duration
+
+	// MinSize is a lower bound of pooled sessions which are not deleted after
+	// IdleTTL.
+	MinSize int
+
 	// BusyCheckInterval is an interval between busy sessions status checks.
 	// If BusyCheckInterval is less than zero then there busy checking is
 	// disabled.
@@ -89,12 +106,58 @@ type SessionPool struct {
 	// DefaultSessionPoolKeepAliveTimeout is used.
 	KeepAliveTimeout time.Duration
 
+	// KeepAliveJitter is a fraction of IdleThreshold in range [0, 1] by which
+	// KeepAlive() call of each idle session is randomly advanced. That is,
+	// session is touched after random duration of inactivity within
+	// [IdleThreshold * (1 - KeepAliveJitter), IdleThreshold], which spreads
+	// KeepAlive() calls of sessions became idle at the same moment over time.
+	// Jitter is applied when session is put back to the idle list after
+	// KeepAlive(), so the first KeepAlive() after Put() is not advanced.
+	// If KeepAliveJitter is less than or equal to zero then no jitter is
+	// applied.
+	KeepAliveJitter float64
+
+	// KeepAliveConcurrency is a maximum number of KeepAlive() calls made
+	// simultaneously by the pool.
+	// If KeepAliveConcurrency is less than or equal to zero then sessions are
+	// touched one by one.
+	KeepAliveConcurrency int
+
 	// DeleteTimeout limits maximum time spent on Delete request for
 	// KeepAliveBatchSize number of sessions.
 	// If DeleteTimeout is less than or equal to zero then the
 	// DefaultSessionPoolDeleteTimeout is used.
 	DeleteTimeout time.Duration
 
+	// DeleteConcurrency is a maximum number of DeleteSession() calls made
+	// simultaneously by the pool when it is closed. Idle sessions are
+	// deleted on the server side within the Close() context instead of
+	// leaking until the server side timeout.
+	// If DeleteConcurrency is zero then the
+	// DefaultSessionPoolDeleteConcurrency is used.
+	// If DeleteConcurrency is negative then sessions are deleted one by one.
+	DeleteConcurrency int
+
+	// LeakThreshold is a duration after which session received by Get() and
+	// not returned back by Put() or PutBusy() (or closed) is reported as
+	// leaked via SessionPoolTrace's Leak callback. Each session is reported
+	// once per Get() call.
+	//
+	// Note that when leak detection is enabled, the pool records stack trace
+	// of each Get() call, which is quite expensive. Thus it is intended mostly
+	// for debugging purposes, similar to finding connection leaks in
+	// database/sql.
+	//
+	// If LeakThreshold is less than or equal to zero then leak detection is
+	// disabled.
+	LeakThreshold time.Duration
+
+	// Clock is a source of time used by the pool for keep alive and busy
+	// checking.
+	// It is useful mostly for testing purposes.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
 	mu       sync.Mutex
 	initOnce sync.Once
 	index    map[*Session]sessionInfo
@@ -110,16 +173,22 @@ type SessionPool struct {
 	touching     bool
 	touchingDone chan struct{}
 
+	rand *rand.Rand // Used for keep alive jitter.
+
 	busyCheck       chan *Session
 	busyCheckerStop chan struct{}
 	busyCheckerDone chan struct{}
 
+	leakDetectorStop chan struct{}
+	leakDetectorDone chan struct{}
+
 	closed bool
 }
 
 func (p *SessionPool) init() {
 	p.initOnce.Do(func() {
 		p.index = make(map[*Session]sessionInfo)
+		p.Clock = timeutil.ClockOrDefault(p.Clock)
 
 		p.idle = list.New()
 		p.ready = list.New()
@@ -133,6 +202,9 @@ func (p *SessionPool) init() {
 		if p.IdleThreshold == 0 {
 			p.IdleThreshold = DefaultSessionPoolIdleThreshold
 		}
+		if p.KeepAliveJitter > 0 {
+			p.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
 		if p.IdleThreshold > 0 {
 			p.keeperStop = make(chan struct{})
 			p.keeperDone = make(chan struct{})
@@ -150,6 +222,12 @@ func (p *SessionPool) init() {
 			p.busyCheck = make(chan *Session)
 			go p.busyChecker()
 		}
+
+		if p.LeakThreshold > 0 {
+			p.leakDetectorStop = make(chan struct{})
+			p.leakDetectorDone = make(chan struct{})
+			go p.leakDetector(p.Clock.NewTimer(p.LeakThreshold))
+		}
 	})
 }
 
@@ -288,6 +366,9 @@ func (p *SessionPool) Get(ctx context.Context) (s *Session, err error) {
 	if s == nil && err == nil {
 		err = ErrNoProgress
 	}
+	if s != nil && p.LeakThreshold > 0 {
+		p.checkout(s)
+	}
 
 	return s, err
 }
@@ -316,8 +397,9 @@ func (p *SessionPool) Put(ctx context.Context, s *Session) (err error) {
 		panicLocked(&p.mu, "ydb: table: Put() on full session pool")
 
 	default:
+		p.checkin(s)
 		if !p.notify(s) {
-			p.pushIdle(s, timeutil.Now())
+			p.pushIdle(s, p.Clock.Now())
 		}
 	}
 	p.mu.Unlock()
@@ -470,6 +552,50 @@ func (p *SessionPool) Create(ctx context.Context) (s *Session, err error) {
 	return nil, ErrNoProgress
 }
 
+// SessionPoolStats contains statistics of the SessionPool.
+type SessionPoolStats struct {
+	// Size is the number of sessions owned by the pool, including idle
+	// sessions and sessions received by Get() but not returned yet.
+	Size int
+
+	// Idle is the number of sessions ready to be returned by Get().
+	Idle int
+
+	// Waiting is the number of Get() calls waiting for a session.
+	Waiting int
+
+	// Limit is the upper bound of the pool size.
+	Limit int
+
+	// Closed reports whether pool is closed.
+	Closed bool
+}
+
+// InUse returns the number of sessions received by Get() and not returned
+// yet.
+func (s SessionPoolStats) InUse() int {
+	return s.Size - s.Idle
+}
+
+// Stats returns current statistics of the pool.
+func (p *SessionPool) Stats() SessionPoolStats {
+	p.init()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return SessionPoolStats{
+			Limit:  p.limit,
+			Closed: true,
+		}
+	}
+	return SessionPoolStats{
+		Size:    len(p.index),
+		Idle:    p.idle.Len(),
+		Waiting: p.waitq.Len(),
+		Limit:   p.limit,
+	}
+}
+
 // Close deletes all stored sessions inside SessionPool.
 // It also stops all underlying timers and goroutines.
 // It returns first error occured during stale sessions deletion.
@@ -498,6 +624,11 @@ func (p *SessionPool) Close(ctx context.Context) (err error) {
 	if ch := p.busyCheckerStop; ch != nil {
 		close(ch)
 	}
+
+	leakDetectorDone := p.leakDetectorDone
+	if ch := p.leakDetectorStop; ch != nil {
+		close(ch)
+	}
 	p.mu.Unlock()
 
 	if keeperDone != nil {
@@ -506,6 +637,9 @@ func (p *SessionPool) Close(ctx context.Context) (err error) {
 	if busyCheckerDone != nil {
 		<-busyCheckerDone
 	}
+	if leakDetectorDone != nil {
+		<-leakDetectorDone
+	}
 
 	p.mu.Lock()
 	idle := p.idle
@@ -520,10 +654,11 @@ func (p *SessionPool) Close(ctx context.Context) (err error) {
 		ch := el.Value.(*chan *Session)
 		close(*ch)
 	}
+	ss := make([]*Session, 0, idle.Len())
 	for e := idle.Front(); e != nil; e = e.Next() {
-		s := e.Value.(*Session)
-		p.closeSession(ctx, s)
+		ss = append(ss, e.Value.(*Session))
 	}
+	p.closeEach(ctx, ss)
 
 	return nil
 }
@@ -534,7 +669,7 @@ func (p *SessionPool) busyChecker() {
 		toCheck []*Session
 
 		active = false
-		timer  = timeutil.NewStoppedTimer()
+		timer  = p.newStoppedTimer()
 		ctx    = context.Background()
 	)
 	for {
@@ -581,7 +716,7 @@ func (p *SessionPool) busyChecker() {
 				if reuse {
 					p.index[s] = sessionInfo{}
 					if !p.notify(s) {
-						p.pushIdle(s, timeutil.Now())
+						p.pushIdle(s, p.Clock.Now())
 						p.pushReady(s)
 					}
 				}
@@ -609,7 +744,7 @@ func (p *SessionPool) keeper() {
 		toDelete []*Session // Cached for reuse.
 
 		wake  = make(chan struct{})
-		timer = timeutil.NewTimer(p.IdleThreshold)
+		timer = p.Clock.NewTimer(p.IdleThreshold)
 	)
 
 	for {
@@ -643,35 +778,44 @@ func (p *SessionPool) keeper() {
 					break
 				}
 				p.removeIdle(s)
+				if p.expired(s, now) {
+					p.removeExpired(s)
+					toDelete = append(toDelete, s)
+					continue
+				}
 				toTouch = append(toTouch, s)
 			}
 		}
 		p.mu.Unlock()
 
 		var mark *list.Element // Element in the list to insert touched sessions after.
-		for i, s := range toTouch {
-			toTouch[i] = nil
-
-			_, err := p.keepAliveSession(context.Background(), s)
+		p.keepAliveEach(toTouch, func(s *Session, err error) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
 			if err != nil {
 				toDelete = append(toDelete, s)
-				continue
+				return
 			}
-
-			p.mu.Lock()
-			if !p.notify(s) {
-				// Need to push back session into list in order, to prevent
-				// shuffling of sessions order.
-				//
-				// That is, there may be a race condition, when some session S1
-				// pushed back in the list before we took the mutex. Suppose S1
-				// touched time is greater than ours `now` for S0. If so, it
-				// then may interrupt next keep alive iteration earlier and
-				// prevent our session S0 being touched:
-				// time.Since(S1) < threshold but time.Since(S0) > threshold.
-				mark = p.pushIdleInOrderAfter(s, now, mark)
+			if p.notify(s) {
+				return
 			}
-			p.mu.Unlock()
+			if p.KeepAliveJitter > 0 {
+				p.pushIdleInOrder(s, p.jitter(now))
+				return
+			}
+			// Need to push back session into list in order, to prevent
+			// shuffling of sessions order.
+			//
+			// That is, there may be a race condition, when some session S1
+			// pushed back in the list before we took the mutex. Suppose S1
+			// touched time is greater than ours `now` for S0. If so, it then
+			// may interrupt next keep alive iteration earlier and prevent our
+			// session S0 being touched:
+			// time.Since(S1) < threshold but time.Since(S0) > threshold.
+			mark = p.pushIdleInOrderAfter(s, now, mark)
+		})
+		for i := range toTouch {
+			toTouch[i] = nil
 		}
 
 		var (
@@ -710,6 +854,69 @@ func (p *SessionPool) keeper() {
 	}
 }
 
+func (p *SessionPool) leakDetector(timer timeutil.Timer) {
+	defer close(p.leakDetectorDone)
+	defer timer.Stop()
+
+	var leaks []SessionPoolLeakInfo // Cached for reuse.
+	for {
+		var now time.Time
+		select {
+		case now = <-timer.C():
+		case <-p.leakDetectorStop:
+			return
+		}
+
+		leaks = leaks[:0]
+		p.mu.Lock()
+		for s, info := range p.index {
+			c := info.checkout
+			if c == nil || c.reported || now.Sub(c.time) < p.LeakThreshold {
+				continue
+			}
+			c.reported = true
+			leaks = append(leaks, SessionPoolLeakInfo{
+				Session:    s,
+				CheckedOut: c.time,
+				Held:       now.Sub(c.time),
+				Stack:      c.stack,
+			})
+		}
+		p.mu.Unlock()
+
+		for i := range leaks {
+			p.traceLeak(context.Background(), leaks[i])
+			leaks[i] = SessionPoolLeakInfo{}
+		}
+		timer.Reset(p.LeakThreshold)
+	}
+}
+
+// checkout records the moment and the stack trace of Get() call which
+// received session s.
+// p.mu must NOT be held.
+func (p *SessionPool) checkout(s *Session) {
+	c := &sessionCheckout{
+		time:  p.Clock.Now(),
+		stack: debug.Stack(),
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if info, has := p.index[s]; has {
+		info.checkout = c
+		p.index[s] = info
+	}
+}
+
+// checkin removes checkout record of session s.
+// p.mu must be held.
+func (p *SessionPool) checkin(s *Session) {
+	if info, has := p.index[s]; has && info.checkout != nil {
+		info.checkout = nil
+		p.index[s] = info
+	}
+}
+
 var (
 	waitChPool        sync.Pool
 	testHookGetWaitCh func() // nil except some tests.
@@ -802,6 +1009,14 @@ func (p *SessionPool) notify(s *Session) (notified bool) {
 	return false
 }
 
+func (p *SessionPool) newStoppedTimer() timeutil.Timer {
+	t := p.Clock.NewTimer(time.Duration(math.MaxInt64))
+	if !t.Stop() {
+		panic("ydb: table: can not create stopped timer")
+	}
+	return t
+}
+
 // p.mu must NOT be held.
 func (p *SessionPool) closeSession(ctx context.Context, s *Session) {
 	timeout := p.DeleteTimeout
@@ -813,6 +1028,19 @@ func (p *SessionPool) closeSession(ctx context.Context, s *Session) {
 	_ = s.Close(ctx)
 }
 
+// closeEach closes each of given sessions with at most DeleteConcurrency
+// calls in flight. It returns after all calls are done.
+// p.mu must NOT be held.
+func (p *SessionPool) closeEach(ctx context.Context, ss []*Session) {
+	n := p.DeleteConcurrency
+	if n == 0 {
+		n = DefaultSessionPoolDeleteConcurrency
+	}
+	forEachLimited(ss, n, func(s *Session) {
+		p.closeSession(ctx, s)
+	})
+}
+
 func (p *SessionPool) keepAliveSession(ctx context.Context, s *Session) (SessionInfo, error) {
 	timeout := p.KeepAliveTimeout
 	if timeout <= 0 {
@@ -823,6 +1051,57 @@ func (p *SessionPool) keepAliveSession(ctx context.Context, s *Session) (Session
 	return s.KeepAlive(ctx)
 }
 
+// keepAliveEach calls KeepAlive() on each of given sessions with at most
+// KeepAliveConcurrency calls in flight. It calls fn with each call result and
+// returns after all calls are done.
+// p.mu must NOT be held.
+func (p *SessionPool) keepAliveEach(ss []*Session, fn func(*Session, error)) {
+	forEachLimited(ss, p.KeepAliveConcurrency, func(s *Session) {
+		_, err := p.keepAliveSession(context.Background(), s)
+		fn(s, err)
+	})
+}
+
+// forEachLimited calls fn for each of given sessions with at most n calls in
+// flight. If n is less than 2, calls are made sequentially within the
+// caller's goroutine. It returns after all calls are done.
+func forEachLimited(ss []*Session, n int, fn func(*Session)) {
+	if n <= 1 || len(ss) <= 1 {
+		for _, s := range ss {
+			fn(s)
+		}
+		return
+	}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, n)
+	)
+	for _, s := range ss {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(s *Session) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(s)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// jitter returns touch time for session being idle since now with respect to
+// KeepAliveJitter.
+// p.mu must be held.
+func (p *SessionPool) jitter(now time.Time) time.Time {
+	j := p.KeepAliveJitter
+	if j > 1 {
+		j = 1
+	}
+	d := p.rand.Float64() * j * float64(p.IdleThreshold)
+	return now.Add(-time.Duration(d))
+}
+
 // p.mu must be held.
 func (p *SessionPool) removeIdle(s *Session) {
 	info, has := p.index[s]
@@ -856,6 +1135,28 @@ func (p *SessionPool) takeIdle(s *Session) (has, took bool) {
 // p.mu must be held.
 func (p *SessionPool) pushIdle(s *Session, now time.Time) {
 	p.handlePush(s, now, p.idle.PushBack(s))
+
+	info := p.index[s]
+	info.used = now
+	p.index[s] = info
+}
+
+// p.mu must be held.
+func (p *SessionPool) expired(s *Session, now time.Time) bool {
+	if p.IdleTTL <= 0 || len(p.index) <= p.MinSize {
+		return false
+	}
+	return now.Sub(p.index[s].used) >= p.IdleTTL
+}
+
+// p.mu must be held.
+// Session s must not be idle.
+func (p *SessionPool) removeExpired(s *Session) {
+	info := p.index[s]
+	if info.ready != nil {
+		p.ready.Remove(info.ready)
+	}
+	delete(p.index, s)
 }
 
 // p.mu must be held.
@@ -1077,6 +1378,15 @@ func (p *SessionPool) traceCloseStart(ctx context.Context) {
 		b(x)
 	}
 }
+func (p *SessionPool) traceLeak(ctx context.Context, x SessionPoolLeakInfo) {
+	x.Context = ctx
+	if a := p.Trace.Leak; a != nil {
+		a(x)
+	}
+	if b := ContextSessionPoolTrace(ctx).Leak; b != nil {
+		b(x)
+	}
+}
 func (p *SessionPool) traceCloseDone(ctx context.Context, err error) {
 	x := SessionPoolCloseDoneInfo{
 		Context: ctx,
@@ -1094,6 +1404,15 @@ type sessionInfo struct {
 	idle    *list.Element
 	ready   *list.Element
 	touched time.Time
+	used    time.Time
+
+	checkout *sessionCheckout
+}
+
+type sessionCheckout struct {
+	time     time.Time
+	stack    []byte
+	reported bool
 }
 
 func panicLocked(mu sync.Locker, message string) {
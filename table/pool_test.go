@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -889,6 +890,91 @@ func TestSessionPoolKeepAlive(t *testing.T) {
 	mustResetTimer(t, timer.Reset, idleThreshold/2)
 }
 
+func TestSessionPoolIdleTTL(t *testing.T) {
+	timer := timetest.StubSingleTimer(t)
+	defer timer.Cleanup()
+
+	shiftTime, cleanup := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanup()
+
+	var (
+		idleThreshold = 4 * time.Second
+		idleTTL       = 6 * time.Second
+
+		keepAliveCount uint32
+		deleteCount    uint32
+	)
+	p := &SessionPool{
+		SizeLimit:         3,
+		MinSize:           1,
+		IdleThreshold:     idleThreshold,
+		IdleTTL:           idleTTL,
+		BusyCheckInterval: -1,
+		Builder: &StubBuilder{
+			T:     t,
+			Limit: 3,
+			Handler: methodHandlers{
+				testutil.TableKeepAlive: func(req, res interface{}) error {
+					atomic.AddUint32(&keepAliveCount, 1)
+					return nil
+				},
+				testutil.TableDeleteSession: func(req, res interface{}) error {
+					atomic.AddUint32(&deleteCount, 1)
+					return nil
+				},
+			},
+		},
+	}
+	defer p.Close(context.Background())
+
+	s1 := mustGetSession(t, p)
+	s2 := mustGetSession(t, p)
+	s3 := mustGetSession(t, p)
+	mustPutSession(t, p, s1)
+	mustPutSession(t, p, s2)
+	mustPutSession(t, p, s3)
+
+	<-timer.Created
+
+	// Sessions are not expired yet.
+	shiftTime(idleThreshold)
+	timer.C <- timeutil.Now()
+	mustResetTimer(t, timer.Reset, idleThreshold)
+	if !atomic.CompareAndSwapUint32(&keepAliveCount, 3, 0) {
+		t.Fatal("unexpected number of keepalives")
+	}
+	if n := atomic.LoadUint32(&deleteCount); n != 0 {
+		t.Fatalf("unexpected number of deletes: %d", n)
+	}
+
+	// Sessions are expired now, but one session must be kept alive due to
+	// MinSize.
+	shiftTime(idleThreshold)
+	timer.C <- timeutil.Now()
+	mustResetTimer(t, timer.Reset, idleThreshold)
+	if !atomic.CompareAndSwapUint32(&keepAliveCount, 1, 0) {
+		t.Fatal("unexpected number of keepalives")
+	}
+	// Note that sessions are deleted after timer reset.
+	for deadline := time.Now().Add(time.Second); ; {
+		n := atomic.LoadUint32(&deleteCount)
+		if n == 2 {
+			break
+		}
+		if n > 2 || time.Now().After(deadline) {
+			t.Fatalf("unexpected number of deletes: %d; want 2", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	p.mu.Lock()
+	size := len(p.index)
+	p.mu.Unlock()
+	if size != 1 {
+		t.Fatalf("unexpected pool size: %d; want 1", size)
+	}
+}
+
 func TestSessionPoolKeepAliveOrdering(t *testing.T) {
 	timer := timetest.StubSingleTimer(t)
 	defer timer.Cleanup()
@@ -958,6 +1044,276 @@ func TestSessionPoolKeepAliveOrdering(t *testing.T) {
 	}
 }
 
+func TestSessionPoolKeepAliveConcurrency(t *testing.T) {
+	timer := timetest.StubSingleTimer(t)
+	defer timer.Cleanup()
+
+	shiftTime, cleanup := timeutil.StubTestHookTimeNow(time.Unix(0, 0))
+	defer cleanup()
+
+	var (
+		idleThreshold = 4 * time.Second
+		concurrency   = 2
+
+		mu       sync.Mutex
+		inflight int
+		maximum  int
+		total    int
+	)
+	p := &SessionPool{
+		SizeLimit:            5,
+		IdleThreshold:        idleThreshold,
+		KeepAliveConcurrency: concurrency,
+		BusyCheckInterval:    -1,
+		Builder: &StubBuilder{
+			T:     t,
+			Limit: 5,
+			Handler: methodHandlers{
+				testutil.TableKeepAlive: func(req, res interface{}) error {
+					mu.Lock()
+					inflight++
+					total++
+					if inflight > maximum {
+						maximum = inflight
+					}
+					mu.Unlock()
+
+					time.Sleep(5 * time.Millisecond)
+
+					mu.Lock()
+					inflight--
+					mu.Unlock()
+					return nil
+				},
+				testutil.TableDeleteSession: okHandler,
+			},
+		},
+	}
+	defer p.Close(context.Background())
+
+	ss := make([]*Session, 5)
+	for i := range ss {
+		ss[i] = mustGetSession(t, p)
+	}
+	for _, s := range ss {
+		mustPutSession(t, p, s)
+	}
+	<-timer.Created
+
+	shiftTime(idleThreshold)
+	timer.C <- timeutil.Now()
+	mustResetTimer(t, timer.Reset, idleThreshold)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if total != len(ss) {
+		t.Errorf("unexpected number of keepalives: %d; want %d", total, len(ss))
+	}
+	if maximum > concurrency {
+		t.Errorf("unexpected keepalive concurrency: %d; want at most %d", maximum, concurrency)
+	}
+}
+
+func TestSessionPoolCloseConcurrency(t *testing.T) {
+	var (
+		concurrency = 2
+
+		mu       sync.Mutex
+		inflight int
+		maximum  int
+		total    int
+	)
+	p := &SessionPool{
+		SizeLimit:         5,
+		IdleThreshold:     -1,
+		BusyCheckInterval: -1,
+		DeleteConcurrency: concurrency,
+		Builder: &StubBuilder{
+			T:     t,
+			Limit: 5,
+			Handler: methodHandlers{
+				testutil.TableDeleteSession: func(req, res interface{}) error {
+					mu.Lock()
+					inflight++
+					total++
+					if inflight > maximum {
+						maximum = inflight
+					}
+					mu.Unlock()
+
+					time.Sleep(5 * time.Millisecond)
+
+					mu.Lock()
+					inflight--
+					mu.Unlock()
+					return nil
+				},
+			},
+		},
+	}
+
+	ss := make([]*Session, 5)
+	for i := range ss {
+		ss[i] = mustGetSession(t, p)
+	}
+	for _, s := range ss {
+		mustPutSession(t, p, s)
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if total != len(ss) {
+		t.Errorf("unexpected number of deletions: %d; want %d", total, len(ss))
+	}
+	if maximum > concurrency {
+		t.Errorf("unexpected deletion concurrency: %d; want at most %d", maximum, concurrency)
+	}
+	if maximum < concurrency {
+		t.Errorf("sessions were not deleted concurrently")
+	}
+}
+
+func TestSessionPoolKeepAliveJitter(t *testing.T) {
+	timer := timetest.StubSingleTimer(t)
+	defer timer.Cleanup()
+
+	shiftTime, cleanup := timeutil.StubTestHookTimeNow(time.Unix(100, 0))
+	defer cleanup()
+
+	idleThreshold := 4 * time.Second
+	p := &SessionPool{
+		SizeLimit:         10,
+		IdleThreshold:     idleThreshold,
+		KeepAliveJitter:   0.5,
+		BusyCheckInterval: -1,
+		Builder: &StubBuilder{
+			T:     t,
+			Limit: 10,
+			Handler: methodHandlers{
+				testutil.TableKeepAlive:     okHandler,
+				testutil.TableDeleteSession: okHandler,
+			},
+		},
+	}
+	defer p.Close(context.Background())
+
+	ss := make([]*Session, 10)
+	for i := range ss {
+		ss[i] = mustGetSession(t, p)
+	}
+	for _, s := range ss {
+		mustPutSession(t, p, s)
+	}
+	<-timer.Created
+
+	// assertTouched checks that idle sessions are ordered by touch time,
+	// which is within [now - max, now], and returns number of distinct
+	// touch times.
+	assertTouched := func(now time.Time, max time.Duration) int {
+		t.Helper()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		var (
+			prev     time.Time
+			distinct = make(map[time.Time]bool)
+		)
+		for el := p.idle.Front(); el != nil; el = el.Next() {
+			touched := p.index[el.Value.(*Session)].touched
+			if touched.After(now) || now.Sub(touched) > max {
+				t.Errorf("unexpected touch time: %v", touched)
+			}
+			if touched.Before(prev) {
+				t.Errorf("idle sessions are not ordered by touch time")
+			}
+			prev = touched
+			distinct[touched] = true
+		}
+		return len(distinct)
+	}
+
+	// No jitter must be applied on Put().
+	if n := assertTouched(timeutil.Now(), 0); n != 1 {
+		t.Errorf("unexpected jitter on put")
+	}
+
+	shiftTime(idleThreshold)
+	now := timeutil.Now()
+	timer.C <- now
+	select {
+	case d := <-timer.Reset:
+		if d < idleThreshold/2 || d > idleThreshold {
+			t.Errorf("unexpected timer reset: %s", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no timer reset")
+	}
+	if n := assertTouched(now, idleThreshold/2); n < 2 {
+		t.Errorf("no jitter applied to touch time after keep alive")
+	}
+}
+
+func TestSessionPoolLeakDetector(t *testing.T) {
+	const threshold = time.Minute
+	var (
+		clock = timetest.NewClock(time.Unix(0, 0))
+		leaks = make(chan SessionPoolLeakInfo, 2)
+	)
+	p := &SessionPool{
+		SizeLimit:         2,
+		IdleThreshold:     -1,
+		BusyCheckInterval: -1,
+		LeakThreshold:     threshold,
+		Clock:             clock,
+		Builder: &StubBuilder{
+			T:       t,
+			Limit:   2,
+			Handler: methodHandlers{
+				testutil.TableDeleteSession: okHandler,
+			},
+		},
+		Trace: SessionPoolTrace{
+			Leak: func(info SessionPoolLeakInfo) {
+				leaks <- info
+			},
+		},
+	}
+	defer p.Close(context.Background())
+
+	s1 := mustGetSession(t, p)
+	s2 := mustGetSession(t, p)
+	mustPutSession(t, p, s2)
+
+	clock.Shift(threshold)
+
+	const timeout = time.Second
+	select {
+	case info := <-leaks:
+		if info.Session != s1 {
+			t.Errorf("unexpected leaked session")
+		}
+		if info.Held != threshold {
+			t.Errorf("unexpected held duration: %v; want %v", info.Held, threshold)
+		}
+		if !strings.Contains(string(info.Stack), "TestSessionPoolLeakDetector") {
+			t.Errorf("unexpected stack trace:\n%s", info.Stack)
+		}
+	case <-time.After(timeout):
+		t.Fatalf("no leak reported")
+	}
+
+	// Each session must be reported only once.
+	clock.Shift(threshold)
+	select {
+	case <-leaks:
+		t.Fatalf("unexpected leak report")
+	case <-time.After(timeout / 10):
+	}
+	mustPutSession(t, p, s1)
+}
+
 func TestSessionPoolDoublePut(t *testing.T) {
 	p := &SessionPool{
 		SizeLimit:         2, // Skip panic on full pool.
@@ -0,0 +1,68 @@
+package table
+
+import (
+	"context"
+)
+
+// ReadProgress describes progress of the streaming read.
+type ReadProgress struct {
+	// ResultSet is the index of the last received result set.
+	ResultSet int
+
+	// SetRows is the number of rows in the last received result set.
+	SetRows int
+
+	// Rows and Bytes are the total number of rows and size of the response
+	// messages received so far.
+	Rows  uint64
+	Bytes uint64
+}
+
+type readProgressContextKey struct{}
+
+// WithReadProgress returns a copy of parent context in which streaming reads
+// (such as StreamReadTable() and StreamExecuteScanQuery()) call f each time
+// the next result set is received from the server. It helps long reads to display progress or to
+// detect stalled ones.
+//
+// Note that f is called before the result set is consumed by the
+// NextStreamSet() and must not block.
+func WithReadProgress(ctx context.Context, f func(ReadProgress)) context.Context {
+	return context.WithValue(ctx, readProgressContextKey{}, f)
+}
+
+// ContextReadProgress returns progress callback stored in the context by
+// WithReadProgress(). It returns nil if no callback is set.
+func ContextReadProgress(ctx context.Context) func(ReadProgress) {
+	f, _ := ctx.Value(readProgressContextKey{}).(func(ReadProgress))
+	return f
+}
+
+// readProgress accounts received result sets of a single streaming read.
+// Nil *readProgress is a valid no-op tracker.
+type readProgress struct {
+	f func(ReadProgress)
+	p ReadProgress
+}
+
+func newReadProgress(ctx context.Context) *readProgress {
+	f := ContextReadProgress(ctx)
+	if f == nil {
+		return nil
+	}
+	return &readProgress{
+		f: f,
+		p: ReadProgress{ResultSet: -1},
+	}
+}
+
+func (r *readProgress) add(rows, bytes int) {
+	if r == nil {
+		return
+	}
+	r.p.ResultSet++
+	r.p.SetRows = rows
+	r.p.Rows += uint64(rows)
+	r.p.Bytes += uint64(bytes)
+	r.f(r.p)
+}
@@ -0,0 +1,101 @@
+package table
+
+import (
+	"strings"
+)
+
+// ReadOnlyError is returned by the Client in read-only mode when operation
+// which may modify data or schema is requested. See Client.ReadOnly.
+type ReadOnlyError struct {
+	// Operation is the name of the rejected operation, such as
+	// "ExecuteDataQuery" or "CreateTable".
+	Operation string
+
+	// Statement is the data modification statement found in the query text.
+	// It is empty for operations other than data queries.
+	Statement string
+}
+
+func (e *ReadOnlyError) Error() string {
+	if e.Statement != "" {
+		return "ydb: table: " + e.Statement + " statement in " + e.Operation + " is forbidden in read-only mode"
+	}
+	return "ydb: table: " + e.Operation + " is forbidden in read-only mode"
+}
+
+// writeStatements contains keywords of YQL statements which modify data or
+// schema.
+var writeStatements = map[string]bool{
+	"INSERT":  true,
+	"UPSERT":  true,
+	"REPLACE": true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"CREATE":  true,
+	"ALTER":   true,
+	"DROP":    true,
+}
+
+// checkReadOnly returns *ReadOnlyError if client is in read-only mode.
+func (t *Client) checkReadOnly(op string) error {
+	if !t.ReadOnly {
+		return nil
+	}
+	return &ReadOnlyError{
+		Operation: op,
+	}
+}
+
+// checkReadOnlyQuery returns *ReadOnlyError if client is in read-only mode
+// and given query contains data modification statements.
+func (t *Client) checkReadOnlyQuery(op, yql string) error {
+	if !t.ReadOnly {
+		return nil
+	}
+	if stmt := writeStatement(yql); stmt != "" {
+		return &ReadOnlyError{
+			Operation: op,
+			Statement: stmt,
+		}
+	}
+	return nil
+}
+
+// writeStatement returns the first data modification keyword found in the
+// given YQL text, or empty string if there are no such keywords.
+//
+// Note that keywords are searched regardless of their position in the query,
+// such that unquoted identifiers equal to the keywords (e.g. a column named
+// "update") are treated as data modification statements too. Such
+// identifiers must be quoted with backticks.
+func writeStatement(yql string) string {
+	// Normalization strips comments and literals.
+	s := NormalizeQuery(yql)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '`':
+			j := strings.IndexByte(s[i+1:], '`')
+			if j < 0 {
+				return ""
+			}
+			i += j + 2
+
+		case c == '$' || isQueryWord(c):
+			n := 1 + queryWordLen(s[i+1:])
+			w := s[i : i+n]
+			// Skip parameters and names qualified by "::", such as
+			// Re2::Replace.
+			if c != '$' && (i == 0 || s[i-1] != ':') {
+				if w = strings.ToUpper(w); writeStatements[w] {
+					return w
+				}
+			}
+			i += n
+
+		default:
+			i++
+		}
+	}
+	return ""
+}
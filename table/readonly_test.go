@@ -0,0 +1,97 @@
+package table
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestWriteStatement(t *testing.T) {
+	for _, test := range []struct {
+		yql string
+		exp string
+	}{
+		{
+			yql: "SELECT * FROM series WHERE series_id = 1",
+		},
+		{
+			yql: "DECLARE $id AS Uint64; upsert INTO series (series_id) VALUES ($id)",
+			exp: "UPSERT",
+		},
+		{
+			yql: "SELECT 'DELETE FROM series' -- UPDATE series\n/* INSERT */",
+		},
+		{
+			yql: "SELECT `update`, $delete, Re2::Replace('a') FROM series",
+		},
+		{
+			yql: "$x = SELECT 1; REPLACE INTO series SELECT * FROM $x",
+			exp: "REPLACE",
+		},
+		{
+			yql: "DELETE FROM series ON SELECT 1 AS series_id",
+			exp: "DELETE",
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			if act := writeStatement(test.yql); act != test.exp {
+				t.Errorf("unexpected statement for %q: %q; want %q", test.yql, act, test.exp)
+			}
+		})
+	}
+}
+
+func TestClientReadOnly(t *testing.T) {
+	var calls []testutil.MethodCode
+	c := Client{
+		ReadOnly: true,
+		Driver: &testutil.Driver{
+			OnCall: func(_ context.Context, m testutil.MethodCode, _, res interface{}) error {
+				calls = append(calls, m)
+				if r, ok := res.(*Ydb_Table.ExecuteQueryResult); ok {
+					r.TxMeta = new(Ydb_Table.TransactionMeta)
+				}
+				return nil
+			},
+		},
+	}
+	s := &Session{
+		ID: "session",
+		c:  c,
+	}
+	ctx := context.Background()
+
+	assertReadOnly := func(err error, op string) {
+		t.Helper()
+		e, ok := err.(*ReadOnlyError)
+		if !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e.Operation != op {
+			t.Fatalf("unexpected operation: %q; want %q", e.Operation, op)
+		}
+	}
+	assertReadOnly(s.CreateTable(ctx, "series"), "CreateTable")
+	assertReadOnly(s.DropTable(ctx, "series"), "DropTable")
+	assertReadOnly(s.ExecuteSchemeQuery(ctx, "DROP TABLE series"), "ExecuteSchemeQuery")
+	assertReadOnly(s.BulkUpsert(ctx, "series", nil), "BulkUpsert")
+
+	_, _, err := s.Execute(ctx, TxControl(), "UPDATE series SET title = 'foo'", nil)
+	assertReadOnly(err, "ExecuteDataQuery")
+	_, err = s.Prepare(ctx, "INSERT INTO series (series_id) VALUES (1)")
+	assertReadOnly(err, "PrepareDataQuery")
+
+	if len(calls) != 0 {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+	_, _, err = s.Execute(ctx, TxControl(), "SELECT 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0] != testutil.TableExecuteDataQuery {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
@@ -42,9 +42,12 @@ type Result struct {
 
 	stats *Ydb_TableStats.QueryStats
 
-	setCh       chan *Ydb.ResultSet
-	setChErr    *error
-	setChCancel func()
+	setCh        chan *Ydb.ResultSet
+	setChErr     *error
+	setChCancel  func()
+	setChProfile *string
+
+	profile string
 
 	err    error
 	closed bool
@@ -55,6 +58,13 @@ func (r *Result) Stats() QueryStats {
 	return QueryStats{stats: r.stats}
 }
 
+// Profile returns execution profile of the scan query (see
+// WithScanQueryProfileModeBasic()). It is available only after
+// NextStreamSet() returns false.
+func (r *Result) Profile() string {
+	return r.profile
+}
+
 // SetCount returns number of result sets.
 // Note that it does not work if r is the result of streaming operation.
 func (r *Result) SetCount() int {
@@ -138,6 +148,9 @@ func (r *Result) NextStreamSet(ctx context.Context) bool {
 	case s, ok := <-r.setCh:
 		if !ok {
 			r.err = *r.setChErr
+			if r.setChProfile != nil {
+				r.profile = *r.setChProfile
+			}
 			return false
 		}
 		result.Reset(&r.Scanner, s)
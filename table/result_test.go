@@ -1,9 +1,11 @@
 package table
 
 import (
+	"encoding/binary"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	ydb "github.com/yandex-cloud/ydb-go-sdk"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
@@ -62,6 +64,150 @@ func TestResultAny(t *testing.T) {
 	}
 }
 
+func TestResultScan(t *testing.T) {
+	ts := time.Unix(1577934245, 0)
+	newResult := func() *Result {
+		return NewResult(
+			NewResultSet(
+				WithColumns(
+					Column{"id", ydb.TypeUint64},
+					Column{"name", ydb.Optional(ydb.TypeUTF8)},
+					Column{"updated", ydb.Optional(ydb.TypeTimestamp)},
+				),
+				WithValues(
+					ydb.Uint64Value(1),
+					ydb.OptionalValue(ydb.UTF8Value("foo")),
+					ydb.OptionalValue(ydb.TimestampValue(ydb.Time(ts).Timestamp())),
+
+					ydb.Uint64Value(2),
+					ydb.NullValue(ydb.TypeUTF8),
+					ydb.NullValue(ydb.TypeTimestamp),
+				),
+			),
+		)
+	}
+	res := newResult()
+	type row struct {
+		id      uint64
+		name    string
+		updated *time.Time
+	}
+	var rows []row
+	for res.NextSet() {
+		for res.NextRow() {
+			var r row
+			if err := res.Scan(&r.id, &r.name, &r.updated); err != nil {
+				t.Fatal(err)
+			}
+			rows = append(rows, r)
+		}
+	}
+	exp := []row{
+		{1, "foo", &ts},
+		{2, "", nil},
+	}
+	if !reflect.DeepEqual(rows, exp) {
+		t.Fatalf("unexpected rows: %+v; want %+v", rows, exp)
+	}
+
+	res = newResult()
+	res.NextSet()
+	res.NextRow()
+	var (
+		id   string
+		name string
+		ts2  time.Time
+	)
+	if err := res.Scan(&id, &name, &ts2); err == nil {
+		t.Fatalf("no error for mismatched types")
+	}
+	if res.Err() == nil {
+		t.Fatalf("scan error does not break the result")
+	}
+}
+
+func TestResultScanNotNullPointer(t *testing.T) {
+	res := NewResult(
+		NewResultSet(
+			WithColumns(
+				Column{"id", ydb.TypeUint64},
+			),
+			WithValues(
+				ydb.Uint64Value(42),
+			),
+		),
+	)
+	res.NextSet()
+	res.NextRow()
+	var id *uint64
+	if err := res.Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	if id == nil || *id != 42 {
+		t.Fatalf("unexpected id: %v", id)
+	}
+}
+
+func TestResultScanStruct(t *testing.T) {
+	var dec [16]byte
+	binary.BigEndian.PutUint64(dec[8:], 1500000000) // 1.5 with scale 9.
+	res := NewResult(
+		NewResultSet(
+			WithColumns(
+				Column{"series_id", ydb.TypeUint64},
+				Column{"title", ydb.Optional(ydb.TypeUTF8)},
+				Column{"price", ydb.DefaultDecimal},
+				Column{"info", ydb.Optional(ydb.Struct(
+					ydb.StructField("views", ydb.TypeUint32),
+					ydb.StructField("tags", ydb.Optional(ydb.TypeUTF8)),
+				))},
+				Column{"unknown", ydb.TypeBool},
+			),
+			WithValues(
+				ydb.Uint64Value(1),
+				ydb.OptionalValue(ydb.UTF8Value("foo")),
+				ydb.DecimalValue(ydb.DefaultDecimal, dec),
+				ydb.OptionalValue(ydb.StructValue(
+					ydb.StructFieldValue("views", ydb.Uint32Value(42)),
+					ydb.StructFieldValue("tags", ydb.NullValue(ydb.TypeUTF8)),
+				)),
+				ydb.BoolValue(true),
+			),
+		),
+	)
+	type info struct {
+		Views uint32
+		Tags  *string
+	}
+	type series struct {
+		ID    uint64 `ydb:"column:series_id"`
+		Title *string
+		Price string
+		Info  *info
+		Cache []byte `ydb:"-"`
+	}
+	var act series
+	for res.NextSet() {
+		for res.NextRow() {
+			if err := res.ScanStruct(&act); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	title := "foo"
+	exp := series{
+		ID:    1,
+		Title: &title,
+		Price: "1.500000000",
+		Info: &info{
+			Views: 42,
+		},
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Fatalf("unexpected struct: %+v; want %+v", act, exp)
+	}
+}
+
 type resultSetDesc Ydb.ResultSet
 
 type ResultSetOption func(*resultSetDesc)
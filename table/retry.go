@@ -3,6 +3,7 @@ package table
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/yandex-cloud/ydb-go-sdk"
 )
@@ -92,9 +93,32 @@ type Retryer struct {
 	// not found error.
 	RetryChecker ydb.RetryChecker
 
+	// Idempotent reports whether operation is idempotent and thus may be
+	// retried on transport errors. See ydb.RetryChecker.CheckIdempotent().
+	Idempotent bool
+
 	// Backoff is a selected backoff policy.
 	// If backoff is nil, then the DefaultBackoff is used.
 	Backoff ydb.Backoff
+
+	// BackoffPolicy contains backoff overrides for particular errors. When
+	// there is no override for an error, Backoff is used.
+	BackoffPolicy ydb.BackoffPolicy
+
+	// DeadlineShare is the share of the context's remaining time given to
+	// each attempt except the last one. For example, if DeadlineShare is 0.5,
+	// then the first attempt is limited by a half of the time left before
+	// the context's deadline, the second one by a half of the rest and so on,
+	// while the last attempt may use all the remaining time. This prevents
+	// the first attempt from eating the whole budget of a slow operation.
+	//
+	// Attempts of idempotent operations interrupted by their own deadline
+	// are retried immediately. Non-idempotent operations are not retried in
+	// that case because they may be already completed on the server.
+	//
+	// If DeadlineShare is not in (0, 1) range or context has no deadline,
+	// then attempts are limited only by the context.
+	DeadlineShare float64
 }
 
 // Retry calls Retryer.Do() configured with default values.
@@ -104,6 +128,7 @@ func Retry(ctx context.Context, s SessionProvider, op Operation) error {
 		MaxRetries:      ydb.DefaultMaxRetries,
 		RetryChecker:    ydb.DefaultRetryChecker,
 		Backoff:         ydb.DefaultBackoff,
+		BackoffPolicy:   ydb.DefaultBackoffPolicy,
 	}).Do(ctx, op)
 }
 
@@ -133,10 +158,26 @@ func (r Retryer) Do(ctx context.Context, op Operation) (err error) {
 				return
 			}
 		}
-		if err = op.Do(ctx, s); err == nil {
+		actx, cancel := r.attemptContext(ctx, i)
+		err = op.Do(actx, s)
+		cancel()
+		if err == nil {
 			return nil
 		}
-		m = r.RetryChecker.Check(err)
+		switch {
+		case actx.Err() != nil && ctx.Err() == nil:
+			// Attempt's own deadline exceeded. Session may be still busy
+			// with the interrupted operation. Non-idempotent operation may
+			// be already completed on the server, thus it is not retried.
+			m = ydb.RetryCheckSession
+			if r.Idempotent {
+				m |= ydb.RetryAvailable
+			}
+		case r.Idempotent:
+			m = r.RetryChecker.CheckIdempotent(err)
+		default:
+			m = r.RetryChecker.Check(err)
+		}
 		switch {
 		case m.MustDeleteSession():
 			defer s.Close(ctx)
@@ -149,8 +190,8 @@ func (r Retryer) Do(ctx context.Context, op Operation) (err error) {
 		if !m.Retriable() {
 			return err
 		}
-		if m.MustBackoff() {
-			if e := ydb.WaitBackoff(ctx, r.Backoff, i); e != nil {
+		if b := r.BackoffPolicy.Backoff(err, m, r.Backoff); b != nil {
+			if e := ydb.WaitBackoff(ctx, b, i); e != nil {
 				// Return original error to make it possible to lay on for the
 				// client.
 				return err
@@ -160,6 +201,17 @@ func (r Retryer) Do(ctx context.Context, op Operation) (err error) {
 	return err
 }
 
+// attemptContext returns context for the i-th attempt with respect to the
+// DeadlineShare.
+func (r Retryer) attemptContext(ctx context.Context, i int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || i >= r.MaxRetries || r.DeadlineShare <= 0 || r.DeadlineShare >= 1 {
+		return ctx, func() {}
+	}
+	t := time.Duration(float64(time.Until(deadline)) * r.DeadlineShare)
+	return context.WithTimeout(ctx, t)
+}
+
 var (
 	errNoSession         = errors.New("no session")
 	errUnexpectedSession = errors.New("unexpected session")
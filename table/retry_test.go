@@ -135,6 +135,61 @@ func TestRetryerBadSession(t *testing.T) {
 	}
 }
 
+func TestRetryerIdempotentTransportError(t *testing.T) {
+	for _, idempotent := range []bool{false, true} {
+		t.Run("", func(t *testing.T) {
+			var (
+				busy  int
+				calls int
+			)
+			r := Retryer{
+				MaxRetries: 2,
+				Idempotent: idempotent,
+				Backoff: ydb.BackoffFunc(func(n int) <-chan time.Time {
+					ch := make(chan time.Time, 1)
+					ch <- time.Time{}
+					return ch
+				}),
+				SessionProvider: SessionProviderFunc{
+					OnGet: func(context.Context) (*Session, error) {
+						return new(Session), nil
+					},
+					OnPut: func(context.Context, *Session) error {
+						return nil
+					},
+					OnPutBusy: func(context.Context, *Session) error {
+						busy++
+						return nil
+					},
+				},
+			}
+			testErr := &ydb.TransportError{
+				Reason: ydb.TransportErrorUnavailable,
+			}
+			err := r.Do(
+				context.Background(),
+				OperationFunc(func(ctx context.Context, _ *Session) error {
+					calls++
+					return testErr
+				}),
+			)
+			if err != testErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			exp := 1
+			if idempotent {
+				exp = r.MaxRetries + 1
+			}
+			if calls != exp {
+				t.Errorf("unexpected operation calls: %v; want %v", calls, exp)
+			}
+			if busy != exp {
+				t.Errorf("unexpected busy sessions: %v; want %v", busy, exp)
+			}
+		})
+	}
+}
+
 func TestRetryerBadSessionReuse(t *testing.T) {
 	client := &Client{
 		Driver: &testutil.Driver{
@@ -223,3 +278,84 @@ func TestRetryerImmediateReturn(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryerDeadlineShare(t *testing.T) {
+	const timeout = 400 * time.Millisecond
+	r := Retryer{
+		MaxRetries:    2,
+		DeadlineShare: 0.5,
+		Idempotent:    true,
+		SessionProvider: SessionProviderFunc{
+			OnGet: func(context.Context) (*Session, error) {
+				return simpleSession(), nil
+			},
+			OnPut: func(context.Context, *Session) error {
+				return nil
+			},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	var budgets []time.Duration
+	err := r.Do(ctx, OperationFunc(func(ctx context.Context, _ *Session) error {
+		d, _ := ctx.Deadline()
+		budgets = append(budgets, time.Until(d))
+		if d.Equal(deadline) {
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(budgets) != 3 {
+		t.Fatalf("unexpected number of attempts: %d", len(budgets))
+	}
+	if b := budgets[0]; b > timeout/2 || b < timeout/4 {
+		t.Errorf("unexpected first attempt budget: %v", b)
+	}
+	if b := budgets[1]; b > timeout/4 || b < timeout/8 {
+		t.Errorf("unexpected second attempt budget: %v", b)
+	}
+}
+
+func TestRetryerDeadlineShareNonIdempotent(t *testing.T) {
+	var busy int
+	r := Retryer{
+		MaxRetries:    2,
+		DeadlineShare: 0.5,
+		SessionProvider: SessionProviderFunc{
+			OnGet: func(context.Context) (*Session, error) {
+				return simpleSession(), nil
+			},
+			OnPut: func(context.Context, *Session) error {
+				return nil
+			},
+			OnPutBusy: func(context.Context, *Session) error {
+				busy++
+				return nil
+			},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var attempts int
+	err := r.Do(ctx, OperationFunc(func(ctx context.Context, _ *Session) error {
+		attempts++
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	if err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("unexpected number of attempts: %d", attempts)
+	}
+	if busy != 1 {
+		t.Fatalf("interrupted session is not checked")
+	}
+}
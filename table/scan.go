@@ -0,0 +1,103 @@
+package table
+
+import (
+	"context"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/grpc/draft/Ydb_Experimental_V1"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Experimental"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+type (
+	scanQueryDesc   Ydb_Experimental.ExecuteStreamQueryRequest
+	ScanQueryOption func(*scanQueryDesc)
+)
+
+// WithScanQueryProfileModeNone returns ScanQueryOption which disables
+// collection of the query execution profile.
+func WithScanQueryProfileModeNone() ScanQueryOption {
+	return func(d *scanQueryDesc) {
+		d.ProfileMode = Ydb_Experimental.ExecuteStreamQueryRequest_NONE
+	}
+}
+
+// WithScanQueryProfileModeBasic returns ScanQueryOption which enables
+// collection of the basic query execution profile. The profile is available
+// by the result's Profile() method after all result sets are read.
+func WithScanQueryProfileModeBasic() ScanQueryOption {
+	return func(d *scanQueryDesc) {
+		d.ProfileMode = Ydb_Experimental.ExecuteStreamQueryRequest_BASIC
+	}
+}
+
+// StreamExecuteScanQuery executes read-only scan query and returns its
+// result as a stream of result sets (parts), which are iterated by the
+// NextStreamSet() method. Unlike ExecuteDataQuery(), the number of rows
+// returned by the scan query is not limited, thus it is suitable for the
+// analytical queries over big tables.
+//
+// Note that scan queries are served by the experimental API. They are not
+// executed within the session's transactions; the session is used only to
+// reach the database.
+func (s *Session) StreamExecuteScanQuery(
+	ctx context.Context, query string, params *QueryParameters,
+	opts ...ScanQueryOption,
+) (r *Result, err error) {
+	var resp Ydb_Experimental.ExecuteStreamQueryResponse
+	req := Ydb_Experimental.ExecuteStreamQueryRequest{
+		YqlText:    query,
+		Parameters: params.params(),
+	}
+	for _, opt := range opts {
+		opt((*scanQueryDesc)(&req))
+	}
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(ctx)
+
+	var (
+		ch = make(chan *Ydb.ResultSet, 1)
+		ce = new(error)
+		cp = new(string)
+		rp = newReadProgress(ctx)
+	)
+	err = s.c.Driver.StreamRead(ctx, internal.WrapStreamOperation(
+		Ydb_Experimental_V1.ExecuteStreamQuery, &req, &resp,
+		func(err error) {
+			if err != io.EOF {
+				*ce = err
+			}
+			if err != nil {
+				close(ch)
+				return
+			}
+			if p := resp.GetResult().GetProfile(); p != "" {
+				*cp = p
+			}
+			set := resp.GetResult().GetResultSet()
+			if set == nil {
+				return
+			}
+			rp.add(len(set.GetRows()), proto.Size(&resp))
+			select {
+			case <-ctx.Done():
+			case ch <- set:
+			}
+		},
+	))
+	if err != nil {
+		cancel()
+		return
+	}
+	r = &Result{
+		setCh:        ch,
+		setChErr:     ce,
+		setChCancel:  cancel,
+		setChProfile: cp,
+	}
+	return r, nil
+}
@@ -0,0 +1,89 @@
+package table
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Experimental"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestSessionStreamExecuteScanQuery(t *testing.T) {
+	var req *Ydb_Experimental.ExecuteStreamQueryRequest
+	s := &Session{
+		ID: "session",
+		c: Client{
+			Driver: &testutil.Driver{
+				OnStreamRead: func(_ context.Context, _ testutil.MethodCode, r, res interface{}, process func(error)) error {
+					req = r.(*Ydb_Experimental.ExecuteStreamQueryRequest)
+					go func() {
+						resp := res.(*Ydb_Experimental.ExecuteStreamQueryResponse)
+						for i := 0; i < 2; i++ {
+							resp.Result = &Ydb_Experimental.ExecuteStreamQueryResult{
+								Result: &Ydb_Experimental.ExecuteStreamQueryResult_ResultSet{
+									ResultSet: NewResultSet(
+										WithColumns(Column{"series_id", ydb.TypeUint64}),
+										WithValues(ydb.Uint64Value(uint64(i))),
+									),
+								},
+							}
+							process(nil)
+						}
+						resp.Result = &Ydb_Experimental.ExecuteStreamQueryResult{
+							Result: &Ydb_Experimental.ExecuteStreamQueryResult_Profile{
+								Profile: "profile",
+							},
+						}
+						process(nil)
+						process(io.EOF)
+					}()
+					return nil
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	params := NewQueryParameters(
+		ValueParam("$min", ydb.Uint64Value(0)),
+	)
+	res, err := s.StreamExecuteScanQuery(ctx, "SELECT series_id FROM series", params,
+		WithScanQueryProfileModeBasic(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if act, exp := req.YqlText, "SELECT series_id FROM series"; act != exp {
+		t.Errorf("unexpected query: %q; want %q", act, exp)
+	}
+	if _, ok := req.Parameters["$min"]; !ok {
+		t.Errorf("no query parameter in request: %+v", req)
+	}
+	if act, exp := req.ProfileMode, Ydb_Experimental.ExecuteStreamQueryRequest_BASIC; act != exp {
+		t.Errorf("unexpected profile mode: %v; want %v", act, exp)
+	}
+
+	var ids []uint64
+	for res.NextStreamSet(ctx) {
+		for res.NextRow() {
+			var id uint64
+			if err := res.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if err := res.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []uint64{0, 1}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("unexpected ids: %v; want %v", ids, exp)
+	}
+	if act, exp := res.Profile(), "profile"; act != exp {
+		t.Errorf("unexpected profile: %q; want %q", act, exp)
+	}
+}
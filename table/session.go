@@ -3,8 +3,12 @@ package table
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"runtime"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
 
 	"github.com/yandex-cloud/ydb-go-sdk"
 	"github.com/yandex-cloud/ydb-go-sdk/api/grpc/Ydb_Table_V1"
@@ -16,6 +20,8 @@ import (
 
 var DefaultMaxQueryCacheSize = 1000
 
+var errNoCopyTablesItems = errors.New("ydb: table: no tables to copy")
+
 // Client contains logic of creation of ydb table sessions.
 type Client struct {
 	Driver ydb.Driver
@@ -27,6 +33,38 @@ type Client struct {
 	// If MaxQueryCacheSize is less than or equal to zero, then the
 	// DefaultMaxQueryCacheSize is used.
 	MaxQueryCacheSize int
+
+	// SlowQueryLog is an optional log of data queries which execution takes
+	// too long.
+	SlowQueryLog *SlowQueryLog
+
+	// MaxRequestSize limits size of the serialized data query request
+	// (query text and parameters) in bytes. Requests exceeding the limit are
+	// not sent and *RequestSizeError is returned instead. It is useful to get
+	// descriptive error rather than the transport one when server's message
+	// size limit is known.
+	//
+	// If MaxRequestSize is less than or equal to zero, then request size is
+	// not checked.
+	MaxRequestSize int
+
+	// ReadOnly makes client reject operations which may modify data or
+	// schema with *ReadOnlyError before sending them to the server. That is,
+	// schema changes, bulk upserts and data queries containing data
+	// modification statements are rejected.
+	//
+	// Note that the check is made on the client side and it is not a
+	// replacement for the proper access rights.
+	ReadOnly bool
+}
+
+// Path returns path of the table with given path elements relative to the
+// database which Driver is dialed to. Relative paths passed to the Session
+// methods are resolved the same way.
+//
+// See ydb.JoinPath() for details.
+func (t *Client) Path(elems ...string) string {
+	return ydb.Path(t.Driver, elems...)
 }
 
 // CreateSession creates new session instance.
@@ -78,8 +116,10 @@ type Session struct {
 
 	c Client
 
-	qcache lru.Cache
-	qhash  queryHasher
+	qmu       sync.Mutex // Guards qcache, qhash and preparing.
+	qcache    lru.Cache
+	qhash     queryHasher
+	preparing map[queryHash]*prepareCall
 
 	closed  bool
 	onClose []func()
@@ -138,9 +178,12 @@ func (s *Session) KeepAlive(ctx context.Context) (info SessionInfo, err error) {
 
 // CreateTable creates table at given path with given options.
 func (s *Session) CreateTable(ctx context.Context, path string, opts ...CreateTableOption) error {
+	if err := s.c.checkReadOnly("CreateTable"); err != nil {
+		return err
+	}
 	req := Ydb_Table.CreateTableRequest{
 		SessionId: s.ID,
-		Path:      path,
+		Path:      s.c.Path(path),
 	}
 	for _, opt := range opts {
 		opt((*createTableDesc)(&req))
@@ -149,23 +192,33 @@ func (s *Session) CreateTable(ctx context.Context, path string, opts ...CreateTa
 }
 
 // DescribeTable describes table at given path.
-func (s *Session) DescribeTable(ctx context.Context, path string) (desc Description, err error) {
+func (s *Session) DescribeTable(ctx context.Context, path string, opts ...DescribeTableOption) (desc Description, err error) {
 	var res Ydb_Table.DescribeTableResult
 	req := Ydb_Table.DescribeTableRequest{
 		SessionId: s.ID,
-		Path:      path,
+		Path:      s.c.Path(path),
+	}
+	for _, opt := range opts {
+		opt((*describeTableDesc)(&req))
 	}
 	err = s.c.Driver.Call(ctx, internal.Wrap(Ydb_Table_V1.DescribeTable, &req, &res))
 	if err != nil {
 		return desc, err
 	}
 
+	var fs map[string]string
 	cs := make([]Column, len(res.Columns))
 	for i, c := range res.Columns {
 		cs[i] = Column{
 			Name: c.Name,
 			Type: internal.TypeFromYDB(c.Type),
 		}
+		if c.Family != "" {
+			if fs == nil {
+				fs = make(map[string]string)
+			}
+			fs[c.Name] = c.Family
+		}
 	}
 
 	rs := make([]KeyRange, len(res.ShardKeyBounds)+1)
@@ -185,19 +238,39 @@ func (s *Session) DescribeTable(ctx context.Context, path string) (desc Descript
 		rs[i].From = last
 	}
 
+	var is []IndexDescription
+	if len(res.Indexes) > 0 {
+		is = make([]IndexDescription, len(res.Indexes))
+		for i, x := range res.Indexes {
+			is[i] = indexDescription(x)
+		}
+	}
+
+	var stats *TableStats
+	if res.TableStats != nil {
+		stats = new(TableStats)
+		stats.init(res.TableStats)
+	}
+
 	return Description{
-		Name:       res.Self.Name,
-		PrimaryKey: res.PrimaryKey,
-		Columns:    cs,
-		KeyRanges:  rs,
+		Name:           res.Self.Name,
+		PrimaryKey:     res.PrimaryKey,
+		Columns:        cs,
+		KeyRanges:      rs,
+		Indexes:        is,
+		ColumnFamilies: fs,
+		Stats:          stats,
 	}, nil
 }
 
 // DropTable drops table at given path with given options.
 func (s *Session) DropTable(ctx context.Context, path string, opts ...DropTableOption) error {
+	if err := s.c.checkReadOnly("DropTable"); err != nil {
+		return err
+	}
 	req := Ydb_Table.DropTableRequest{
 		SessionId: s.ID,
-		Path:      path,
+		Path:      s.c.Path(path),
 	}
 	for _, opt := range opts {
 		opt((*dropTableDesc)(&req))
@@ -207,9 +280,12 @@ func (s *Session) DropTable(ctx context.Context, path string, opts ...DropTableO
 
 // AlterTable modifies schema of table at given path with given options.
 func (s *Session) AlterTable(ctx context.Context, path string, opts ...AlterTableOption) error {
+	if err := s.c.checkReadOnly("AlterTable"); err != nil {
+		return err
+	}
 	req := Ydb_Table.AlterTableRequest{
 		SessionId: s.ID,
-		Path:      path,
+		Path:      s.c.Path(path),
 	}
 	for _, opt := range opts {
 		opt((*alterTableDesc)(&req))
@@ -217,16 +293,84 @@ func (s *Session) AlterTable(ctx context.Context, path string, opts ...AlterTabl
 	return s.c.Driver.Call(ctx, internal.Wrap(Ydb_Table_V1.AlterTable, &req, nil))
 }
 
+// AddIndex adds secondary index with given name to the table at given path.
+// At least one column must be passed with WithIndexColumns() option.
+//
+// Note that the index is built by the server asynchronously; it becomes
+// available for reads with the VIEW clause after the build is complete.
+func (s *Session) AddIndex(ctx context.Context, path, name string, opts ...IndexOption) error {
+	if err := s.c.checkReadOnly("AddIndex"); err != nil {
+		return err
+	}
+	query, err := addIndexQuery(s.c.Path(path), name, opts...)
+	if err != nil {
+		return err
+	}
+	return s.executeSchemeQuery(ctx, query)
+}
+
+// DropIndex drops secondary index with given name of the table at given path.
+func (s *Session) DropIndex(ctx context.Context, path, name string) error {
+	if err := s.c.checkReadOnly("DropIndex"); err != nil {
+		return err
+	}
+	return s.executeSchemeQuery(ctx, dropIndexQuery(s.c.Path(path), name))
+}
+
 // CopyTable creates copy of table at given path.
-func (s *Session) CopyTable(ctx context.Context, dst, src string, opts ...CopyTableOption) error {
+func (s *Session) CopyTable(ctx context.Context, dst, src string, opts ...CopyTableOption) (err error) {
+	if err = s.c.checkReadOnly("CopyTable"); err != nil {
+		return err
+	}
 	req := Ydb_Table.CopyTableRequest{
 		SessionId:       s.ID,
-		SourcePath:      src,
-		DestinationPath: dst,
+		SourcePath:      s.c.Path(src),
+		DestinationPath: s.c.Path(dst),
 	}
+	for _, opt := range opts {
+		opt((*copyTableDesc)(&req))
+	}
+	s.c.traceCopyTableStart(ctx, s, req.DestinationPath, req.SourcePath)
+	defer func() {
+		s.c.traceCopyTableDone(ctx, s, req.DestinationPath, req.SourcePath, err)
+	}()
 	return s.c.Driver.Call(ctx, internal.Wrap(Ydb_Table_V1.CopyTable, &req, nil))
 }
 
+// CopyTables creates consistent copies of the tables given by
+// WithCopyTablesItem() options. That is, copies are made from the same
+// snapshot of the source tables, which makes it suitable for blue/green
+// swaps of related tables.
+func (s *Session) CopyTables(ctx context.Context, opts ...CopyTablesOption) (err error) {
+	if err = s.c.checkReadOnly("CopyTables"); err != nil {
+		return err
+	}
+	req := Ydb_Table.CopyTablesRequest{
+		SessionId: s.ID,
+	}
+	for _, opt := range opts {
+		opt((*copyTablesDesc)(&req))
+	}
+	if len(req.Tables) == 0 {
+		return errNoCopyTablesItems
+	}
+	items := make([]CopyTablesItem, len(req.Tables))
+	for i, x := range req.Tables {
+		x.SourcePath = s.c.Path(x.SourcePath)
+		x.DestinationPath = s.c.Path(x.DestinationPath)
+		items[i] = CopyTablesItem{
+			Destination: x.DestinationPath,
+			Source:      x.SourcePath,
+			OmitIndexes: x.OmitIndexes,
+		}
+	}
+	s.c.traceCopyTablesStart(ctx, s, items)
+	defer func() {
+		s.c.traceCopyTablesDone(ctx, s, items, err)
+	}()
+	return s.c.Driver.Call(ctx, internal.Wrap(Ydb_Table_V1.CopyTables, &req, nil))
+}
+
 // DataQueryExplanation is a result of ExplainDataQuery call.
 type DataQueryExplanation struct {
 	AST  string
@@ -250,13 +394,20 @@ func (s *Session) Explain(ctx context.Context, query string) (exp DataQueryExpla
 	}, nil
 }
 
-// Statement is a prepared statement. Like a single Session, it is not safe for
-// concurrent use by multiple goroutines.
+// Statement is a prepared statement. The same Statement is returned by
+// concurrent Prepare() calls of the same query within session, thus it is
+// safe for concurrent use by multiple goroutines.
 type Statement struct {
 	session *Session
-	query   *DataQuery
 	qhash   queryHash
-	params  map[string]*Ydb.Type
+
+	// text is the text of query. It is used to re-prepare the statement
+	// after schema change.
+	text string
+
+	mu     sync.RWMutex
+	query  *DataQuery
+	params map[string]*Ydb.Type
 }
 
 // Execute executes prepared data query.
@@ -267,9 +418,11 @@ func (s *Statement) Execute(
 ) (
 	txr *Transaction, r *Result, err error,
 ) {
-	s.session.c.traceExecuteDataQueryStart(ctx, s.session, tx, s.query, params)
+	q, _ := s.prepared()
+	s.session.c.traceExecuteDataQueryStart(ctx, s.session, tx, q, params)
 	defer func() {
-		s.session.c.traceExecuteDataQueryDone(ctx, s.session, tx, s.query, params, true, txr, r, err)
+		q, _ := s.prepared()
+		s.session.c.traceExecuteDataQueryDone(ctx, s.session, tx, q, params, true, txr, r, err)
 	}()
 	return s.execute(ctx, tx, params, opts...)
 }
@@ -282,9 +435,20 @@ func (s *Statement) execute(
 ) (
 	txr *Transaction, r *Result, err error,
 ) {
-	_, res, err := s.session.executeDataQuery(ctx, tx, s.query, params, opts...)
-	if ydb.IsOpError(err, ydb.StatusNotFound) {
-		s.session.qcache.Remove(s.qhash)
+	q, types := s.prepared()
+	if err = checkNullParams(types, params.params()); err != nil {
+		return nil, nil, err
+	}
+	_, res, err := s.session.executeDataQuery(ctx, tx, q, params, opts...)
+	// NotFound means that the prepared query is not known by the server
+	// anymore: it was evicted from the server side cache or the session was
+	// moved to another node. In both cases, as well as in case of scheme
+	// change, query must be prepared again.
+	if ydb.IsOpError(err, ydb.StatusNotFound) || isSchemeMismatch(err) {
+		s.session.removeQueryFromCache(s.qhash)
+		if q, e := s.reprepare(ctx); e == nil {
+			_, res, err = s.session.executeDataQuery(ctx, tx, q, params, opts...)
+		}
 	}
 	if err != nil {
 		return nil, nil, err
@@ -292,11 +456,48 @@ func (s *Statement) execute(
 	return s.session.executeQueryResult(res)
 }
 
+// prepared returns prepared query and its parameters types.
+func (s *Statement) prepared() (*DataQuery, map[string]*Ydb.Type) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.query, s.params
+}
+
+// reprepare prepares statement's query again and puts the statement back to
+// the session's cache. It returns newly prepared query.
+func (s *Statement) reprepare(ctx context.Context) (*DataQuery, error) {
+	q, params, err := s.session.prepare(ctx, s.text)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.query = q
+	s.params = params
+	s.mu.Unlock()
+
+	s.session.addQueryToCache(s.qhash, s)
+	return q, nil
+}
+
 func (s *Statement) NumInput() int {
-	return len(s.params)
+	_, params := s.prepared()
+	return len(params)
+}
+
+// prepareCall is an in-flight preparation of the query within session.
+type prepareCall struct {
+	done chan struct{}
+	stmt *Statement
+	err  error
 }
 
 // Prepare prepares data query within session s.
+//
+// Prepared statements are cached by the query text within session, such that
+// repeated Prepare() calls of the same query do not make requests to the
+// server. Concurrent Prepare() calls of the same query share the single
+// request to the server. Note that prepared queries are bound to the session
+// on the server side, so the cache is not shared across sessions.
 func (s *Session) Prepare(
 	ctx context.Context, query string,
 ) (
@@ -311,13 +512,70 @@ func (s *Session) Prepare(
 		s.c.tracePrepareDataQueryDone(ctx, s, query, q, cached, err)
 	}()
 
+	s.qmu.Lock()
 	cacheKey := s.qhash.hash(query)
-	stmt, cached = s.getQueryFromCache(cacheKey)
-	if cached {
-		q = stmt.query
+	if v, ok := s.qcache.Get(cacheKey); ok {
+		s.qmu.Unlock()
+		stmt, cached = v.(*Statement), true
+		q, _ = stmt.prepared()
 		return stmt, nil
 	}
+	if call, ok := s.preparing[cacheKey]; ok {
+		s.qmu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		stmt, cached = call.stmt, true
+		q, _ = stmt.prepared()
+		return stmt, nil
+	}
+	call := &prepareCall{
+		done: make(chan struct{}),
+	}
+	if s.preparing == nil {
+		s.preparing = make(map[queryHash]*prepareCall)
+	}
+	s.preparing[cacheKey] = call
+	s.qmu.Unlock()
+
+	q, params, err := s.prepare(ctx, query)
+	if err == nil {
+		stmt = &Statement{
+			session: s,
+			query:   q,
+			qhash:   cacheKey,
+			params:  params,
+			text:    query,
+		}
+	}
+	s.qmu.Lock()
+	if err == nil {
+		s.qcache.Add(cacheKey, stmt)
+	}
+	delete(s.preparing, cacheKey)
+	s.qmu.Unlock()
+
+	call.stmt, call.err = stmt, err
+	close(call.done)
+
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
 
+// prepare prepares data query without any caching and tracing.
+func (s *Session) prepare(ctx context.Context, query string) (
+	q *DataQuery, params map[string]*Ydb.Type, err error,
+) {
+	if err = s.c.checkReadOnlyQuery("PrepareDataQuery", query); err != nil {
+		return nil, nil, err
+	}
 	var res Ydb_Table.PrepareQueryResult
 	req := Ydb_Table.PrepareDataQueryRequest{
 		SessionId: s.ID,
@@ -325,23 +583,22 @@ func (s *Session) Prepare(
 	}
 	err = s.c.Driver.Call(ctx, internal.Wrap(Ydb_Table_V1.PrepareDataQuery, &req, &res))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
 	q = new(DataQuery)
 	q.initPrepared(res.QueryId)
-	stmt = &Statement{
-		session: s,
-		query:   q,
-		qhash:   cacheKey,
-		params:  res.ParametersTypes,
-	}
-	s.addQueryToCache(cacheKey, stmt)
+	return q, res.ParametersTypes, nil
+}
 
-	return stmt, nil
+func (s *Session) hashQuery(query string) queryHash {
+	s.qmu.Lock()
+	defer s.qmu.Unlock()
+	return s.qhash.hash(query)
 }
 
 func (s *Session) getQueryFromCache(key queryHash) (*Statement, bool) {
+	s.qmu.Lock()
+	defer s.qmu.Unlock()
 	v, cached := s.qcache.Get(key)
 	if cached {
 		return v.(*Statement), true
@@ -350,9 +607,17 @@ func (s *Session) getQueryFromCache(key queryHash) (*Statement, bool) {
 }
 
 func (s *Session) addQueryToCache(key queryHash, stmt *Statement) {
+	s.qmu.Lock()
+	defer s.qmu.Unlock()
 	s.qcache.Add(key, stmt)
 }
 
+func (s *Session) removeQueryFromCache(key queryHash) {
+	s.qmu.Lock()
+	defer s.qmu.Unlock()
+	s.qcache.Remove(key)
+}
+
 // Execute executes given data query represented by text.
 func (s *Session) Execute(
 	ctx context.Context, tx *TransactionControl,
@@ -370,11 +635,12 @@ func (s *Session) Execute(
 		s.c.traceExecuteDataQueryDone(ctx, s, tx, q, params, cached, txr, r, err)
 	}()
 
-	cacheKey := s.qhash.hash(query)
+	cacheKey := s.hashQuery(query)
 	stmt, cached := s.getQueryFromCache(cacheKey)
 	if cached {
 		// Supplement q with ID for tracing.
-		q.initPreparedText(query, stmt.query.ID())
+		prepared, _ := stmt.prepared()
+		q.initPreparedText(query, prepared.ID())
 		return stmt.execute(ctx, tx, params, opts...)
 	}
 	req, res, err := s.executeDataQuery(ctx, tx, q, params, opts...)
@@ -385,8 +651,8 @@ func (s *Session) Execute(
 		queryID := res.QueryMeta.Id
 		// Supplement q with ID for tracing.
 		q.initPreparedText(query, queryID)
-		// Create new DataQuery instead of q above to not store the whole query
-		// string within statement.
+		// Create new DataQuery instead of q above to execute the statement by
+		// its ID only. The query text is kept to prepare statement again.
 		subq := new(DataQuery)
 		subq.initPrepared(queryID)
 		stmt = &Statement{
@@ -394,6 +660,7 @@ func (s *Session) Execute(
 			query:   subq,
 			qhash:   cacheKey,
 			params:  res.QueryMeta.ParametersTypes,
+			text:    query,
 		}
 		s.addQueryToCache(cacheKey, stmt)
 	}
@@ -401,6 +668,30 @@ func (s *Session) Execute(
 	return s.executeQueryResult(res)
 }
 
+// issueCodeSchemeMismatch is a code of issue reported when prepared query was
+// compiled for a different schema version.
+const issueCodeSchemeMismatch = 2028
+
+// isSchemeMismatch reports whether err means that prepared query must be
+// compiled again due to the schema change. Note that other scheme errors,
+// such as unknown table or column, are not fixed by compiling query again.
+func isSchemeMismatch(err error) bool {
+	e, ok := err.(interface {
+		Issues() ydb.IssueIterator
+	})
+	return ok && hasIssueCode(e.Issues(), issueCodeSchemeMismatch)
+}
+
+func hasIssueCode(it ydb.IssueIterator, code uint32) bool {
+	for i := 0; i < it.Len(); i++ {
+		issue, nested := it.Get(i)
+		if issue.Code == code || hasIssueCode(nested, code) {
+			return true
+		}
+	}
+	return false
+}
+
 func keepInCache(req *Ydb_Table.ExecuteDataQueryRequest) bool {
 	p := req.QueryCachePolicy
 	return p != nil && p.KeepInCache
@@ -440,6 +731,19 @@ func (s *Session) executeDataQuery(
 	for _, opt := range opts {
 		opt((*executeDataQueryDesc)(req))
 	}
+	if err = s.c.checkReadOnlyQuery("ExecuteDataQuery", query.query.GetYqlText()); err != nil {
+		return
+	}
+	if err = checkRequestSize(req, s.c.MaxRequestSize); err != nil {
+		return
+	}
+	if l := s.c.SlowQueryLog; l != nil {
+		var done func(QueryStats, error)
+		ctx, done = l.observe(ctx, s, query)
+		defer func() {
+			done(QueryStats{stats: res.QueryStats}, err)
+		}()
+	}
 	err = s.c.Driver.Call(ctx, internal.Wrap(Ydb_Table_V1.ExecuteDataQuery, req, res))
 	return
 }
@@ -448,6 +752,16 @@ func (s *Session) executeDataQuery(
 func (s *Session) ExecuteSchemeQuery(
 	ctx context.Context, query string,
 	opts ...ExecuteSchemeQueryOption,
+) error {
+	if err := s.c.checkReadOnly("ExecuteSchemeQuery"); err != nil {
+		return err
+	}
+	return s.executeSchemeQuery(ctx, query, opts...)
+}
+
+func (s *Session) executeSchemeQuery(
+	ctx context.Context, query string,
+	opts ...ExecuteSchemeQueryOption,
 ) error {
 	req := Ydb_Table.ExecuteSchemeQueryRequest{
 		SessionId: s.ID,
@@ -556,6 +870,27 @@ func (s *Session) DescribeTableOptions(ctx context.Context) (desc TableOptionsDe
 
 // StreamReadTable reads table at given path with given options.
 //
+// Result sets are received from the server as the read progresses and are
+// iterated by NextStreamSet() calls:
+//
+//     res, err := s.StreamReadTable(ctx, "series",
+//         table.ReadColumns("series_id", "title"),
+//         table.ReadGreaterOrEqual(ydb.TupleValue(ydb.Uint64Value(1))),
+//         table.ReadOrdered(),
+//     )
+//     if err != nil {
+//         return err
+//     }
+//     defer res.Close()
+//     for res.NextStreamSet(ctx) {
+//         for res.NextRow() {
+//             // Scan row.
+//         }
+//     }
+//     return res.Err()
+//
+// Progress of the read may be observed via WithReadProgress().
+//
 // Note that given ctx controls the lifetime of the whole read, not only this
 // StreamReadTable() call; that is, the time until returned result is closed
 // via Close() call or fully drained by sequential NextStreamSet() calls.
@@ -563,7 +898,7 @@ func (s *Session) StreamReadTable(ctx context.Context, path string, opts ...Read
 	var resp Ydb_Table.ReadTableResponse
 	req := Ydb_Table.ReadTableRequest{
 		SessionId: s.ID,
-		Path:      path,
+		Path:      s.c.Path(path),
 	}
 	for _, opt := range opts {
 		opt((*readTableDesc)(&req))
@@ -575,6 +910,7 @@ func (s *Session) StreamReadTable(ctx context.Context, path string, opts ...Read
 	var (
 		ch = make(chan *Ydb.ResultSet, 1)
 		ce = new(error)
+		rp = newReadProgress(ctx)
 	)
 	err = s.c.Driver.StreamRead(ctx, internal.WrapStreamOperation(
 		Ydb_Table_V1.StreamReadTable, &req, &resp,
@@ -586,6 +922,10 @@ func (s *Session) StreamReadTable(ctx context.Context, path string, opts ...Read
 				close(ch)
 				return
 			}
+			rp.add(
+				len(resp.GetResult().GetResultSet().GetRows()),
+				proto.Size(&resp),
+			)
 			select {
 			case <-ctx.Done():
 			case ch <- resp.Result.ResultSet:
@@ -606,8 +946,11 @@ func (s *Session) StreamReadTable(ctx context.Context, path string, opts ...Read
 
 // BulkUpsert uploads given list of ydb struct values to the table.
 func (s *Session) BulkUpsert(ctx context.Context, table string, rows ydb.Value) error {
+	if err := s.c.checkReadOnly("BulkUpsert"); err != nil {
+		return err
+	}
 	req := Ydb_Table.BulkUpsertRequest{
-		Table: table,
+		Table: s.c.Path(table),
 		Rows:  internal.ValueToYDB(rows),
 	}
 	return s.c.Driver.Call(ctx, internal.Wrap(
@@ -929,6 +1272,62 @@ func (t *Client) traceRollbackTransactionDone(ctx context.Context, tx *Transacti
 		b(x)
 	}
 }
+func (t *Client) traceCopyTableStart(ctx context.Context, s *Session, dst, src string) {
+	x := CopyTableStartInfo{
+		Context:     ctx,
+		Session:     s,
+		Destination: dst,
+		Source:      src,
+	}
+	if a := t.Trace.CopyTableStart; a != nil {
+		a(x)
+	}
+	if b := ContextClientTrace(ctx).CopyTableStart; b != nil {
+		b(x)
+	}
+}
+func (t *Client) traceCopyTableDone(ctx context.Context, s *Session, dst, src string, err error) {
+	x := CopyTableDoneInfo{
+		Context:     ctx,
+		Session:     s,
+		Destination: dst,
+		Source:      src,
+		Error:       err,
+	}
+	if a := t.Trace.CopyTableDone; a != nil {
+		a(x)
+	}
+	if b := ContextClientTrace(ctx).CopyTableDone; b != nil {
+		b(x)
+	}
+}
+func (t *Client) traceCopyTablesStart(ctx context.Context, s *Session, items []CopyTablesItem) {
+	x := CopyTablesStartInfo{
+		Context: ctx,
+		Session: s,
+		Items:   items,
+	}
+	if a := t.Trace.CopyTablesStart; a != nil {
+		a(x)
+	}
+	if b := ContextClientTrace(ctx).CopyTablesStart; b != nil {
+		b(x)
+	}
+}
+func (t *Client) traceCopyTablesDone(ctx context.Context, s *Session, items []CopyTablesItem, err error) {
+	x := CopyTablesDoneInfo{
+		Context: ctx,
+		Session: s,
+		Items:   items,
+		Error:   err,
+	}
+	if a := t.Trace.CopyTablesDone; a != nil {
+		a(x)
+	}
+	if b := ContextClientTrace(ctx).CopyTablesDone; b != nil {
+		b(x)
+	}
+}
 
 type DataQuery struct {
 	query    Ydb_Table.Query
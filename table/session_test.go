@@ -3,10 +3,15 @@ package table
 import (
 	"context"
 	"errors"
+	"io"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/yandex-cloud/ydb-go-sdk"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Scheme"
@@ -15,7 +20,7 @@ import (
 	"github.com/yandex-cloud/ydb-go-sdk/testutil"
 )
 
-func TestStatementRemoveNotFound(t *testing.T) {
+func TestStatementReprepareNotFound(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -69,9 +74,321 @@ func TestStatementRemoveNotFound(t *testing.T) {
 	prepare()
 	assertNotPrepared() // Used from cache.
 
-	_, _, _ = stmt1.Execute(ctx, TxControl(), nil)
-	prepare()
+	// Statement must be prepared again once and put back to the cache.
+	_, _, err = stmt1.Execute(ctx, TxControl(), nil)
+	if !ydb.IsOpError(err, ydb.StatusNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	assertPrepared()
+	prepare()
+	assertNotPrepared()
+}
+
+func TestSessionPrepareSingleflight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		started = make(chan struct{}, 1)
+		release = make(chan struct{})
+		calls   int32
+	)
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TablePrepareDataQuery: func(req, res interface{}) error {
+				atomic.AddInt32(&calls, 1)
+				started <- struct{}{}
+				<-release
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		yql = "SOME YQL TEXT"
+		n   = 3
+	)
+	var (
+		wg    sync.WaitGroup
+		stmts = make([]*Statement, n)
+		errs  = make([]error, n)
+	)
+	prepare := func(i int) {
+		defer wg.Done()
+		stmts[i], errs[i] = s.Prepare(ctx, yql)
+	}
+	wg.Add(n)
+	go prepare(0)
+	<-started
+	for i := 1; i < n; i++ {
+		go prepare(i)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("unexpected number of prepare calls: %d", n)
+	}
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if stmts[i] != stmts[0] {
+			t.Errorf("unexpected statement #%d", i)
+		}
+	}
+}
+
+func TestStatementConcurrentReprepare(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var prepares int32
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TablePrepareDataQuery: func(req, res interface{}) error {
+				atomic.AddInt32(&prepares, 1)
+				return nil
+			},
+			testutil.TableExecuteDataQuery: func(req, res interface{}) error {
+				return &ydb.OpError{
+					Reason: ydb.StatusNotFound,
+				}
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 4
+	var (
+		wg    sync.WaitGroup
+		stmts = make([]*Statement, n)
+	)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			stmt, err := s.Prepare(ctx, "SOME YQL TEXT")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			stmts[i] = stmt
+			// Statement shared by concurrent Prepare() callers must be
+			// re-prepared without data races.
+			_, _, _ = stmt.Execute(ctx, TxControl(), nil)
+			_ = stmt.NumInput()
+		}(i)
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&prepares); n < 2 {
+		t.Fatalf("unexpected number of prepares: %d", n)
+	}
+}
+
+func TestStatementReprepareSchemeMismatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		prepares int
+		executes int
+		failed   bool
+	)
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TablePrepareDataQuery: func(req, res interface{}) error {
+				prepares++
+				return nil
+			},
+			testutil.TableExecuteDataQuery: func(req, res interface{}) error {
+				executes++
+				if !failed {
+					failed = true
+					return schemeMismatchError()
+				}
+				r := res.(*Ydb_Table.ExecuteQueryResult)
+				r.TxMeta = &Ydb_Table.TransactionMeta{}
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := s.Prepare(ctx, "SOME YQL TEXT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := stmt.Execute(ctx, TxControl(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prepares != 2 || executes != 2 {
+		t.Fatalf(
+			"unexpected number of calls: %d prepares and %d executes; want 2 and 2",
+			prepares, executes,
+		)
+	}
+
+	// Statement must be put back to the cache after re-prepare.
+	if _, err := s.Prepare(ctx, "SOME YQL TEXT"); err != nil {
+		t.Fatal(err)
+	}
+	if prepares != 2 {
+		t.Fatalf("unexpected prepare")
+	}
+
+	// Error must be surfaced if re-prepared statement fails again.
+	executes = 0
+	b.Handler[testutil.TableExecuteDataQuery] = func(req, res interface{}) error {
+		executes++
+		return schemeMismatchError()
+	}
+	_, _, err = stmt.Execute(ctx, TxControl(), nil)
+	if !isSchemeMismatch(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executes != 2 {
+		t.Fatalf("unexpected number of executes: %d; want 2", executes)
+	}
+}
+
+func TestStatementSchemeErrorNoReprepare(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		prepares int
+		executes int
+	)
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TablePrepareDataQuery: func(req, res interface{}) error {
+				prepares++
+				return nil
+			},
+			testutil.TableExecuteDataQuery: func(req, res interface{}) error {
+				executes++
+				// Unknown table, for example.
+				return &ydb.OpError{
+					Reason: ydb.StatusSchemeError,
+				}
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := s.Prepare(ctx, "SOME YQL TEXT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = stmt.Execute(ctx, TxControl(), nil)
+	if !ydb.IsOpError(err, ydb.StatusSchemeError) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prepares != 1 || executes != 1 {
+		t.Fatalf(
+			"unexpected number of calls: %d prepares and %d executes; want 1 and 1",
+			prepares, executes,
+		)
+	}
+}
+
+func TestSessionExecuteReprepareSchemeMismatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const yql = "SOME YQL TEXT"
+	var (
+		prepares int
+		executes int
+		fail     bool
+	)
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TablePrepareDataQuery: func(req, res interface{}) error {
+				prepares++
+				if act := req.(*Ydb_Table.PrepareDataQueryRequest).YqlText; act != yql {
+					t.Errorf("unexpected query: %q; want %q", act, yql)
+				}
+				return nil
+			},
+			testutil.TableExecuteDataQuery: func(req, res interface{}) error {
+				executes++
+				if fail {
+					fail = false
+					return schemeMismatchError()
+				}
+				r := res.(*Ydb_Table.ExecuteQueryResult)
+				r.TxMeta = &Ydb_Table.TransactionMeta{}
+				r.QueryMeta = &Ydb_Table.QueryMeta{
+					Id: "query",
+				}
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt := WithQueryCachePolicy(WithQueryCachePolicyKeepInCache())
+	if _, _, err := s.Execute(ctx, TxControl(), yql, nil, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	// Statement cached implicitly by the first call must be prepared again
+	// and executed once.
+	fail = true
+	executes = 0
+	if _, _, err := s.Execute(ctx, TxControl(), yql, nil, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prepares != 1 || executes != 2 {
+		t.Fatalf(
+			"unexpected number of calls: %d prepares and %d executes; want 1 and 2",
+			prepares, executes,
+		)
+	}
+}
+
+// schemeMismatchError returns an operation error, which reports that
+// prepared query was compiled for a different schema version.
+func schemeMismatchError() error {
+	return issuesError{
+		OpError: &ydb.OpError{
+			Reason: ydb.StatusSchemeError,
+		},
+		issues: ydb.IssueIterator{{
+			Message:   proto.String("query compiled for different schema"),
+			IssueCode: proto.Uint32(issueCodeSchemeMismatch),
+			Severity:  proto.Uint32(1),
+		}},
+	}
+}
+
+// issuesError is an operation error with issues, which can not be set to
+// ydb.OpError outside of the ydb package.
+type issuesError struct {
+	*ydb.OpError
+	issues ydb.IssueIterator
+}
+
+func (e issuesError) Issues() ydb.IssueIterator {
+	return e.issues
 }
 
 func TestSessionKeepAlive(t *testing.T) {
@@ -133,12 +450,15 @@ func TestSessionDescribeTable(t *testing.T) {
 
 	var (
 		result Ydb_Table.DescribeTableResult
+		bounds bool
 		e      error
 	)
 	b := StubBuilder{
 		T: t,
 		Handler: methodHandlers{
 			testutil.TableDescribeTable: func(req, res interface{}) error {
+				q, _ := req.(*Ydb_Table.DescribeTableRequest)
+				bounds = q.IncludeShardKeyBounds
 				r, _ := res.(*Ydb_Table.DescribeTableResult)
 				*r = result
 
@@ -169,6 +489,16 @@ func TestSessionDescribeTable(t *testing.T) {
 					Type: ydb.Void(),
 				},
 			},
+			ColumnFamilies: map[string]string{
+				"testColumn": "testFamily",
+			},
+			Indexes: []IndexDescription{
+				{
+					Name:         "testIndex",
+					IndexColumns: []string{"testColumn"},
+					Type:         GlobalIndex(),
+				},
+			},
 			KeyRanges: []KeyRange{
 				{
 					From: nil,
@@ -192,23 +522,282 @@ func TestSessionDescribeTable(t *testing.T) {
 				{
 					Name:   expect.Columns[0].Name,
 					Type:   internal.TypeToYDB(expect.Columns[0].Type),
-					Family: "",
+					Family: expect.ColumnFamilies["testColumn"],
 				},
 			},
 			PrimaryKey: expect.PrimaryKey,
 			ShardKeyBounds: []*Ydb.TypedValue{
 				internal.ValueToYDB(expect.KeyRanges[0].To),
 			},
-			Indexes:    nil,
+			Indexes: []*Ydb_Table.TableIndex{
+				{
+					Name:         expect.Indexes[0].Name,
+					IndexColumns: expect.Indexes[0].IndexColumns,
+					Type: &Ydb_Table.TableIndex_GlobalIndex{
+						GlobalIndex: new(Ydb_Table.GlobalIndex),
+					},
+				},
+			},
 			TableStats: nil,
 		}
 
-		d, err := s.DescribeTable(ctx, "")
+		d, err := s.DescribeTable(ctx, "", WithShardKeyBounds())
 		if err != nil {
 			t.Fatal(err)
 		}
+		if !bounds {
+			t.Fatalf("shard key bounds are not requested")
+		}
 		if !reflect.DeepEqual(d, expect) {
 			t.Fatalf("Result %+v differ from, expectd %+v", d, expect)
 		}
 	}
 }
+
+func TestSessionCreateTableProfile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var req *Ydb_Table.CreateTableRequest
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TableCreateTable: func(r, _ interface{}) error {
+				req = r.(*Ydb_Table.CreateTableRequest)
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.CreateTable(ctx, "series",
+		WithColumn("series_id", ydb.Optional(ydb.TypeUint64)),
+		WithFamilyColumn("payload", ydb.Optional(ydb.TypeString), "cold"),
+		WithPrimaryKeyColumn("series_id"),
+		WithProfile(
+			WithStoragePolicy(
+				WithStoragePolicyData("ssd"),
+				WithStoragePolicyKeepInMemory(ydb.FeatureEnabled),
+				WithStoragePolicyColumnFamily("cold",
+					WithColumnFamilyPolicyData("hdd"),
+				),
+			),
+			WithPartitioningPolicy(
+				WithPartitioningPolicyMode(PartitioningAutoSplitMerge),
+				WithPartitioningPolicyUniformPartitions(4),
+			),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := req.Path, s.c.Path("series"); act != exp {
+		t.Errorf("unexpected path: %q; want %q", act, exp)
+	}
+	if n := len(req.Columns); n != 2 || req.Columns[1].Family != "cold" {
+		t.Errorf("unexpected columns: %v", req.Columns)
+	}
+	storage := req.Profile.GetStoragePolicy()
+	if storage.GetData().GetStorageKind() != "ssd" ||
+		storage.GetKeepInMemory() != Ydb.FeatureFlag_ENABLED {
+		t.Errorf("unexpected storage policy: %v", storage)
+	}
+	if fs := storage.GetColumnFamilies(); len(fs) != 1 ||
+		fs[0].Name != "cold" ||
+		fs[0].GetData().GetStorageKind() != "hdd" {
+		t.Errorf("unexpected column families: %v", fs)
+	}
+	partitioning := req.Profile.GetPartitioningPolicy()
+	if act, exp := partitioning.GetAutoPartitioning(), Ydb_Table.PartitioningPolicy_AUTO_SPLIT_MERGE; act != exp {
+		t.Errorf("unexpected auto partitioning: %v; want %v", act, exp)
+	}
+	if act := partitioning.GetUniformPartitions(); act != 4 {
+		t.Errorf("unexpected uniform partitions: %d; want 4", act)
+	}
+}
+
+func TestSessionIndexes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var queries []string
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TableExecuteSchemeQuery: func(req, res interface{}) error {
+				r := req.(*Ydb_Table.ExecuteSchemeQueryRequest)
+				queries = append(queries, r.YqlText)
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "`" + s.c.Path("series") + "`"
+
+	if err := s.AddIndex(ctx, "series", "title_index"); err != errNoIndexColumns {
+		t.Fatalf("unexpected error: %v; want %v", err, errNoIndexColumns)
+	}
+	err = s.AddIndex(ctx, "series", "title_index",
+		WithIndexColumns("title", "release_date"),
+		WithIndexType(GlobalIndex()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DropIndex(ctx, "series", "title_index"); err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{
+		"ALTER TABLE " + path + " ADD INDEX `title_index` GLOBAL ON (`title`, `release_date`);",
+		"ALTER TABLE " + path + " DROP INDEX `title_index`;",
+	}
+	if !reflect.DeepEqual(queries, exp) {
+		t.Fatalf("unexpected queries:\n%q\nwant:\n%q", queries, exp)
+	}
+}
+
+func TestSessionCopyTables(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var req *Ydb_Table.CopyTablesRequest
+	b := StubBuilder{
+		T: t,
+		Handler: methodHandlers{
+			testutil.TableCopyTables: func(r, _ interface{}) error {
+				req = r.(*Ydb_Table.CopyTablesRequest)
+				return nil
+			},
+		},
+	}
+	s, err := b.CreateSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CopyTables(ctx); err != errNoCopyTablesItems {
+		t.Fatalf("unexpected error: %v; want %v", err, errNoCopyTablesItems)
+	}
+
+	var (
+		start []CopyTablesItem
+		done  []CopyTablesItem
+	)
+	ctx = WithClientTrace(ctx, ClientTrace{
+		CopyTablesStart: func(info CopyTablesStartInfo) {
+			start = info.Items
+		},
+		CopyTablesDone: func(info CopyTablesDoneInfo) {
+			done = info.Items
+		},
+	})
+	err = s.CopyTables(ctx,
+		WithCopyTablesItem("series_new", "series", false),
+		WithCopyTablesItem("episodes_new", "episodes", true),
+		WithCopyTablesOperationTimeout(time.Minute),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []CopyTablesItem{
+		{
+			Destination: s.c.Path("series_new"),
+			Source:      s.c.Path("series"),
+		},
+		{
+			Destination: s.c.Path("episodes_new"),
+			Source:      s.c.Path("episodes"),
+			OmitIndexes: true,
+		},
+	}
+	if !reflect.DeepEqual(start, exp) || !reflect.DeepEqual(done, exp) {
+		t.Fatalf("unexpected traced items: %+v, %+v; want %+v", start, done, exp)
+	}
+	if n := len(req.Tables); n != 2 {
+		t.Fatalf("unexpected number of tables: %d", n)
+	}
+	if act := req.Tables[1].DestinationPath; act != exp[1].Destination {
+		t.Errorf("unexpected destination path: %q; want %q", act, exp[1].Destination)
+	}
+	if d := req.OperationParams.GetOperationTimeout(); d.GetSeconds() != 60 {
+		t.Errorf("unexpected operation timeout: %v", d)
+	}
+}
+
+func TestSessionStreamReadTable(t *testing.T) {
+	var req *Ydb_Table.ReadTableRequest
+	s := &Session{
+		ID: "session",
+		c: Client{
+			Driver: &testutil.Driver{
+				OnStreamRead: func(_ context.Context, m testutil.MethodCode, r, res interface{}, process func(error)) error {
+					req = r.(*Ydb_Table.ReadTableRequest)
+					go func() {
+						resp := res.(*Ydb_Table.ReadTableResponse)
+						for i := 0; i < 2; i++ {
+							resp.Result = &Ydb_Table.ReadTableResult{
+								ResultSet: NewResultSet(
+									WithColumns(Column{"series_id", ydb.TypeUint64}),
+									WithValues(ydb.Uint64Value(uint64(i))),
+								),
+							}
+							process(nil)
+						}
+						process(io.EOF)
+					}()
+					return nil
+				},
+			},
+		},
+	}
+	var progress []ReadProgress
+	ctx := WithReadProgress(context.Background(), func(p ReadProgress) {
+		progress = append(progress, p)
+	})
+	res, err := s.StreamReadTable(ctx, "series",
+		ReadColumns("series_id", "title"),
+		ReadGreaterOrEqual(ydb.TupleValue(ydb.Uint64Value(1))),
+		ReadOrdered(),
+		ReadRowLimit(10),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Close()
+
+	if act, exp := req.Columns, []string{"series_id", "title"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected columns: %v; want %v", act, exp)
+	}
+	if !req.Ordered || req.RowLimit != 10 || req.KeyRange.GetGreaterOrEqual() == nil {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	var ids []uint64
+	for res.NextStreamSet(ctx) {
+		for res.NextRow() {
+			var id uint64
+			if err := res.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if err := res.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []uint64{0, 1}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("unexpected ids: %v; want %v", ids, exp)
+	}
+	if n := len(progress); n != 2 {
+		t.Fatalf("unexpected number of progress calls: %d", n)
+	}
+	for i, p := range progress {
+		if p.ResultSet != i || p.SetRows != 1 || p.Rows != uint64(i+1) || p.Bytes == 0 {
+			t.Errorf("unexpected progress #%d: %+v", i, p)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+)
+
+// maxRequestSizeParams is a maximum number of parameters listed in the
+// RequestSizeError's message.
+const maxRequestSizeParams = 3
+
+// RequestSizeError is returned by data query execution methods when the size
+// of serialized request exceeds the Client's MaxRequestSize.
+type RequestSizeError struct {
+	// Size is the size of the serialized request in bytes.
+	Size int
+
+	// Limit is the Client's MaxRequestSize value.
+	Limit int
+
+	// QuerySize is the size of the query text or id in bytes.
+	QuerySize int
+
+	// Params contains sizes of the query parameters ordered by size
+	// descending.
+	Params []ParamSize
+}
+
+// ParamSize describes the size of a single serialized query parameter.
+type ParamSize struct {
+	Name string
+	Size int
+}
+
+func (e *RequestSizeError) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf,
+		"ydb: table: request size of %d bytes exceeds limit of %d bytes (query: %d bytes",
+		e.Size, e.Limit, e.QuerySize,
+	)
+	for i, p := range e.Params {
+		if i == maxRequestSizeParams {
+			fmt.Fprintf(&buf, ", %d more", len(e.Params)-i)
+			break
+		}
+		if i == 0 {
+			buf.WriteString("; largest parameters: ")
+		} else {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(p.Name)
+		buf.WriteString(": ")
+		buf.WriteString(strconv.Itoa(p.Size))
+		buf.WriteString(" bytes")
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// checkRequestSize returns *RequestSizeError if size of req exceeds limit.
+// If limit is less than or equal to zero, no check is made.
+func checkRequestSize(req *Ydb_Table.ExecuteDataQueryRequest, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	size := proto.Size(req)
+	if size <= limit {
+		return nil
+	}
+	params := make([]ParamSize, 0, len(req.Parameters))
+	for name, v := range req.Parameters {
+		params = append(params, ParamSize{
+			Name: name,
+			Size: proto.Size(v),
+		})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].Size == params[j].Size {
+			return params[i].Name < params[j].Name
+		}
+		return params[i].Size > params[j].Size
+	})
+	return &RequestSizeError{
+		Size:      size,
+		Limit:     limit,
+		QuerySize: proto.Size(req.Query),
+		Params:    params,
+	}
+}
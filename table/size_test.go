@@ -0,0 +1,66 @@
+package table
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestSessionMaxRequestSize(t *testing.T) {
+	var calls int
+	s := &Session{
+		ID: "session",
+		c: Client{
+			MaxRequestSize: 1024,
+			Driver: &testutil.Driver{
+				OnCall: func(_ context.Context, _ testutil.MethodCode, _, res interface{}) error {
+					calls++
+					res.(*Ydb_Table.ExecuteQueryResult).TxMeta = new(Ydb_Table.TransactionMeta)
+					return nil
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	_, _, err := s.Execute(ctx, TxControl(), "SELECT 1", NewQueryParameters(
+		ValueParam("$small", ydb.Int32Value(1)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("unexpected calls: %d", calls)
+	}
+
+	_, _, err = s.Execute(ctx, TxControl(), "SELECT 2", NewQueryParameters(
+		ValueParam("$small", ydb.Int32Value(1)),
+		ValueParam("$large", ydb.StringValue(bytes.Repeat([]byte{'x'}, 2048))),
+		ValueParam("$medium", ydb.UTF8Value(strings.Repeat("y", 100))),
+	))
+	e, ok := err.(*RequestSizeError)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("request exceeding limit was sent")
+	}
+	if e.Limit != 1024 || e.Size <= e.Limit {
+		t.Errorf("unexpected size error: %+v", e)
+	}
+	var names []string
+	for _, p := range e.Params {
+		names = append(names, p.Name)
+	}
+	if act, exp := strings.Join(names, ","), "$large,$medium,$small"; act != exp {
+		t.Errorf("unexpected params order: %s; want %s", act, exp)
+	}
+	if msg := e.Error(); !strings.Contains(msg, "largest parameters: $large: ") {
+		t.Errorf("unexpected error message: %s", msg)
+	}
+}
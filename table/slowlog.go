@@ -0,0 +1,149 @@
+package table
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+const (
+	DefaultSlowQueryThreshold = time.Second
+)
+
+// SlowQuery describes data query execution which took longer than the
+// SlowQueryLog threshold.
+type SlowQuery struct {
+	Context   context.Context
+	SessionID string
+
+	// Query is the query text passed through the SlowQueryLog's Redact
+	// function. It is the prepared query id when query text is unknown.
+	Query string
+
+	// Hash is a stable hash of the original query text (or id) suitable for
+	// grouping and labeling.
+	Hash string
+
+	// Fingerprint is a hash of the normalized query text which is the same
+	// for queries differing only in literal values. See QueryFingerprint().
+	Fingerprint string
+
+	// Endpoint is the "host:port" address of the endpoint which executed the
+	// query.
+	Endpoint string
+
+	// Prepared reports whether prepared query was executed.
+	Prepared bool
+
+	Duration time.Duration
+
+	// Stats contains query execution statistics. Note that statistics are
+	// collected only with WithCollectStatsModeBasic() option.
+	Stats QueryStats
+
+	Error error
+}
+
+// SlowQueryLog reports data queries which execution exceeds the threshold.
+// It is enabled by setting Client's SlowQueryLog field.
+type SlowQueryLog struct {
+	// Threshold is the minimum duration of the query execution to be
+	// reported.
+	// If Threshold is zero then the DefaultSlowQueryThreshold is used.
+	Threshold time.Duration
+
+	// SampleRate is the fraction of the slow queries to be reported, in the
+	// range (0, 1].
+	// If SampleRate is zero then every slow query is reported.
+	SampleRate float64
+
+	// Redact is an optional function which returns query text to be
+	// reported, such as the text with all literals stripped off.
+	Redact func(query string) string
+
+	// Report is called for each slow query sampled.
+	// Report must not be nil.
+	Report func(SlowQuery)
+
+	// Clock is a source of time used by the log.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (l *SlowQueryLog) threshold() time.Duration {
+	if l.Threshold > 0 {
+		return l.Threshold
+	}
+	return DefaultSlowQueryThreshold
+}
+
+func (l *SlowQueryLog) sample() bool {
+	r := l.SampleRate
+	if r <= 0 || r >= 1 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rand == nil {
+		l.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return l.rand.Float64() < r
+}
+
+// observe starts observation of the query execution. It returns context
+// which must be used for the query execution and function which must be
+// called when execution is done.
+func (l *SlowQueryLog) observe(ctx context.Context, s *Session, q *DataQuery) (
+	_ context.Context, done func(QueryStats, error),
+) {
+	var (
+		clock    = timeutil.ClockOrDefault(l.Clock)
+		start    = clock.Now()
+		endpoint string
+	)
+	ctx = ydb.WithDriverTrace(ctx, ydb.DriverTrace{
+		OperationDone: func(info ydb.OperationDoneInfo) {
+			endpoint = info.Address
+		},
+	})
+	return ctx, func(stats QueryStats, err error) {
+		d := clock.Now().Sub(start)
+		if d < l.threshold() || !l.sample() {
+			return
+		}
+		text := q.YQL()
+		if text == "" {
+			text = q.ID()
+		}
+		query := text
+		if f := l.Redact; f != nil {
+			query = f(text)
+		}
+		l.Report(SlowQuery{
+			Context:     ctx,
+			SessionID:   s.ID,
+			Query:       query,
+			Hash:        slowQueryHash(text),
+			Fingerprint: QueryFingerprint(text),
+			Endpoint:    endpoint,
+			Prepared:    q.ID() != "",
+			Duration:    d,
+			Stats:       stats,
+			Error:       err,
+		})
+	}
+}
+
+func slowQueryHash(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:8])
+}
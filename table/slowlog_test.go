@@ -0,0 +1,83 @@
+package table
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	var (
+		clock    = timetest.NewClock(time.Unix(0, 0))
+		duration time.Duration
+		reported []SlowQuery
+	)
+	s := &Session{
+		ID: "session",
+		c: Client{
+			Driver: &testutil.Driver{
+				OnCall: func(ctx context.Context, _ testutil.MethodCode, _, res interface{}) error {
+					res.(*Ydb_Table.ExecuteQueryResult).TxMeta = new(Ydb_Table.TransactionMeta)
+					clock.Shift(duration)
+					if f := ydb.ContextDriverTrace(ctx).OperationDone; f != nil {
+						f(ydb.OperationDoneInfo{
+							Context: ctx,
+							Address: "node:2135",
+						})
+					}
+					return nil
+				},
+			},
+			SlowQueryLog: &SlowQueryLog{
+				Threshold: time.Second,
+				Redact:    strings.ToLower,
+				Report: func(q SlowQuery) {
+					reported = append(reported, q)
+				},
+				Clock: clock,
+			},
+		},
+	}
+	ctx := context.Background()
+
+	duration = 500 * time.Millisecond
+	if _, _, err := s.Execute(ctx, TxControl(), "SELECT 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reported); n != 0 {
+		t.Fatalf("unexpected reported queries: %d", n)
+	}
+
+	duration = 2 * time.Second
+	if _, _, err := s.Execute(ctx, TxControl(), "SELECT 2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reported); n != 1 {
+		t.Fatalf("unexpected reported queries: %d; want 1", n)
+	}
+	q := reported[0]
+	if q.Query != "select 2" {
+		t.Errorf("unexpected query: %q", q.Query)
+	}
+	if q.Hash != slowQueryHash("SELECT 2") {
+		t.Errorf("unexpected query hash: %q", q.Hash)
+	}
+	if q.Fingerprint != QueryFingerprint("SELECT 1") {
+		t.Errorf("unexpected query fingerprint: %q", q.Fingerprint)
+	}
+	if q.Duration != duration {
+		t.Errorf("unexpected duration: %v; want %v", q.Duration, duration)
+	}
+	if q.Endpoint != "node:2135" {
+		t.Errorf("unexpected endpoint: %q", q.Endpoint)
+	}
+	if q.SessionID != "session" || q.Prepared {
+		t.Errorf("unexpected slow query: %+v", q)
+	}
+}
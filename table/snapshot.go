@@ -0,0 +1,101 @@
+package table
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+)
+
+// ErrSnapshotNoQuery is returned by QuerySnapshot unmarshaling methods to
+// indicate that snapshot does not contain query text.
+var ErrSnapshotNoQuery = errors.New("ydb: table: query snapshot has no query text")
+
+// QuerySnapshot is a self-contained snapshot of the data query text and its
+// typed parameters. It is intended to be attached to bug reports to
+// reproduce issues: snapshot may be saved by MarshalJSON() or
+// MarshalBinary() and then loaded and executed again.
+//
+// JSON format is the protobuf JSON mapping of the ExecuteDataQueryRequest
+// message with only query text and parameters set. Binary format is the
+// protobuf encoding of the same message.
+type QuerySnapshot struct {
+	Query  string
+	Params *QueryParameters
+}
+
+// Execute executes snapshot query with its parameters within given session.
+func (q QuerySnapshot) Execute(
+	ctx context.Context, s *Session, tx *TransactionControl,
+	opts ...ExecuteDataQueryOption,
+) (*Transaction, *Result, error) {
+	return s.Execute(ctx, tx, q.Query, q.Params, opts...)
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (q QuerySnapshot) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	m := jsonpb.Marshaler{
+		Indent: "  ",
+	}
+	if err := m.Marshal(&buf, q.request()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (q *QuerySnapshot) UnmarshalJSON(b []byte) error {
+	var req Ydb_Table.ExecuteDataQueryRequest
+	if err := jsonpb.Unmarshal(bytes.NewReader(b), &req); err != nil {
+		return err
+	}
+	return q.fromRequest(&req)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (q QuerySnapshot) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(q.request())
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (q *QuerySnapshot) UnmarshalBinary(b []byte) error {
+	var req Ydb_Table.ExecuteDataQueryRequest
+	if err := proto.Unmarshal(b, &req); err != nil {
+		return err
+	}
+	return q.fromRequest(&req)
+}
+
+func (q QuerySnapshot) request() *Ydb_Table.ExecuteDataQueryRequest {
+	return &Ydb_Table.ExecuteDataQueryRequest{
+		Query: &Ydb_Table.Query{
+			Query: &Ydb_Table.Query_YqlText{
+				YqlText: q.Query,
+			},
+		},
+		Parameters: q.Params.params(),
+	}
+}
+
+func (q *QuerySnapshot) fromRequest(req *Ydb_Table.ExecuteDataQueryRequest) error {
+	text := req.GetQuery().GetYqlText()
+	if text == "" {
+		return ErrSnapshotNoQuery
+	}
+	params := req.Parameters
+	if params == nil {
+		params = make(queryParams)
+	}
+	*q = QuerySnapshot{
+		Query: text,
+		Params: &QueryParameters{
+			m: params,
+		},
+	}
+	return nil
+}
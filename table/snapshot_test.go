@@ -0,0 +1,74 @@
+package table
+
+import (
+	"encoding/json"
+	"testing"
+
+	ydb "github.com/yandex-cloud/ydb-go-sdk"
+)
+
+func TestQuerySnapshot(t *testing.T) {
+	q := QuerySnapshot{
+		Query: "DECLARE $id AS Uint64; SELECT * FROM series WHERE id = $id;",
+		Params: NewQueryParameters(
+			ValueParam("$id", ydb.Uint64Value(42)),
+			ValueParam("$tags", ydb.ListValue(
+				ydb.UTF8Value("a"),
+				ydb.UTF8Value("b"),
+			)),
+			ValueParam("$title", ydb.OptionalValue(ydb.UTF8Value("foo"))),
+		),
+	}
+	for _, test := range []struct {
+		name      string
+		marshal   func(QuerySnapshot) ([]byte, error)
+		unmarshal func(*QuerySnapshot, []byte) error
+	}{
+		{
+			name: "json",
+			marshal: func(q QuerySnapshot) ([]byte, error) {
+				return json.Marshal(q)
+			},
+			unmarshal: func(q *QuerySnapshot, b []byte) error {
+				return json.Unmarshal(b, q)
+			},
+		},
+		{
+			name:      "binary",
+			marshal:   QuerySnapshot.MarshalBinary,
+			unmarshal: (*QuerySnapshot).UnmarshalBinary,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := test.marshal(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var act QuerySnapshot
+			if err := test.unmarshal(&act, b); err != nil {
+				t.Fatal(err)
+			}
+			if act.Query != q.Query {
+				t.Errorf("unexpected query: %q; want %q", act.Query, q.Query)
+			}
+			exp := make(map[string]string)
+			q.Params.Each(func(name string, v ydb.Value) {
+				exp[name] = NewQueryParameters(ValueParam(name, v)).String()
+			})
+			act.Params.Each(func(name string, v ydb.Value) {
+				if s := NewQueryParameters(ValueParam(name, v)).String(); s != exp[name] {
+					t.Errorf("unexpected param %s: %s; want %s", name, s, exp[name])
+				}
+				delete(exp, name)
+			})
+			if len(exp) != 0 {
+				t.Errorf("missing params: %v", exp)
+			}
+		})
+	}
+
+	var empty QuerySnapshot
+	if err := empty.UnmarshalBinary(nil); err != ErrSnapshotNoQuery {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -3,6 +3,9 @@ package table
 import (
 	"time"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_TableStats"
 )
 
@@ -84,3 +87,48 @@ func (s *OperationStats) init(x *Ydb_TableStats.OperationStats) {
 		Bytes: x.Bytes,
 	}
 }
+
+// TableStats contains table statistics returned by DescribeTable().
+type TableStats struct {
+	Partitions       uint64
+	RowsEstimate     uint64
+	StoreSize        uint64
+	CreationTime     time.Time
+	ModificationTime time.Time
+
+	// PartitionStats contains statistics of each table partition. It is empty
+	// unless WithPartitionStats() option is passed to DescribeTable().
+	PartitionStats []PartitionStats
+}
+
+// PartitionStats contains statistics of single table partition.
+type PartitionStats struct {
+	RowsEstimate uint64
+	StoreSize    uint64
+}
+
+func (s *TableStats) init(x *Ydb_Table.TableStats) {
+	*s = TableStats{
+		Partitions:       x.Partitions,
+		RowsEstimate:     x.RowsEstimate,
+		StoreSize:        x.StoreSize,
+		CreationTime:     timeFromTimestamp(x.CreationTime),
+		ModificationTime: timeFromTimestamp(x.ModificationTime),
+	}
+	if n := len(x.PartitionStats); n > 0 {
+		s.PartitionStats = make([]PartitionStats, n)
+		for i, p := range x.PartitionStats {
+			s.PartitionStats[i] = PartitionStats{
+				RowsEstimate: p.RowsEstimate,
+				StoreSize:    p.StoreSize,
+			}
+		}
+	}
+}
+
+func timeFromTimestamp(t *timestamp.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Unix(t.Seconds, int64(t.Nanos))
+}
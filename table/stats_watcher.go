@@ -0,0 +1,230 @@
+package table
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+const (
+	DefaultStatsWatcherInterval = time.Minute
+)
+
+// StatsWatcherTrace contains options for tracing StatsWatcher activity.
+type StatsWatcherTrace struct {
+	SampleStart func(StatsWatcherSampleStartInfo)
+	SampleDone  func(StatsWatcherSampleDoneInfo)
+}
+
+type (
+	StatsWatcherSampleStartInfo struct {
+		Context context.Context
+		Path    string
+	}
+	StatsWatcherSampleDoneInfo struct {
+		Context context.Context
+		Path    string
+		Stats   TableStats
+		Error   error
+	}
+)
+
+func (t StatsWatcherTrace) sampleStart(ctx context.Context, path string) {
+	if f := t.SampleStart; f != nil {
+		f(StatsWatcherSampleStartInfo{
+			Context: ctx,
+			Path:    path,
+		})
+	}
+}
+
+func (t StatsWatcherTrace) sampleDone(ctx context.Context, path string, stats TableStats, err error) {
+	if f := t.SampleDone; f != nil {
+		f(StatsWatcherSampleDoneInfo{
+			Context: ctx,
+			Path:    path,
+			Stats:   stats,
+			Error:   err,
+		})
+	}
+}
+
+// StatsWatcher periodically samples statistics of registered tables (such as
+// partitions count, approximate rows count and size) in background using
+// DescribeTable() calls.
+//
+// Sampled stats are available via Stats() method and StatsWatcherTrace.
+type StatsWatcher struct {
+	// SessionProvider is used to obtain sessions for DescribeTable() calls.
+	// Failed calls are retried as Retry() does.
+	// SessionProvider must not be nil.
+	SessionProvider SessionProvider
+
+	// Interval is the frequency of tables sampling.
+	// If Interval is zero then the DefaultStatsWatcherInterval is used.
+	Interval time.Duration
+
+	// PartitionStats reports whether statistics of each table partition must
+	// be sampled too.
+	PartitionStats bool
+
+	// Trace contains watcher tracing options.
+	Trace StatsWatcherTrace
+
+	// Clock is a source of time used by the watcher.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	initOnce sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu     sync.Mutex
+	tables map[string]*watchedTable
+	closed bool
+}
+
+type watchedTable struct {
+	stats TableStats
+	time  time.Time
+	ok    bool
+}
+
+func (w *StatsWatcher) init() {
+	w.initOnce.Do(func() {
+		if w.Interval <= 0 {
+			w.Interval = DefaultStatsWatcherInterval
+		}
+		w.Clock = timeutil.ClockOrDefault(w.Clock)
+		w.ctx, w.cancel = context.WithCancel(context.Background())
+		w.done = make(chan struct{})
+		w.tables = make(map[string]*watchedTable)
+		go w.watcher(w.Clock.NewTimer(w.Interval))
+	})
+}
+
+// Watch registers table at given path for sampling. The first sample is made
+// at the next sampling round.
+func (w *StatsWatcher) Watch(path string) {
+	w.init()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, has := w.tables[path]; !has && !w.closed {
+		w.tables[path] = new(watchedTable)
+	}
+}
+
+// Unwatch removes table at given path from sampling.
+func (w *StatsWatcher) Unwatch(path string) {
+	w.init()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.tables, path)
+}
+
+// Stats returns last successfully sampled stats of table at given path and
+// the time when they were sampled. If table is not registered or was not
+// sampled yet, ok is false.
+func (w *StatsWatcher) Stats(path string) (stats TableStats, at time.Time, ok bool) {
+	w.init()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t := w.tables[path]
+	if t == nil || !t.ok {
+		return stats, at, false
+	}
+	return t.stats, t.time, true
+}
+
+// Sample samples stats of all registered tables immediately. It returns the
+// first error occurred.
+func (w *StatsWatcher) Sample(ctx context.Context) (err error) {
+	w.init()
+	for _, path := range w.paths() {
+		if e := w.sample(ctx, path); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Close stops background sampling.
+func (w *StatsWatcher) Close() error {
+	w.init()
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	w.cancel()
+	<-w.done
+
+	return nil
+}
+
+func (w *StatsWatcher) watcher(timer timeutil.Timer) {
+	defer close(w.done)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-timer.C():
+			_ = w.Sample(w.ctx)
+			timer.Reset(w.Interval)
+		}
+	}
+}
+
+func (w *StatsWatcher) paths() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ps := make([]string, 0, len(w.tables))
+	for path := range w.tables {
+		ps = append(ps, path)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+func (w *StatsWatcher) sample(ctx context.Context, path string) (err error) {
+	opt := WithTableStats()
+	if w.PartitionStats {
+		opt = WithPartitionStats()
+	}
+	var stats TableStats
+	w.Trace.sampleStart(ctx, path)
+	defer func() {
+		w.Trace.sampleDone(ctx, path, stats, err)
+	}()
+	err = Retry(ctx, w.SessionProvider,
+		OperationFunc(func(ctx context.Context, s *Session) error {
+			desc, err := s.DescribeTable(ctx, path, opt)
+			if err == nil && desc.Stats != nil {
+				stats = *desc.Stats
+			}
+			return err
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	now := w.Clock.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Table could be unregistered during sampling.
+	if t := w.tables[path]; t != nil {
+		t.stats = stats
+		t.time = now
+		t.ok = true
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package table
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Scheme"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestStatsWatcher(t *testing.T) {
+	var rows uint64
+	s := newSession(t, methodHandlers{
+		testutil.TableDescribeTable: func(req, res interface{}) error {
+			q := req.(*Ydb_Table.DescribeTableRequest)
+			if !q.IncludeTableStats || !q.IncludePartitionStats {
+				t.Errorf("unexpected request: %v", q)
+			}
+			rows++
+			r := res.(*Ydb_Table.DescribeTableResult)
+			r.Self = &Ydb_Scheme.Entry{Name: q.Path}
+			r.TableStats = &Ydb_Table.TableStats{
+				Partitions:   2,
+				RowsEstimate: rows,
+				PartitionStats: []*Ydb_Table.PartitionStats{
+					{RowsEstimate: rows},
+					{},
+				},
+			}
+			return nil
+		},
+	})
+	clock := timetest.NewClock(time.Unix(0, 0))
+	sampled := make(chan StatsWatcherSampleDoneInfo, 1)
+	w := StatsWatcher{
+		SessionProvider: SingleSession(s),
+		Interval:        time.Second,
+		PartitionStats:  true,
+		Clock:           clock,
+		Trace: StatsWatcherTrace{
+			SampleDone: func(info StatsWatcherSampleDoneInfo) {
+				sampled <- info
+			},
+		},
+	}
+	defer w.Close()
+
+	w.Watch("/db/t")
+	if _, _, ok := w.Stats("/db/t"); ok {
+		t.Fatalf("unexpected stats before sampling")
+	}
+	if err := w.Sample(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-sampled
+	stats, at, ok := w.Stats("/db/t")
+	if !ok || !at.Equal(time.Unix(0, 0)) {
+		t.Fatalf("unexpected sample: %v at %v", ok, at)
+	}
+	if stats.Partitions != 2 || stats.RowsEstimate != 1 || len(stats.PartitionStats) != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// Background sampling.
+	clock.Shift(time.Second)
+	select {
+	case info := <-sampled:
+		if info.Error != nil || info.Stats.RowsEstimate != 2 {
+			t.Fatalf("unexpected background sample: %+v", info)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no background sample")
+	}
+
+	w.Unwatch("/db/t")
+	if _, _, ok := w.Stats("/db/t"); ok {
+		t.Fatalf("unexpected stats after unwatch")
+	}
+}
@@ -1,6 +1,9 @@
 package table
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ClientTrace contains options for tracing table client activity.
 type ClientTrace struct {
@@ -27,6 +30,12 @@ type ClientTrace struct {
 
 	RollbackTransactionStart func(RollbackTransactionStartInfo)
 	RollbackTransactionDone  func(RollbackTransactionDoneInfo)
+
+	CopyTableStart func(CopyTableStartInfo)
+	CopyTableDone  func(CopyTableDoneInfo)
+
+	CopyTablesStart func(CopyTablesStartInfo)
+	CopyTablesDone  func(CopyTablesDoneInfo)
 }
 
 type (
@@ -119,8 +128,39 @@ type (
 		TxID    string
 		Error   error
 	}
+	CopyTableStartInfo struct {
+		Context     context.Context
+		Session     *Session
+		Destination string
+		Source      string
+	}
+	CopyTableDoneInfo struct {
+		Context     context.Context
+		Session     *Session
+		Destination string
+		Source      string
+		Error       error
+	}
+	CopyTablesStartInfo struct {
+		Context context.Context
+		Session *Session
+		Items   []CopyTablesItem
+	}
+	CopyTablesDoneInfo struct {
+		Context context.Context
+		Session *Session
+		Items   []CopyTablesItem
+		Error   error
+	}
 )
 
+// CopyTablesItem describes single table copy made by CopyTables() call.
+type CopyTablesItem struct {
+	Destination string
+	Source      string
+	OmitIndexes bool
+}
+
 type clientTraceContextKey struct{}
 
 func WithClientTrace(ctx context.Context, trace ClientTrace) context.Context {
@@ -314,6 +354,50 @@ func composeClientTrace(a, b ClientTrace) (c ClientTrace) {
 			b.RollbackTransactionDone(info)
 		}
 	}
+	switch {
+	case a.CopyTableStart == nil:
+		c.CopyTableStart = b.CopyTableStart
+	case b.CopyTableStart == nil:
+		c.CopyTableStart = a.CopyTableStart
+	default:
+		c.CopyTableStart = func(info CopyTableStartInfo) {
+			a.CopyTableStart(info)
+			b.CopyTableStart(info)
+		}
+	}
+	switch {
+	case a.CopyTableDone == nil:
+		c.CopyTableDone = b.CopyTableDone
+	case b.CopyTableDone == nil:
+		c.CopyTableDone = a.CopyTableDone
+	default:
+		c.CopyTableDone = func(info CopyTableDoneInfo) {
+			a.CopyTableDone(info)
+			b.CopyTableDone(info)
+		}
+	}
+	switch {
+	case a.CopyTablesStart == nil:
+		c.CopyTablesStart = b.CopyTablesStart
+	case b.CopyTablesStart == nil:
+		c.CopyTablesStart = a.CopyTablesStart
+	default:
+		c.CopyTablesStart = func(info CopyTablesStartInfo) {
+			a.CopyTablesStart(info)
+			b.CopyTablesStart(info)
+		}
+	}
+	switch {
+	case a.CopyTablesDone == nil:
+		c.CopyTablesDone = b.CopyTablesDone
+	case b.CopyTablesDone == nil:
+		c.CopyTablesDone = a.CopyTablesDone
+	default:
+		c.CopyTablesDone = func(info CopyTablesDoneInfo) {
+			a.CopyTablesDone(info)
+			b.CopyTablesDone(info)
+		}
+	}
 	return
 }
 
@@ -332,6 +416,10 @@ type SessionPoolTrace struct {
 	PutDone        func(SessionPoolPutDoneInfo)
 	CloseStart     func(SessionPoolCloseStartInfo)
 	CloseDone      func(SessionPoolCloseDoneInfo)
+
+	// Leak is called when session received by Get() is not returned to the
+	// pool for longer than SessionPool's LeakThreshold.
+	Leak func(SessionPoolLeakInfo)
 }
 
 type (
@@ -391,6 +479,19 @@ type (
 		Context context.Context
 		Error   error
 	}
+	SessionPoolLeakInfo struct {
+		Context context.Context
+		Session *Session
+
+		// CheckedOut is the time when session was received by Get().
+		CheckedOut time.Time
+
+		// Held is the duration session is held by the caller of Get().
+		Held time.Duration
+
+		// Stack is the stack trace of the Get() call.
+		Stack []byte
+	}
 )
 
 type sessionPoolTraceContextKey struct{}
@@ -553,5 +654,16 @@ func composeSessionPoolTrace(a, b SessionPoolTrace) (c SessionPoolTrace) {
 			b.CloseDone(info)
 		}
 	}
+	switch {
+	case a.Leak == nil:
+		c.Leak = b.Leak
+	case b.Leak == nil:
+		c.Leak = a.Leak
+	default:
+		c.Leak = func(info SessionPoolLeakInfo) {
+			a.Leak(info)
+			b.Leak(info)
+		}
+	}
 	return
 }
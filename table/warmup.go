@@ -0,0 +1,89 @@
+package table
+
+import (
+	"context"
+	"sync"
+)
+
+// WarmupConfig describes what SessionPool's Warmup() prepares in advance.
+type WarmupConfig struct {
+	// Sessions is the number of sessions to create. It is limited by the
+	// pool size limit.
+	Sessions int
+
+	// PrepareQueries contains texts of data queries to prepare within each
+	// created session. Prepared queries are kept in the session's statement
+	// cache, thus further Prepare() calls of the same text are not sent to the
+	// server.
+	PrepareQueries []string
+
+	// DescribePaths contains paths of the tables to describe once. It makes
+	// the server to load the schema of the tables.
+	DescribePaths []string
+}
+
+// Warmup prepares the pool to serve requests right after the start: it
+// creates sessions simultaneously (which also establishes connections to the
+// endpoints sessions are created on), prepares queries within them and
+// describes tables. Created sessions are put back to the pool as idle ones.
+//
+// It returns the first error occurred. Sessions created before the error are
+// kept in the pool anyway.
+func (p *SessionPool) Warmup(ctx context.Context, c WarmupConfig) error {
+	p.init()
+
+	n := c.Sessions
+	if n > p.limit {
+		n = p.limit
+	}
+	if n <= 0 && len(c.DescribePaths) > 0 {
+		n = 1
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		ss   = make([]*Session, 0, n)
+		fail error
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail == nil {
+			fail = err
+		}
+	}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := p.Get(ctx)
+			if err != nil {
+				setErr(err)
+				return
+			}
+			mu.Lock()
+			ss = append(ss, s)
+			mu.Unlock()
+			for _, q := range c.PrepareQueries {
+				if _, err := s.Prepare(ctx, q); err != nil {
+					setErr(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fail == nil && len(ss) > 0 {
+		for _, path := range c.DescribePaths {
+			if _, err := ss[0].DescribeTable(ctx, path); err != nil {
+				fail = err
+				break
+			}
+		}
+	}
+	for _, s := range ss {
+		_ = p.Put(ctx, s)
+	}
+	return fail
+}
@@ -0,0 +1,80 @@
+package table
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Scheme"
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/testutil"
+)
+
+func TestSessionPoolWarmup(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		prepared  = make(map[string]int)
+		described []string
+	)
+	p := &SessionPool{
+		SizeLimit:         2,
+		BusyCheckInterval: time.Hour,
+		IdleThreshold:     -1,
+		Builder: &StubBuilder{
+			T:     t,
+			Limit: 2,
+			Handler: methodHandlers{
+				testutil.TablePrepareDataQuery: func(req, res interface{}) error {
+					mu.Lock()
+					defer mu.Unlock()
+					prepared[req.(*Ydb_Table.PrepareDataQueryRequest).YqlText]++
+					return nil
+				},
+				testutil.TableDescribeTable: func(req, res interface{}) error {
+					mu.Lock()
+					defer mu.Unlock()
+					described = append(described, req.(*Ydb_Table.DescribeTableRequest).Path)
+					res.(*Ydb_Table.DescribeTableResult).Self = new(Ydb_Scheme.Entry)
+					return nil
+				},
+				testutil.TableDeleteSession: func(req, res interface{}) error {
+					return nil
+				},
+			},
+		},
+	}
+	defer func() {
+		_ = p.Close(context.Background())
+	}()
+
+	err := p.Warmup(context.Background(), WarmupConfig{
+		Sessions:       3, // Greater than the SizeLimit.
+		PrepareQueries: []string{"SELECT 1;", "SELECT 2;"},
+		DescribePaths:  []string{"series"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := p.Stats(); stats.Idle != 2 {
+		t.Fatalf("unexpected number of idle sessions: %d", stats.Idle)
+	}
+	for _, q := range []string{"SELECT 1;", "SELECT 2;"} {
+		if n := prepared[q]; n != 2 {
+			t.Errorf("query %q prepared %d times; want 2", q, n)
+		}
+	}
+	if len(described) != 1 || described[0] != "series" {
+		t.Errorf("unexpected described paths: %v", described)
+	}
+
+	// Statements must be cached by sessions.
+	s := mustGetSession(t, p)
+	if _, err := s.Prepare(context.Background(), "SELECT 1;"); err != nil {
+		t.Fatal(err)
+	}
+	if n := prepared["SELECT 1;"]; n != 2 {
+		t.Errorf("query prepared again")
+	}
+	mustPutSession(t, p, s)
+}
@@ -29,6 +29,7 @@ const (
 	TableDropTable
 	TableAlterTable
 	TableCopyTable
+	TableCopyTables
 	TableDescribeTable
 	TableExplainDataQuery
 	TablePrepareDataQuery
@@ -49,6 +50,7 @@ var grpcMethodToCode = map[string]MethodCode{
 	Ydb_Table_V1.DropTable:            TableDropTable,
 	Ydb_Table_V1.AlterTable:           TableAlterTable,
 	Ydb_Table_V1.CopyTable:            TableCopyTable,
+	Ydb_Table_V1.CopyTables:           TableCopyTables,
 	Ydb_Table_V1.DescribeTable:        TableDescribeTable,
 	Ydb_Table_V1.ExplainDataQuery:     TableExplainDataQuery,
 	Ydb_Table_V1.PrepareDataQuery:     TablePrepareDataQuery,
@@ -69,6 +71,7 @@ var codeToString = map[MethodCode]string{
 	TableDropTable:            lastSegment(Ydb_Table_V1.DropTable),
 	TableAlterTable:           lastSegment(Ydb_Table_V1.AlterTable),
 	TableCopyTable:            lastSegment(Ydb_Table_V1.CopyTable),
+	TableCopyTables:           lastSegment(Ydb_Table_V1.CopyTables),
 	TableDescribeTable:        lastSegment(Ydb_Table_V1.DescribeTable),
 	TableExplainDataQuery:     lastSegment(Ydb_Table_V1.ExplainDataQuery),
 	TablePrepareDataQuery:     lastSegment(Ydb_Table_V1.PrepareDataQuery),
@@ -0,0 +1,101 @@
+package timetest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// Clock is a manually controlled timeutil.Clock implementation.
+// Its time is changed only by Shift() calls; timers created by NewTimer()
+// fire when clock's time reaches their deadlines.
+//
+// Clock is safe for use by multiple goroutines simultaneously.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*clockTimer
+}
+
+// NewClock creates new Clock with current time set to now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns current clock's time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Shift shifts current clock's time by d and fires expired timers.
+func (c *Clock) Shift(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		t.fire(c.now)
+	}
+}
+
+// NewTimer creates new timer which fires after clock's time is shifted by at
+// least d.
+func (c *Clock) NewTimer(d time.Duration) timeutil.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &clockTimer{
+		clock: c,
+		ch:    make(chan time.Time, 1),
+	}
+	t.reset(d)
+	c.timers = append(c.timers, t)
+	t.fire(c.now)
+	return t
+}
+
+// clockTimer fields are guarded by clock's mutex.
+type clockTimer struct {
+	clock    *Clock
+	ch       chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *clockTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *clockTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.reset(d)
+	t.fire(t.clock.now)
+	return active
+}
+
+func (t *clockTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.active
+	t.active = false
+	return active
+}
+
+func (t *clockTimer) reset(d time.Duration) {
+	t.deadline = t.clock.now.Add(d)
+	t.active = true
+}
+
+func (t *clockTimer) fire(now time.Time) {
+	if !t.active || now.Before(t.deadline) {
+		return
+	}
+	t.active = false
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
@@ -69,3 +69,31 @@ func (t timeTimer) Reset(d time.Duration) bool {
 func (t timeTimer) Stop() bool {
 	return t.t.Stop()
 }
+
+// Clock is the interface of time source used by ydb packages.
+type Clock interface {
+	Now() time.Time
+	NewTimer(time.Duration) Timer
+}
+
+// DefaultClock is a Clock which uses Now() and NewTimer() functions of this
+// package.
+var DefaultClock Clock = defaultClock{}
+
+type defaultClock struct{}
+
+func (defaultClock) Now() time.Time {
+	return Now()
+}
+
+func (defaultClock) NewTimer(d time.Duration) Timer {
+	return NewTimer(d)
+}
+
+// ClockOrDefault returns c or DefaultClock if c is nil.
+func ClockOrDefault(c Clock) Clock {
+	if c == nil {
+		return DefaultClock
+	}
+	return c
+}
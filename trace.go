@@ -3,6 +3,7 @@ package ydb
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Operations"
 	"github.com/yandex-cloud/ydb-go-sdk/internal"
@@ -26,6 +27,10 @@ type DriverTrace struct {
 	DiscoveryStart func(DiscoveryStartInfo)
 	DiscoveryDone  func(DiscoveryDoneInfo)
 
+	// DiscoveryDiff is called when discovery round changes the set of
+	// endpoints or their properties. It is not called when nothing changed.
+	DiscoveryDiff func(DiscoveryDiffInfo)
+
 	OperationStart func(OperationStartInfo)
 	OperationWait  func(OperationWaitInfo)
 	OperationDone  func(OperationDoneInfo)
@@ -114,11 +119,15 @@ func (d DriverTrace) getCredentialsStart(ctx context.Context) {
 		f(x)
 	}
 }
-func (d DriverTrace) getCredentialsDone(ctx context.Context, token bool, err error) {
+func (d DriverTrace) getCredentialsDone(ctx context.Context, src Credentials, token bool, latency, age, ttl time.Duration, err error) {
 	x := GetCredentialsDoneInfo{
-		Context: ctx,
-		Token:   token,
-		Error:   err,
+		Context:     ctx,
+		Credentials: src,
+		Token:       token,
+		Latency:     latency,
+		TokenAge:    age,
+		TokenTTL:    ttl,
+		Error:       err,
 	}
 	if f := d.GetCredentialsDone; f != nil {
 		f(x)
@@ -151,6 +160,23 @@ func (d DriverTrace) discoveryDone(ctx context.Context, es []Endpoint, err error
 		f(x)
 	}
 }
+func (d DriverTrace) discoveryDiff(ctx context.Context, added, removed, updated []Endpoint) {
+	if len(added)+len(removed)+len(updated) == 0 {
+		return
+	}
+	x := DiscoveryDiffInfo{
+		Context: ctx,
+		Added:   added,
+		Removed: removed,
+		Updated: updated,
+	}
+	if f := d.DiscoveryDiff; f != nil {
+		f(x)
+	}
+	if f := ContextDriverTrace(ctx).DiscoveryDiff; f != nil {
+		f(x)
+	}
+}
 func (d DriverTrace) operationStart(ctx context.Context, conn *conn, method string, params OperationParams) {
 	x := OperationStartInfo{
 		Context: ctx,
@@ -296,8 +322,27 @@ type (
 	}
 	GetCredentialsDoneInfo struct {
 		Context context.Context
-		Token   bool
-		Error   error
+
+		// Credentials is the credentials which returned the token. It is
+		// the first succeeded underlying credentials for the
+		// MultiCredentials().
+		Credentials Credentials
+
+		Token bool
+
+		// Latency is the duration of the Credentials' Token() call.
+		Latency time.Duration
+
+		// TokenAge is the time passed since the current token was obtained
+		// for the first time.
+		TokenAge time.Duration
+
+		// TokenTTL is the remaining lifetime of the current token. It is
+		// zero if credentials do not implement TokenExpirer or expiration
+		// time is unknown. It is negative if token is already expired.
+		TokenTTL time.Duration
+
+		Error error
 	}
 	DiscoveryStartInfo struct {
 		Context context.Context
@@ -307,6 +352,14 @@ type (
 		Endpoints []Endpoint
 		Error     error
 	}
+	DiscoveryDiffInfo struct {
+		Context context.Context
+		Added   []Endpoint
+		Removed []Endpoint
+		// Updated contains new versions of endpoints with the same address
+		// but changed properties (such as load factor or locality).
+		Updated []Endpoint
+	}
 	OperationStartInfo struct {
 		Context context.Context
 		Address string
@@ -466,6 +519,17 @@ func composeDriverTrace(a, b DriverTrace) (c DriverTrace) {
 		}
 	}
 	switch {
+	case a.DiscoveryDiff == nil:
+		c.DiscoveryDiff = b.DiscoveryDiff
+	case b.DiscoveryDiff == nil:
+		c.DiscoveryDiff = a.DiscoveryDiff
+	default:
+		c.DiscoveryDiff = func(info DiscoveryDiffInfo) {
+			a.DiscoveryDiff(info)
+			b.DiscoveryDiff(info)
+		}
+	}
+	switch {
 	case a.OperationStart == nil:
 		c.OperationStart = b.OperationStart
 	case b.OperationStart == nil:
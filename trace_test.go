@@ -1,6 +1,7 @@
 package ydb
 
 import (
+	"context"
 	"testing"
 
 	"github.com/yandex-cloud/ydb-go-sdk/internal/tracetest"
@@ -9,3 +10,21 @@ import (
 func TestDriverTraceCompose(t *testing.T) {
 	tracetest.TestCompose(t, composeDriverTrace, DriverTrace{})
 }
+
+func TestDriverTraceDiscoveryDiff(t *testing.T) {
+	var calls []DiscoveryDiffInfo
+	trace := DriverTrace{
+		DiscoveryDiff: func(info DiscoveryDiffInfo) {
+			calls = append(calls, info)
+		},
+	}
+	ctx := context.Background()
+	trace.discoveryDiff(ctx, nil, nil, nil)
+	if len(calls) != 0 {
+		t.Fatalf("unexpected call for empty diff")
+	}
+	trace.discoveryDiff(ctx, []Endpoint{{Addr: "a"}}, nil, nil)
+	if len(calls) != 1 || len(calls[0].Added) != 1 {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+}
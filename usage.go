@@ -0,0 +1,280 @@
+package ydb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil"
+)
+
+// DefaultUsageTopMethods is the number of methods reported in UsageSummary
+// by default.
+const DefaultUsageTopMethods = 10
+
+// MethodUsage contains usage counters of a single method.
+type MethodUsage struct {
+	Method string
+
+	// Calls is the number of completed calls (or streams) of the method.
+	Calls uint64
+
+	// Errors is the number of failed calls of the method.
+	Errors uint64
+
+	// Latency is the total duration of all calls of the method.
+	Latency time.Duration
+
+	// MaxLatency is the duration of the longest call of the method.
+	MaxLatency time.Duration
+}
+
+// AvgLatency returns average duration of the method call.
+func (m MethodUsage) AvgLatency() time.Duration {
+	if m.Calls == 0 {
+		return 0
+	}
+	return m.Latency / time.Duration(m.Calls)
+}
+
+// UsageSummary contains aggregated statistics of driver usage.
+type UsageSummary struct {
+	// Duration is the time passed since UsageStats was first used.
+	Duration time.Duration
+
+	// Calls is the total number of completed unary and streaming
+	// operations.
+	Calls uint64
+
+	// Errors is the total number of failed operations.
+	Errors uint64
+
+	// Statuses contains number of operations failed with OpError by status
+	// code.
+	Statuses map[StatusCode]uint64
+
+	// TransportErrors contains number of operations failed with
+	// TransportError by transport error code.
+	TransportErrors map[TransportErrorCode]uint64
+
+	// Methods contains the top methods ordered by total latency descending.
+	Methods []MethodUsage
+
+	// DiscoveryRounds is the number of completed discovery rounds.
+	DiscoveryRounds uint64
+
+	// DiscoveryErrors is the number of failed discovery rounds.
+	DiscoveryErrors uint64
+}
+
+// String returns human readable multi-line representation of the summary.
+// It is suitable to be printed at the end of the program run.
+func (s UsageSummary) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf,
+		"ydb: %d calls, %d errors, %d discovery rounds (%d failed) in %v\n",
+		s.Calls, s.Errors, s.DiscoveryRounds, s.DiscoveryErrors, s.Duration,
+	)
+	codes := make([]StatusCode, 0, len(s.Statuses))
+	for c := range s.Statuses {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	for _, c := range codes {
+		fmt.Fprintf(&buf, "  status %v: %d\n", c, s.Statuses[c])
+	}
+	reasons := make([]TransportErrorCode, 0, len(s.TransportErrors))
+	for r := range s.TransportErrors {
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		return reasons[i] < reasons[j]
+	})
+	for _, r := range reasons {
+		fmt.Fprintf(&buf, "  transport %v: %d\n", r, s.TransportErrors[r])
+	}
+	for _, m := range s.Methods {
+		fmt.Fprintf(&buf,
+			"  %s: %d calls, %d errors, total %v, avg %v, max %v\n",
+			m.Method, m.Calls, m.Errors, m.Latency, m.AvgLatency(), m.MaxLatency,
+		)
+	}
+	return buf.String()
+}
+
+// UsageStats collects driver usage statistics: number of calls, errors by
+// status, latencies by method and discovery rounds. It is helpful for batch
+// jobs and command line tools which want to report driver usage at the end
+// of the run.
+//
+// UsageStats may be used as driver middleware (see CallMiddleware() and
+// StreamReadMiddleware()) along with DriverTrace() to account discovery
+// rounds. See also DriverConfig's UsageSummary field.
+type UsageStats struct {
+	// TopMethods is the maximum number of methods reported by Summary().
+	// If TopMethods is zero then the DefaultUsageTopMethods is used.
+	// If TopMethods is negative then all methods are reported.
+	TopMethods int
+
+	// Clock is a source of time used to measure latencies.
+	// If Clock is nil then the timeutil.DefaultClock is used.
+	Clock timeutil.Clock
+
+	once  sync.Once
+	start time.Time
+
+	mu              sync.Mutex
+	calls           uint64
+	errors          uint64
+	statuses        map[StatusCode]uint64
+	transportErrors map[TransportErrorCode]uint64
+	methods         map[string]*MethodUsage
+	discoveryRounds uint64
+	discoveryErrors uint64
+}
+
+// CallMiddleware returns driver middleware which accounts unary operations.
+func (u *UsageStats) CallMiddleware() CallMiddleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, op internal.Operation) error {
+			method, _, _ := internal.Unwrap(op)
+			u.init()
+			start := u.now()
+			err := next(ctx, op)
+			u.observe(method, u.now().Sub(start), err)
+			return err
+		}
+	}
+}
+
+// StreamReadMiddleware returns driver middleware which accounts streaming
+// operations. Latency of the stream is the time until the stream is closed.
+func (u *UsageStats) StreamReadMiddleware() StreamReadMiddleware {
+	return func(next StreamReadFunc) StreamReadFunc {
+		return func(ctx context.Context, op internal.StreamOperation) error {
+			method, req, resp, process := internal.UnwrapStreamOperation(op)
+			u.init()
+			start := u.now()
+			err := next(ctx, internal.WrapStreamOperation(
+				method, req, resp,
+				func(err error) {
+					process(err)
+					if err != nil {
+						// Non-nil error (including io.EOF) means the end of the
+						// stream.
+						u.observe(method, u.now().Sub(start), hideEOF(err))
+					}
+				},
+			))
+			if err != nil {
+				u.observe(method, u.now().Sub(start), err)
+			}
+			return err
+		}
+	}
+}
+
+// DriverTrace returns DriverTrace which accounts discovery rounds.
+func (u *UsageStats) DriverTrace() DriverTrace {
+	return DriverTrace{
+		DiscoveryDone: func(x DiscoveryDoneInfo) {
+			u.init()
+			u.mu.Lock()
+			defer u.mu.Unlock()
+			u.discoveryRounds++
+			if x.Error != nil {
+				u.discoveryErrors++
+			}
+		},
+	}
+}
+
+// Summary returns statistics collected so far.
+func (u *UsageStats) Summary() UsageSummary {
+	u.init()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s := UsageSummary{
+		Duration:        u.now().Sub(u.start),
+		Calls:           u.calls,
+		Errors:          u.errors,
+		Statuses:        make(map[StatusCode]uint64, len(u.statuses)),
+		TransportErrors: make(map[TransportErrorCode]uint64, len(u.transportErrors)),
+		Methods:         make([]MethodUsage, 0, len(u.methods)),
+		DiscoveryRounds: u.discoveryRounds,
+		DiscoveryErrors: u.discoveryErrors,
+	}
+	for c, n := range u.statuses {
+		s.Statuses[c] = n
+	}
+	for r, n := range u.transportErrors {
+		s.TransportErrors[r] = n
+	}
+	for _, m := range u.methods {
+		s.Methods = append(s.Methods, *m)
+	}
+	sort.Slice(s.Methods, func(i, j int) bool {
+		a, b := s.Methods[i], s.Methods[j]
+		if a.Latency == b.Latency {
+			return a.Method < b.Method
+		}
+		return a.Latency > b.Latency
+	})
+	n := u.TopMethods
+	if n == 0 {
+		n = DefaultUsageTopMethods
+	}
+	if n > 0 && len(s.Methods) > n {
+		s.Methods = s.Methods[:n]
+	}
+	return s
+}
+
+func (u *UsageStats) observe(method string, latency time.Duration, err error) {
+	u.init()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.methods == nil {
+		u.methods = make(map[string]*MethodUsage)
+		u.statuses = make(map[StatusCode]uint64)
+		u.transportErrors = make(map[TransportErrorCode]uint64)
+	}
+	m := u.methods[method]
+	if m == nil {
+		m = &MethodUsage{Method: method}
+		u.methods[method] = m
+	}
+	u.calls++
+	m.Calls++
+	m.Latency += latency
+	if latency > m.MaxLatency {
+		m.MaxLatency = latency
+	}
+	if err == nil {
+		return
+	}
+	u.errors++
+	m.Errors++
+	switch e := err.(type) {
+	case *OpError:
+		u.statuses[e.Reason]++
+	case *TransportError:
+		u.transportErrors[e.Reason]++
+	}
+}
+
+func (u *UsageStats) init() {
+	u.once.Do(func() {
+		u.start = u.now()
+	})
+}
+
+func (u *UsageStats) now() time.Time {
+	return timeutil.ClockOrDefault(u.Clock).Now()
+}
@@ -0,0 +1,92 @@
+package ydb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk/api/protos/Ydb_Table"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+func TestUsageStats(t *testing.T) {
+	clock := timetest.NewClock(time.Unix(0, 0))
+	u := UsageStats{
+		TopMethods: 2,
+		Clock:      clock,
+	}
+	errs := map[string]error{
+		"fast": nil,
+		"slow": &OpError{Reason: StatusOverloaded},
+		"rare": &TransportError{Reason: TransportErrorUnavailable},
+		"misc": errors.New("test error"),
+	}
+	latency := map[string]time.Duration{
+		"fast": time.Millisecond,
+		"slow": time.Second,
+		"rare": 10 * time.Millisecond,
+		"misc": 0,
+	}
+	call := u.CallMiddleware()(func(ctx context.Context, op internal.Operation) error {
+		method, _, _ := internal.Unwrap(op)
+		clock.Shift(latency[method])
+		return errs[method]
+	})
+	for _, method := range []string{"fast", "fast", "slow", "rare", "misc"} {
+		_ = call(context.Background(), internal.Wrap(method, nil, nil))
+	}
+
+	read := u.StreamReadMiddleware()(func(ctx context.Context, op internal.StreamOperation) error {
+		_, _, _, process := internal.UnwrapStreamOperation(op)
+		process(nil)
+		clock.Shift(2 * time.Second)
+		process(io.EOF)
+		return nil
+	})
+	err := read(context.Background(), internal.WrapStreamOperation(
+		"stream", nil, new(Ydb_Table.ReadTableResponse), func(error) {},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trace := u.DriverTrace()
+	trace.DiscoveryDone(DiscoveryDoneInfo{})
+	trace.DiscoveryDone(DiscoveryDoneInfo{Error: errors.New("test error")})
+
+	exp := UsageSummary{
+		Duration: 3*time.Second + 12*time.Millisecond,
+		Calls:    6,
+		Errors:   3,
+		Statuses: map[StatusCode]uint64{
+			StatusOverloaded: 1,
+		},
+		TransportErrors: map[TransportErrorCode]uint64{
+			TransportErrorUnavailable: 1,
+		},
+		Methods: []MethodUsage{
+			{
+				Method:     "stream",
+				Calls:      1,
+				Latency:    2 * time.Second,
+				MaxLatency: 2 * time.Second,
+			},
+			{
+				Method:     "slow",
+				Calls:      1,
+				Errors:     1,
+				Latency:    time.Second,
+				MaxLatency: time.Second,
+			},
+		},
+		DiscoveryRounds: 2,
+		DiscoveryErrors: 1,
+	}
+	if act := u.Summary(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected summary:\n%s\nwant:\n%s", act, exp)
+	}
+}
@@ -82,3 +82,113 @@ func DictValue(pairs ...Value) Value {
 func VariantValue(v Value, i uint32, variantT Type) Value {
 	return internal.VariantValue(v, i, variantT)
 }
+
+// TypeError is returned by the value destructuring functions, such as
+// Int32FromValue() or ListItems(), when type of the value differs from the
+// expected one.
+type TypeError = internal.TypeError
+
+// TypeOf returns type of v.
+func TypeOf(v Value) Type { return internal.ValueType(v) }
+
+// IsNull reports whether v is a NULL value.
+func IsNull(v Value) bool { return internal.IsNull(v) }
+
+// UnwrapOptional returns the value wrapped by optional value v and true, or
+// nil and false if v is NULL. If v is not of optional type, it returns v and
+// true. That is, Optional<Optional<T>> must be unwrapped twice.
+func UnwrapOptional(v Value) (Value, bool) {
+	x, ok := internal.UnwrapOptional(v)
+	if !ok {
+		return nil, false
+	}
+	return x, true
+}
+
+// ListItems returns items of the list value v.
+func ListItems(v Value) ([]Value, error) {
+	xs, err := internal.ListItems(v)
+	return values(xs), err
+}
+
+// TupleItems returns elements of the tuple value v.
+func TupleItems(v Value) ([]Value, error) {
+	xs, err := internal.TupleItems(v)
+	return values(xs), err
+}
+
+// StructFields calls it for each field of the struct value v in order of the
+// struct type declaration.
+func StructFields(v Value, it func(name string, v Value)) error {
+	return internal.StructFieldsOf(v, func(name string, v internal.V) {
+		it(name, v)
+	})
+}
+
+// DictPairs calls it for each key and payload pair of the dict value v.
+func DictPairs(v Value, it func(key, payload Value)) error {
+	return internal.DictPairs(v, func(key, payload internal.V) {
+		it(key, payload)
+	})
+}
+
+// VariantItem returns the value stored in the variant value v and its index.
+func VariantItem(v Value) (Value, uint32, error) {
+	x, i, err := internal.VariantItem(v)
+	if err != nil {
+		return nil, 0, err
+	}
+	return x, i, nil
+}
+
+// Functions below return underlying value of primitive value v. They return
+// *TypeError if v is of other type. Optional values must be unwrapped first
+// with UnwrapOptional().
+func BoolFromValue(v Value) (bool, error)          { return internal.BoolFromValue(v) }
+func Int8FromValue(v Value) (int8, error)          { return internal.Int8FromValue(v) }
+func Uint8FromValue(v Value) (uint8, error)        { return internal.Uint8FromValue(v) }
+func Int16FromValue(v Value) (int16, error)        { return internal.Int16FromValue(v) }
+func Uint16FromValue(v Value) (uint16, error)      { return internal.Uint16FromValue(v) }
+func Int32FromValue(v Value) (int32, error)        { return internal.Int32FromValue(v) }
+func Uint32FromValue(v Value) (uint32, error)      { return internal.Uint32FromValue(v) }
+func Int64FromValue(v Value) (int64, error)        { return internal.Int64FromValue(v) }
+func Uint64FromValue(v Value) (uint64, error)      { return internal.Uint64FromValue(v) }
+func FloatFromValue(v Value) (float32, error)      { return internal.FloatFromValue(v) }
+func DoubleFromValue(v Value) (float64, error)     { return internal.DoubleFromValue(v) }
+func DateFromValue(v Value) (uint32, error)        { return internal.DateFromValue(v) }
+func DatetimeFromValue(v Value) (uint32, error)    { return internal.DatetimeFromValue(v) }
+func TimestampFromValue(v Value) (uint64, error)   { return internal.TimestampFromValue(v) }
+func IntervalFromValue(v Value) (int64, error)     { return internal.IntervalFromValue(v) }
+func TzDateFromValue(v Value) (string, error)      { return internal.TzDateFromValue(v) }
+func TzDatetimeFromValue(v Value) (string, error)  { return internal.TzDatetimeFromValue(v) }
+func TzTimestampFromValue(v Value) (string, error) { return internal.TzTimestampFromValue(v) }
+func StringFromValue(v Value) ([]byte, error)      { return internal.StringFromValue(v) }
+func UTF8FromValue(v Value) (string, error)        { return internal.UTF8FromValue(v) }
+func YSONFromValue(v Value) (string, error)        { return internal.YSONFromValue(v) }
+func JSONFromValue(v Value) (string, error)        { return internal.JSONFromValue(v) }
+func UUIDFromValue(v Value) ([16]byte, error)      { return internal.UUIDFromValue(v) }
+
+func values(xs []internal.V) []Value {
+	if xs == nil {
+		return nil
+	}
+	vs := make([]Value, len(xs))
+	for i, x := range xs {
+		vs[i] = x
+	}
+	return vs
+}
+
+// FormatValue returns human-readable representation of v which is intended
+// mostly for debugging and logging purposes. Optional values are rendered as
+// underlying value or NULL; dates and times are rendered in RFC3339 format.
+func FormatValue(v Value) string {
+	return internal.FormatValue(v)
+}
+
+// FormatYQL returns YQL literal expression which evaluates to v, such as
+// `Just(Int64("42"))`. It may be used to generate YQL scripts from the values
+// read from the database.
+func FormatYQL(v Value) (string, error) {
+	return internal.FormatYQL(v)
+}
@@ -38,6 +38,16 @@ func WithEndpoint(addr string) ConnectorOption {
 	}
 }
 
+// WithDriverOptions returns ConnectorOption which appends given options used
+// to construct the driver with ydb.New(). Options are applied after ones
+// configured by WithDialer(), WithEndpoint() and other connector options and
+// thus may override them.
+func WithDriverOptions(opts ...ydb.Option) ConnectorOption {
+	return func(c *connector) {
+		c.driverOptions = append(c.driverOptions, opts...)
+	}
+}
+
 func WithDriverConfig(config ydb.DriverConfig) ConnectorOption {
 	return func(c *connector) {
 		*(c.dialer.DriverConfig) = config
@@ -122,9 +132,10 @@ func Connector(opts ...ConnectorOption) driver.Connector {
 			DriverConfig: new(ydb.DriverConfig),
 		},
 		retryConfig: RetryConfig{
-			MaxRetries:   ydb.DefaultMaxRetries,
-			Backoff:      ydb.DefaultBackoff,
-			RetryChecker: retryChecker,
+			MaxRetries:    ydb.DefaultMaxRetries,
+			Backoff:       ydb.DefaultBackoff,
+			BackoffPolicy: ydb.DefaultBackoffPolicy,
+			RetryChecker:  retryChecker,
 		},
 	}
 	for _, opt := range opts {
@@ -135,8 +146,9 @@ func Connector(opts ...ConnectorOption) driver.Connector {
 
 // USE CONNECTOR ONLY
 type connector struct {
-	dialer   ydb.Dialer
-	endpoint string
+	dialer        ydb.Dialer
+	endpoint      string
+	driverOptions []ydb.Option
 
 	clientTrace table.ClientTrace
 
@@ -188,7 +200,11 @@ func (c *connector) prepare(dial func(context.Context) (*table.Client, error)) {
 }
 
 func (c *connector) dial(ctx context.Context) (*table.Client, error) {
-	d, err := c.dialer.Dial(ctx, c.endpoint)
+	opts := append([]ydb.Option{
+		ydb.WithDialer(&c.dialer),
+		ydb.WithEndpoint(c.endpoint),
+	}, c.driverOptions...)
+	d, err := ydb.New(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +34,14 @@ required.
 Data source name parameters:
  token – access token to be used during requests (required).
 
+Data source name also may be a connection string with "grpc" or "grpcs" scheme
+as accepted by ydb.WithConnectionString():
+
+	db, err := sql.Open("ydb", "grpcs://endpoint/?database=/mydb&auth=metadata")
+
+Note that errors of such data source name are reported on the first
+connection attempt rather than by sql.Open().
+
 As you may notice, initialization via sql.Open() does not provide ability to
 setup tracing configuration.
 
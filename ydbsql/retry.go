@@ -15,6 +15,10 @@ type RetryConfig struct {
 	// Backoff is a selected backoff policy.
 	// If backoff is nil, then the DefaultBackoff is used.
 	Backoff ydb.Backoff
+
+	// BackoffPolicy contains backoff overrides for particular errors. When
+	// there is no override for an error, Backoff is used.
+	BackoffPolicy ydb.BackoffPolicy
 }
 
 func isBusy(err error) bool {
@@ -291,10 +291,13 @@ func (c *conn) exec(ctx context.Context, tx *table.TransactionControl, exec exec
 		// NOTE: when under transaction, no retries must be done.
 		maxRetries = 0
 	}
-	var m ydb.RetryMode
+	var (
+		m ydb.RetryMode
+		b ydb.Backoff
+	)
 	for i := 0; i <= maxRetries; i++ {
-		if m.MustBackoff() {
-			e := ydb.WaitBackoff(ctx, c.retryConfig.Backoff, i-1)
+		if b != nil {
+			e := ydb.WaitBackoff(ctx, b, i-1)
 			if e != nil {
 				// Use original error to make it possible to lay on for the
 				// client.
@@ -336,6 +339,7 @@ func (c *conn) exec(ctx context.Context, tx *table.TransactionControl, exec exec
 		if !m.Retriable() {
 			break
 		}
+		b = c.retryConfig.BackoffPolicy.Backoff(err, m, c.retryConfig.Backoff)
 	}
 	return nil, err
 }
@@ -394,8 +398,8 @@ func (d TxDoer) Do(ctx context.Context, f TxOperationFunc) (err error) {
 		if !m.Retriable() {
 			return err
 		}
-		if m.MustBackoff() {
-			if e := ydb.WaitBackoff(ctx, rc.Backoff, i); e != nil {
+		if b := rc.BackoffPolicy.Backoff(err, m, rc.Backoff); b != nil {
+			if e := ydb.WaitBackoff(ctx, b, i); e != nil {
 				// Return original error to make it possible to lay on for the
 				// client.
 				return err
@@ -507,6 +511,14 @@ func checkNamedValue(v *driver.NamedValue) (err error) {
 	case ydb.Value:
 		// OK.
 
+	case nil:
+		// Untyped NULL could not be converted to the YDB value. Report it
+		// here rather than letting server fail with GENERIC_ERROR.
+		return fmt.Errorf(
+			"ydbsql: NULL value of parameter %q has no type; use ydb.NullValue() instead",
+			v.Name,
+		)
+
 	case valuer:
 		// Some ydbsql level types implement valuer interface.
 		// Currently it is a date/time types.
@@ -16,11 +16,20 @@ func init() {
 type legacyDriver struct {
 }
 
+// OpenConnector returns connector for the data source name, which is either
+// the connection string accepted by ydb.WithConnectionString() (with "grpc"
+// or "grpcs" scheme) or the legacy "ydb://endpoint/database?auth-token=xxx"
+// url.
 func (d *legacyDriver) OpenConnector(name string) (driver.Connector, error) {
 	u, err := url.ParseRequestURI(name)
 	if err != nil {
 		return nil, err
 	}
+	if u.Scheme == "grpc" || u.Scheme == "grpcs" {
+		return Connector(WithDriverOptions(
+			ydb.WithConnectionString(name),
+		)), nil
+	}
 	if err := validateURL(u); err != nil {
 		return nil, err
 	}
@@ -70,3 +70,15 @@ func TestLegacyDriverOpen(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestLegacyDriverConnectionString(t *testing.T) {
+	c, err := new(legacyDriver).OpenConnector(
+		"grpcs://endpoint:2135/?database=/mydb&auth=anonymous",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.(*connector).driverOptions); n != 1 {
+		t.Fatalf("unexpected number of driver options: %d", n)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Package ydbtest provides tools for testing code which uses ydb driver.
+*/
+package ydbtest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/internal/clustertest"
+	"github.com/yandex-cloud/ydb-go-sdk/timeutil/timetest"
+)
+
+// ErrNoOnlineEndpoints is returned by FakeCluster when there are no online
+// endpoints to process an operation.
+var ErrNoOnlineEndpoints = errors.New("ydbtest: no online endpoints")
+
+// FakeEndpoint describes scripted behavior of a single endpoint of
+// FakeCluster.
+type FakeEndpoint struct {
+	ydb.Endpoint
+
+	// Latency is an optional function returning duration of i-th operation
+	// handled by the endpoint (i starts from 0).
+	// If Latency is nil then operations take no time.
+	Latency func(i int) time.Duration
+
+	// Error is an optional function returning result of i-th operation
+	// handled by the endpoint (i starts from 0).
+	// If Error is nil then all operations succeed.
+	Error func(i int) error
+}
+
+// FakeCluster is an in-memory cluster of scripted endpoints which uses the
+// same balancing logic as the driver returned by ydb.Dial() with the same
+// configuration.
+//
+// It is intended for deterministic testing of balancing options (such as
+// BalancingMethod, PreferLocalEndpoints or user defined Balancer):
+// FakeCluster has its own time which is changed only by Advance() calls and
+// by latencies of the processed operations, and operations are processed
+// synchronously by Call().
+//
+// FakeCluster is safe for use by multiple goroutines simultaneously.
+type FakeCluster struct {
+	mu        sync.Mutex
+	clock     *timetest.Clock
+	cluster   clustertest.Cluster
+	endpoints []FakeEndpoint
+	calls     []int
+	index     map[string]int
+}
+
+// NewFakeCluster creates new FakeCluster with balancer described by given
+// config and online endpoints es.
+// Note that config may be nil.
+func NewFakeCluster(config *ydb.DriverConfig, es ...FakeEndpoint) *FakeCluster {
+	c := &FakeCluster{
+		clock:     timetest.NewClock(time.Unix(0, 0)),
+		endpoints: es,
+		calls:     make([]int, len(es)),
+		index:     make(map[string]int, len(es)),
+	}
+	c.cluster = clustertest.New(config, c.clock)
+	for _, e := range es {
+		addr := endpointAddr(e.Addr, e.Port)
+		if _, has := c.index[addr]; has {
+			panic(fmt.Sprintf("ydbtest: duplicate endpoint %s", addr))
+		}
+		c.index[addr] = c.cluster.Add(e.Addr, e.Port, e.LoadFactor, e.Local)
+	}
+	return c
+}
+
+// Call selects endpoint by the balancer and processes single operation on it.
+// It returns selected endpoint and scripted operation result. Cluster's time
+// is shifted by the operation latency.
+// If there are no online endpoints, Call returns ErrNoOnlineEndpoints.
+func (c *FakeCluster) Call() (ydb.Endpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := c.cluster.Next()
+	if i < 0 {
+		return ydb.Endpoint{}, ErrNoOnlineEndpoints
+	}
+	e := c.endpoints[i]
+	n := c.calls[i]
+	c.calls[i]++
+
+	var (
+		latency time.Duration
+		err     error
+	)
+	if f := e.Latency; f != nil {
+		latency = f(n)
+	}
+	if f := e.Error; f != nil {
+		err = f(n)
+	}
+	start := c.clock.Now()
+	c.cluster.OperationStart(i, start)
+	c.clock.Shift(latency)
+	c.cluster.OperationDone(i, start, c.clock.Now(), err)
+
+	return e.Endpoint, err
+}
+
+// Now returns cluster's current time.
+func (c *FakeCluster) Now() time.Time {
+	return c.clock.Now()
+}
+
+// Advance shifts cluster's time by d.
+func (c *FakeCluster) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock.Shift(d)
+}
+
+// SetOnline marks endpoint with given address as online or offline. Offline
+// endpoints are not used for operations.
+// It panics if there is no such endpoint in the cluster.
+func (c *FakeCluster) SetOnline(addr string, port int, online bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, has := c.index[endpointAddr(addr, port)]
+	if !has {
+		panic(fmt.Sprintf("ydbtest: unknown endpoint %s", endpointAddr(addr, port)))
+	}
+	c.cluster.SetOnline(i, online)
+}
+
+// Stats iterates over all cluster endpoints and its connection stats in order
+// of endpoints passed to NewFakeCluster().
+func (c *FakeCluster) Stats(it func(ydb.Endpoint, ydb.ConnStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, e := range c.endpoints {
+		it(e.Endpoint, c.cluster.Stats(i).(ydb.ConnStats))
+	}
+}
+
+func endpointAddr(addr string, port int) string {
+	return fmt.Sprintf("%s:%d", addr, port)
+}
@@ -0,0 +1,166 @@
+package ydbtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+)
+
+func TestFakeClusterPreferLocal(t *testing.T) {
+	c := NewFakeCluster(
+		&ydb.DriverConfig{
+			BalancingMethod:      ydb.BalancingRoundRobin,
+			PreferLocalEndpoints: true,
+		},
+		FakeEndpoint{Endpoint: ydb.Endpoint{Addr: "a", Port: 1, Local: true}},
+		FakeEndpoint{Endpoint: ydb.Endpoint{Addr: "b", Port: 1}},
+		FakeEndpoint{Endpoint: ydb.Endpoint{Addr: "c", Port: 1}},
+	)
+	for i := 0; i < 10; i++ {
+		e, err := c.Call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Addr != "a" {
+			t.Fatalf("unexpected endpoint: %q; want local one", e.Addr)
+		}
+	}
+
+	c.SetOnline("a", 1, false)
+	calls := make(map[string]int)
+	for i := 0; i < 10; i++ {
+		e, err := c.Call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls[e.Addr]++
+	}
+	if calls["a"] != 0 || calls["b"] != 5 || calls["c"] != 5 {
+		t.Fatalf("unexpected calls distribution: %v", calls)
+	}
+
+	c.SetOnline("b", 1, false)
+	c.SetOnline("c", 1, false)
+	if _, err := c.Call(); err != ErrNoOnlineEndpoints {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFakeClusterP2C(t *testing.T) {
+	errFail := errors.New("fail")
+	c := NewFakeCluster(
+		&ydb.DriverConfig{
+			BalancingMethod: ydb.BalancingP2C,
+		},
+		FakeEndpoint{
+			Endpoint: ydb.Endpoint{Addr: "a", Port: 1},
+			Error: func(int) error {
+				return errFail
+			},
+		},
+		FakeEndpoint{
+			Endpoint: ydb.Endpoint{Addr: "b", Port: 1},
+			Latency: func(int) time.Duration {
+				return 10 * time.Millisecond
+			},
+		},
+	)
+	// Warm up both endpoints and make their stats available.
+	for i := 0; i < 10; i++ {
+		_, _ = c.Call()
+	}
+	c.Advance(5 * time.Second)
+
+	// Note that p2c may choose the same endpoint twice, so the failing one
+	// still gets some small part of operations.
+	calls := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		e, _ := c.Call()
+		calls[e.Addr]++
+	}
+	if calls["a"]*4 > calls["b"] {
+		t.Fatalf("unexpected calls distribution: %v", calls)
+	}
+	c.Stats(func(e ydb.Endpoint, s ydb.ConnStats) {
+		if e.Addr == "b" && s.AvgOpTime != 10*time.Millisecond {
+			t.Errorf("unexpected average op time: %s", s.AvgOpTime)
+		}
+	})
+}
+
+func TestFakeClusterLatency(t *testing.T) {
+	c := NewFakeCluster(nil, FakeEndpoint{
+		Endpoint: ydb.Endpoint{Addr: "a", Port: 1},
+		Latency: func(i int) time.Duration {
+			return time.Duration(i+1) * time.Second
+		},
+	})
+	start := c.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.Call(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if act, exp := c.Now().Sub(start), 6*time.Second; act != exp {
+		t.Fatalf("unexpected time shift: %s; want %s", act, exp)
+	}
+}
+
+// lastBalancer is a user defined balancer which always chooses the most
+// recently inserted connection.
+type lastBalancer struct {
+	conns []ydb.BalancerConn
+}
+
+func (b *lastBalancer) Next() ydb.BalancerConn {
+	if len(b.conns) == 0 {
+		return nil
+	}
+	return b.conns[len(b.conns)-1]
+}
+
+func (b *lastBalancer) Insert(c ydb.BalancerConn, _ ydb.BalancerConnInfo) ydb.BalancerElement {
+	b.conns = append(b.conns, c)
+	return c
+}
+
+func (b *lastBalancer) Update(ydb.BalancerElement, ydb.BalancerConnInfo) {}
+
+func (b *lastBalancer) Remove(x ydb.BalancerElement) {
+	for i, c := range b.conns {
+		if c == x {
+			b.conns = append(b.conns[:i], b.conns[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestFakeClusterCustomBalancer(t *testing.T) {
+	c := NewFakeCluster(
+		&ydb.DriverConfig{
+			Balancer: new(lastBalancer),
+		},
+		FakeEndpoint{Endpoint: ydb.Endpoint{Addr: "a", Port: 1}},
+		FakeEndpoint{Endpoint: ydb.Endpoint{Addr: "b", Port: 1}},
+	)
+	for _, exp := range []string{"b", "b"} {
+		e, err := c.Call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Addr != exp {
+			t.Fatalf("unexpected endpoint: %q; want %q", e.Addr, exp)
+		}
+	}
+	c.SetOnline("b", 1, false)
+	if e, _ := c.Call(); e.Addr != "a" {
+		t.Fatalf("unexpected endpoint: %q; want %q", e.Addr, "a")
+	}
+	c.Stats(func(e ydb.Endpoint, s ydb.ConnStats) {
+		if exp := map[string]uint64{"a": 1, "b": 2}[e.Addr]; s.OpSucceed != exp {
+			t.Errorf("unexpected number of operations on %q: %d; want %d", e.Addr, s.OpSucceed, exp)
+		}
+	})
+}
@@ -0,0 +1,460 @@
+package yql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/table"
+)
+
+// BatchParam is a name of parameter containing list of rows for queries
+// built with Batch() option.
+const BatchParam = "$rows"
+
+var (
+	errBatchNotSupported = errors.New("ydb: yql: batch is not supported for this query")
+	errEmptyBatch        = errors.New("ydb: yql: empty batch")
+	errNilRow            = errors.New("ydb: yql: nil row")
+)
+
+type queryKind uint8
+
+const (
+	querySelect queryKind = iota
+	queryInsert
+	queryUpsert
+	queryReplace
+	queryUpdate
+	queryDelete
+)
+
+func (k queryKind) String() string {
+	switch k {
+	case querySelect:
+		return "SELECT"
+	case queryInsert:
+		return "INSERT"
+	case queryUpsert:
+		return "UPSERT"
+	case queryReplace:
+		return "REPLACE"
+	case queryUpdate:
+		return "UPDATE"
+	case queryDelete:
+		return "DELETE"
+	default:
+		return "unknown"
+	}
+}
+
+// Query is a builder of a simple query over single table.
+//
+// Query methods return the same query such that calls may be chained. Errors
+// (such as unknown column names) are deferred until Build() or Params() call.
+type Query struct {
+	table *Table
+	kind  queryKind
+
+	columns []column
+	where   []column
+	order   []column
+	limit   int
+	batch   bool
+	view    string
+
+	err error
+}
+
+// Select returns query which selects given columns of rows. If no columns
+// given, all table columns are selected.
+func (t *Table) Select(columns ...string) *Query {
+	return t.query(querySelect, columns)
+}
+
+// Insert returns query which inserts row with all table columns.
+func (t *Table) Insert() *Query {
+	return t.query(queryInsert, nil)
+}
+
+// Upsert returns query which upserts row with all table columns.
+func (t *Table) Upsert() *Query {
+	return t.query(queryUpsert, nil)
+}
+
+// Replace returns query which replaces row with all table columns.
+func (t *Table) Replace() *Query {
+	return t.query(queryReplace, nil)
+}
+
+// Update returns query which updates given columns of rows. If no columns
+// given, all table columns except the ones passed to Where() are updated.
+func (t *Table) Update(columns ...string) *Query {
+	return t.query(queryUpdate, columns)
+}
+
+// Delete returns query which deletes rows.
+// Note that without Where() call all table rows are deleted.
+func (t *Table) Delete() *Query {
+	return t.query(queryDelete, nil)
+}
+
+func (t *Table) query(kind queryKind, columns []string) *Query {
+	q := &Query{
+		table: t,
+		kind:  kind,
+	}
+	q.columns = q.lookup(columns)
+	return q
+}
+
+// Where adds the condition of equality of given columns to the values of
+// appropriate parameters.
+func (q *Query) Where(columns ...string) *Query {
+	q.where = append(q.where, q.lookup(columns)...)
+	return q
+}
+
+// OrderBy sets the columns to order selected rows by.
+func (q *Query) OrderBy(columns ...string) *Query {
+	q.order = append(q.order, q.lookup(columns)...)
+	return q
+}
+
+// Limit sets the maximum number of selected rows.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// View makes select query to read rows through the secondary index with given
+// name, i.e. to use the VIEW clause. Columns passed to Where() should be the
+// prefix of the index columns for the index to be effective.
+func (q *Query) View(index string) *Query {
+	q.view = index
+	return q
+}
+
+// Batch makes insert, upsert or replace query to write list of rows passed as
+// a single BatchParam parameter.
+func (q *Query) Batch() *Query {
+	switch q.kind {
+	case queryInsert, queryUpsert, queryReplace:
+		q.batch = true
+	default:
+		q.fail(errBatchNotSupported)
+	}
+	return q
+}
+
+func (q *Query) lookup(names []string) []column {
+	cs := make([]column, 0, len(names))
+	for _, name := range names {
+		c, err := q.table.column(name)
+		if err != nil {
+			q.fail(err)
+			continue
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+func (q *Query) fail(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+func (q *Query) check() error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.kind != querySelect && (len(q.order) > 0 || q.limit > 0) {
+		return fmt.Errorf(
+			"ydb: yql: order and limit are not supported for %s query",
+			q.kind,
+		)
+	}
+	if q.kind != querySelect && q.view != "" {
+		return fmt.Errorf(
+			"ydb: yql: view is not supported for %s query",
+			q.kind,
+		)
+	}
+	if q.kind != querySelect && q.kind != queryUpdate && q.kind != queryDelete && len(q.where) > 0 {
+		return fmt.Errorf(
+			"ydb: yql: where is not supported for %s query",
+			q.kind,
+		)
+	}
+	for _, c := range q.params() {
+		if !isIdent(c.name) {
+			return fmt.Errorf(
+				"ydb: yql: column %q can not be used as parameter name",
+				c.name,
+			)
+		}
+	}
+	return nil
+}
+
+// selected returns columns used for select, write or update.
+func (q *Query) selected() []column {
+	if len(q.columns) > 0 {
+		return q.columns
+	}
+	if q.kind != queryUpdate {
+		return q.table.columns
+	}
+	var cs []column
+	for _, c := range q.table.columns {
+		if !hasColumn(q.where, c.name) {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// params returns columns which are passed as query parameters.
+func (q *Query) params() (cs []column) {
+	add := func(c column) {
+		if !hasColumn(cs, c.name) {
+			cs = append(cs, c)
+		}
+	}
+	switch q.kind {
+	case queryInsert, queryUpsert, queryReplace, queryUpdate:
+		for _, c := range q.selected() {
+			add(c)
+		}
+	}
+	for _, c := range q.where {
+		add(c)
+	}
+	return cs
+}
+
+// Build returns the text of query.
+func (q *Query) Build() (string, error) {
+	if err := q.check(); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	q.writeDeclare(&buf)
+	switch q.kind {
+	case querySelect:
+		buf.WriteString("SELECT ")
+		writeColumns(&buf, q.selected())
+		buf.WriteString(" FROM ")
+		writeIdent(&buf, q.table.name)
+		if q.view != "" {
+			buf.WriteString(" VIEW ")
+			writeIdent(&buf, q.view)
+		}
+		q.writeWhere(&buf)
+		if len(q.order) > 0 {
+			buf.WriteString(" ORDER BY ")
+			writeColumns(&buf, q.order)
+		}
+		if q.limit > 0 {
+			buf.WriteString(" LIMIT ")
+			buf.WriteString(strconv.Itoa(q.limit))
+		}
+
+	case queryInsert, queryUpsert, queryReplace:
+		cs := q.selected()
+		buf.WriteString(q.kind.String())
+		buf.WriteString(" INTO ")
+		writeIdent(&buf, q.table.name)
+		if q.batch {
+			buf.WriteString(" SELECT ")
+			writeColumns(&buf, cs)
+			buf.WriteString(" FROM AS_TABLE(")
+			buf.WriteString(BatchParam)
+			buf.WriteString(")")
+			break
+		}
+		buf.WriteString(" (")
+		writeColumns(&buf, cs)
+		buf.WriteString(") VALUES (")
+		for i, c := range cs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("$" + c.name)
+		}
+		buf.WriteString(")")
+
+	case queryUpdate:
+		buf.WriteString("UPDATE ")
+		writeIdent(&buf, q.table.name)
+		buf.WriteString(" SET ")
+		for i, c := range q.selected() {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeIdent(&buf, c.name)
+			buf.WriteString(" = $" + c.name)
+		}
+		q.writeWhere(&buf)
+
+	case queryDelete:
+		buf.WriteString("DELETE FROM ")
+		writeIdent(&buf, q.table.name)
+		q.writeWhere(&buf)
+	}
+	buf.WriteString(";")
+	return buf.String(), nil
+}
+
+func (q *Query) writeDeclare(buf *bytes.Buffer) {
+	if q.batch {
+		cs := q.selected()
+		fs := make([]ydb.StructOption, len(cs))
+		for i, c := range cs {
+			fs[i] = ydb.StructField(c.name, c.ydbType())
+		}
+		fmt.Fprintf(buf, "DECLARE %s AS %s;\n\n", BatchParam, ydb.List(ydb.Struct(fs...)))
+		return
+	}
+	cs := q.params()
+	for _, c := range cs {
+		fmt.Fprintf(buf, "DECLARE $%s AS %s;\n", c.name, c.ydbType())
+	}
+	if len(cs) > 0 {
+		buf.WriteString("\n")
+	}
+}
+
+func (q *Query) writeWhere(buf *bytes.Buffer) {
+	for i, c := range q.where {
+		if i == 0 {
+			buf.WriteString(" WHERE ")
+		} else {
+			buf.WriteString(" AND ")
+		}
+		writeIdent(buf, c.name)
+		buf.WriteString(" = $" + c.name)
+	}
+}
+
+// Params returns query parameters filled with values of row fields.
+//
+// Row must be of the same type used for NewTable() call or a pointer to it.
+// If query is built with the Batch() option, row must be a slice of such
+// values.
+func (q *Query) Params(row interface{}) (*table.QueryParameters, error) {
+	if err := q.check(); err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(row)
+	if q.batch {
+		if v.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("ydb: yql: batch rows must be a slice; got %T", row)
+		}
+		n := v.Len()
+		if n == 0 {
+			return nil, errEmptyBatch
+		}
+		cs := q.selected()
+		list := make([]ydb.Value, n)
+		for i := 0; i < n; i++ {
+			r, err := q.row(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			fs := make([]ydb.StructValueOption, len(cs))
+			for j, c := range cs {
+				x, err := c.value(r)
+				if err != nil {
+					return nil, err
+				}
+				fs[j] = ydb.StructFieldValue(c.name, x)
+			}
+			list[i] = ydb.StructValue(fs...)
+		}
+		return table.NewQueryParameters(
+			table.ValueParam(BatchParam, ydb.ListValue(list...)),
+		), nil
+	}
+	r, err := q.row(v)
+	if err != nil {
+		return nil, err
+	}
+	params := table.NewQueryParameters()
+	for _, c := range q.params() {
+		x, err := c.value(r)
+		if err != nil {
+			return nil, err
+		}
+		params.Add(table.ValueParam("$"+c.name, x))
+	}
+	return params, nil
+}
+
+func (q *Query) row(v reflect.Value) (reflect.Value, error) {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return v, errNilRow
+	}
+	if v.Type() != q.table.typ {
+		return v, fmt.Errorf(
+			"ydb: yql: unexpected row type: %s; want %s",
+			v.Type(), q.table.typ,
+		)
+	}
+	return v, nil
+}
+
+func hasColumn(cs []column, name string) bool {
+	for _, c := range cs {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func writeColumns(buf *bytes.Buffer, cs []column) {
+	for i, c := range cs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writeIdent(buf, c.name)
+	}
+}
+
+func writeIdent(buf *bytes.Buffer, name string) {
+	buf.WriteByte('`')
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c == '`' || c == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(name[i])
+	}
+	buf.WriteByte('`')
+}
+
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_':
+		case 'a' <= c && c <= 'z':
+		case 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package yql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/opt"
+)
+
+type series struct {
+	ID      uint64 `ydb:"column:series_id"`
+	Title   string
+	Info    *string
+	Views   opt.Int64
+	Cache   []byte `ydb:"-"`
+	private int
+}
+
+func TestQueryBuild(t *testing.T) {
+	tbl, err := NewTable("series", series{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name  string
+		query *Query
+		exp   string
+	}{
+		{
+			name:  "select",
+			query: tbl.Select("series_id", "title").Where("series_id").OrderBy("title").Limit(10),
+			exp: strings.Join([]string{
+				"DECLARE $series_id AS Uint64;",
+				"",
+				"SELECT `series_id`, `title` FROM `series` WHERE `series_id` = $series_id ORDER BY `title` LIMIT 10;",
+			}, "\n"),
+		},
+		{
+			name:  "select view",
+			query: tbl.Select("series_id").View("title_index").Where("title"),
+			exp: strings.Join([]string{
+				"DECLARE $title AS Utf8;",
+				"",
+				"SELECT `series_id` FROM `series` VIEW `title_index` WHERE `title` = $title;",
+			}, "\n"),
+		},
+		{
+			name:  "upsert",
+			query: tbl.Upsert(),
+			exp: strings.Join([]string{
+				"DECLARE $series_id AS Uint64;",
+				"DECLARE $title AS Utf8;",
+				"DECLARE $info AS Optional<Utf8>;",
+				"DECLARE $views AS Optional<Int64>;",
+				"",
+				"UPSERT INTO `series` (`series_id`, `title`, `info`, `views`) VALUES ($series_id, $title, $info, $views);",
+			}, "\n"),
+		},
+		{
+			name:  "batch",
+			query: tbl.Replace().Batch(),
+			exp: strings.Join([]string{
+				"DECLARE $rows AS List<Struct<series_id:Uint64,title:Utf8,info:Optional<Utf8>,views:Optional<Int64>>>;",
+				"",
+				"REPLACE INTO `series` SELECT `series_id`, `title`, `info`, `views` FROM AS_TABLE($rows);",
+			}, "\n"),
+		},
+		{
+			name:  "update",
+			query: tbl.Update().Where("series_id"),
+			exp: strings.Join([]string{
+				"DECLARE $title AS Utf8;",
+				"DECLARE $info AS Optional<Utf8>;",
+				"DECLARE $views AS Optional<Int64>;",
+				"DECLARE $series_id AS Uint64;",
+				"",
+				"UPDATE `series` SET `title` = $title, `info` = $info, `views` = $views WHERE `series_id` = $series_id;",
+			}, "\n"),
+		},
+		{
+			name:  "delete",
+			query: tbl.Delete().Where("series_id"),
+			exp: strings.Join([]string{
+				"DECLARE $series_id AS Uint64;",
+				"",
+				"DELETE FROM `series` WHERE `series_id` = $series_id;",
+			}, "\n"),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			act, err := test.query.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.exp {
+				t.Errorf("unexpected query:\n%s\nwant:\n%s", act, test.exp)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tbl, err := NewTable("series", series{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []*Query{
+		tbl.Select().Where("unknown"),
+		tbl.Delete().Batch(),
+		tbl.Upsert().Where("series_id"),
+		tbl.Update().Limit(1),
+		tbl.Delete().View("title_index"),
+	} {
+		if _, err := q.Build(); err == nil {
+			t.Errorf("expected error")
+		}
+	}
+}
+
+func TestQueryParams(t *testing.T) {
+	tbl, err := NewTable("series", series{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := "info"
+	params, err := tbl.Upsert().Params(&series{
+		ID:    42,
+		Title: "title",
+		Info:  &info,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	act := make(map[string]string)
+	params.Each(func(name string, v ydb.Value) {
+		act[name] = fmt.Sprint(v)
+	})
+	exp := map[string]string{
+		"$series_id": "Uint64(42)",
+		"$title":     "Utf8(title)",
+		"$info":      "Optional<Utf8>(info)",
+		"$views":     "Optional<Int64>(NULL)",
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected params: %v; want %v", act, exp)
+	}
+
+	if _, err := tbl.Upsert().Batch().Params([]series{{ID: 1}, {ID: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tbl.Upsert().Params(struct{}{}); err == nil {
+		t.Errorf("expected error")
+	}
+	if _, err := tbl.Upsert().Params(nil); err != errNilRow {
+		t.Errorf("unexpected error: %v; want %v", err, errNilRow)
+	}
+}
+
+func TestNewTableErrors(t *testing.T) {
+	for _, row := range []interface{}{
+		42,
+		struct {
+			A uint64 `ydb:"type:utf8"`
+		}{},
+		struct {
+			A *uint64 `ydb:"type:uint64"`
+		}{},
+		struct {
+			A uint64 `ydb:"column:a"`
+			B uint64 `ydb:"column:a"`
+		}{},
+		struct {
+			A chan int
+		}{},
+	} {
+		if _, err := NewTable("t", row); err == nil {
+			t.Errorf("expected error for %T", row)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Package yql provides helpers for safe assembly of simple YQL queries.
+
+It is not an ORM: it just produces the query text with DECLARE statements and
+appropriate query parameters for the simple select/insert/update/delete
+queries over a single table. Table columns are derived from the struct fields
+and its tags:
+
+	type Series struct {
+		ID      uint64    `ydb:"column:series_id"`
+		Title   string
+		Info    *string
+		Release time.Time `ydb:"type:date?"`
+		Cache   []byte    `ydb:"-"`
+	}
+
+	t, err := yql.NewTable("series", Series{})
+	if err != nil {
+		// handle error
+	}
+	q := t.Select().Where("series_id")
+	text, err := q.Build()
+	if err != nil {
+		// handle error
+	}
+	params, err := q.Params(Series{ID: 42})
+	if err != nil {
+		// handle error
+	}
+
+Supported tag keys are the same as for the ydbgen tool: "column" to set the
+column name (by default it is field name in snake case), "type" to set the
+column type and "-" to skip the field. Types ending with "?" are optional.
+Fields of pointer types and types with Get() (T, bool) method (such as types
+from opt package) are treated as optional.
+*/
+package yql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+	"github.com/yandex-cloud/ydb-go-sdk/internal/naming"
+	"github.com/yandex-cloud/ydb-go-sdk/internal/ydbtypes"
+)
+
+// Table describes table which columns are derived from struct fields.
+type Table struct {
+	name    string
+	typ     reflect.Type
+	columns []column
+	index   map[string]int
+}
+
+type column struct {
+	name     string
+	field    []int
+	typ      internal.PrimitiveType
+	optional bool
+}
+
+func (c column) ydbType() ydb.Type {
+	if c.optional {
+		return ydb.Optional(c.typ)
+	}
+	return c.typ
+}
+
+// NewTable creates table description with given name and columns derived from
+// the fields of struct row. Row may be a struct or a pointer to a struct.
+func NewTable(name string, row interface{}) (*Table, error) {
+	typ := reflect.TypeOf(row)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ydb: yql: row must be a struct; got %T", row)
+	}
+	t := &Table{
+		name:  name,
+		typ:   typ,
+		index: make(map[string]int),
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+		c, skip, err := parseField(f)
+		if err != nil {
+			return nil, fmt.Errorf("ydb: yql: field %q: %v", f.Name, err)
+		}
+		if skip {
+			continue
+		}
+		if _, has := t.index[c.name]; has {
+			return nil, fmt.Errorf("ydb: yql: duplicate column %q", c.name)
+		}
+		t.index[c.name] = len(t.columns)
+		t.columns = append(t.columns, c)
+	}
+	if len(t.columns) == 0 {
+		return nil, fmt.Errorf("ydb: yql: no columns in %s", typ)
+	}
+	return t, nil
+}
+
+// Name returns table name.
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Columns returns names of the table columns in order of struct fields.
+func (t *Table) Columns() []string {
+	names := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func (t *Table) column(name string) (column, error) {
+	i, ok := t.index[name]
+	if !ok {
+		return column{}, fmt.Errorf("ydb: yql: unknown column %q", name)
+	}
+	return t.columns[i], nil
+}
+
+func parseField(f reflect.StructField) (c column, skip bool, err error) {
+	c.name = naming.CamelToSnake(f.Name)
+	c.field = f.Index
+
+	var typs string
+	if tag, ok := f.Tag.Lookup("ydb"); ok {
+		for _, pair := range strings.Split(tag, ",") {
+			key, value := splitPair(pair, ':')
+			switch key {
+			case "-":
+				return c, true, nil
+			case "column":
+				c.name = value
+			case "type":
+				typs = value
+			case "conv", "pos", "container":
+				// Used by ydbgen only.
+			default:
+				return c, false, fmt.Errorf("unexpected tag key: %q", key)
+			}
+		}
+	}
+
+	base, optional := baseType(f.Type)
+	if typs != "" {
+		if n := len(typs); typs[n-1] == '?' {
+			c.optional = true
+			typs = typs[:n-1]
+		}
+		c.typ, err = ydbtypes.PrimitiveTypeFromString(typs)
+		if err != nil {
+			return c, false, err
+		}
+		if optional && !c.optional {
+			return c, false, fmt.Errorf(
+				"optional field %s for non-optional type %s",
+				f.Type, c.typ,
+			)
+		}
+	} else {
+		c.optional = optional
+		c.typ, err = primitiveTypeOf(base)
+		if err != nil {
+			return c, false, err
+		}
+	}
+	if !convertible(base, c.typ) {
+		return c, false, fmt.Errorf(
+			"field of type %s can not be used as %s",
+			f.Type, c.typ,
+		)
+	}
+	return c, false, nil
+}
+
+func splitPair(p string, sep byte) (key, value string) {
+	i := strings.IndexByte(p, sep)
+	if i == -1 {
+		return p, ""
+	}
+	return p[:i], p[i+1:]
+}
+
+var (
+	typeTime     = reflect.TypeOf(time.Time{})
+	typeDuration = reflect.TypeOf(time.Duration(0))
+	typeBytes    = reflect.TypeOf([]byte(nil))
+	typeUUID     = reflect.TypeOf([16]byte{})
+)
+
+// baseType returns type of the value stored in field of type t and reports
+// whether t represents optional value.
+func baseType(t reflect.Type) (_ reflect.Type, optional bool) {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem(), true
+	}
+	if m, ok := t.MethodByName("Get"); ok && isGetter(m.Type) {
+		return m.Type.Out(0), true
+	}
+	return t, false
+}
+
+func isGetter(t reflect.Type) bool {
+	// Note that method type contains receiver as the first argument.
+	return t.NumIn() == 1 &&
+		t.NumOut() == 2 &&
+		t.Out(1).Kind() == reflect.Bool
+}
+
+func primitiveTypeOf(t reflect.Type) (internal.PrimitiveType, error) {
+	switch t {
+	case typeTime:
+		return ydb.TypeTimestamp, nil
+	case typeDuration:
+		return ydb.TypeInterval, nil
+	case typeBytes:
+		return ydb.TypeString, nil
+	case typeUUID:
+		return ydb.TypeUUID, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return ydb.TypeBool, nil
+	case reflect.Int8:
+		return ydb.TypeInt8, nil
+	case reflect.Int16:
+		return ydb.TypeInt16, nil
+	case reflect.Int32:
+		return ydb.TypeInt32, nil
+	case reflect.Int, reflect.Int64:
+		return ydb.TypeInt64, nil
+	case reflect.Uint8:
+		return ydb.TypeUint8, nil
+	case reflect.Uint16:
+		return ydb.TypeUint16, nil
+	case reflect.Uint32:
+		return ydb.TypeUint32, nil
+	case reflect.Uint, reflect.Uint64:
+		return ydb.TypeUint64, nil
+	case reflect.Float32:
+		return ydb.TypeFloat, nil
+	case reflect.Float64:
+		return ydb.TypeDouble, nil
+	case reflect.String:
+		return ydb.TypeUTF8, nil
+	}
+	return ydb.TypeUnknown, fmt.Errorf("unsupported field type: %s", t)
+}
+
+func isInt(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isText(t reflect.Type) bool {
+	return t.Kind() == reflect.String || t == typeBytes
+}
+
+// convertible reports whether value of type t may be converted to the value
+// of type p.
+func convertible(t reflect.Type, p internal.PrimitiveType) bool {
+	switch p {
+	case ydb.TypeBool:
+		return t.Kind() == reflect.Bool
+	case
+		ydb.TypeInt8,
+		ydb.TypeInt16,
+		ydb.TypeInt32,
+		ydb.TypeInt64:
+		return isInt(t) && t != typeDuration
+	case ydb.TypeInterval:
+		return isInt(t)
+	case
+		ydb.TypeUint8,
+		ydb.TypeUint16,
+		ydb.TypeUint32,
+		ydb.TypeUint64:
+		return isUint(t)
+	case ydb.TypeFloat, ydb.TypeDouble:
+		k := t.Kind()
+		return k == reflect.Float32 || k == reflect.Float64
+	case
+		ydb.TypeDate,
+		ydb.TypeDatetime,
+		ydb.TypeTimestamp,
+		ydb.TypeTzDate,
+		ydb.TypeTzDatetime,
+		ydb.TypeTzTimestamp:
+		return t == typeTime
+	case
+		ydb.TypeString,
+		ydb.TypeUTF8,
+		ydb.TypeYSON,
+		ydb.TypeJSON:
+		return isText(t)
+	case ydb.TypeUUID:
+		return t == typeUUID
+	}
+	return false
+}
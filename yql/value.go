@@ -0,0 +1,142 @@
+package yql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/yandex-cloud/ydb-go-sdk"
+	"github.com/yandex-cloud/ydb-go-sdk/internal"
+)
+
+// value returns ydb value of column c stored in the struct value row.
+func (c column) value(row reflect.Value) (ydb.Value, error) {
+	v := row.FieldByIndex(c.field)
+	switch {
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			return ydb.NullValue(c.typ), nil
+		}
+		v = v.Elem()
+
+	case c.optional && v.Type() != typeTime:
+		if m := v.MethodByName("Get"); m.IsValid() {
+			out := m.Call(nil)
+			if !out[1].Bool() {
+				return ydb.NullValue(c.typ), nil
+			}
+			v = out[0]
+		}
+	}
+	x, err := primitiveValue(c.typ, v)
+	if err != nil {
+		return nil, fmt.Errorf("ydb: yql: column %q: %v", c.name, err)
+	}
+	if c.optional {
+		return ydb.OptionalValue(x), nil
+	}
+	return x, nil
+}
+
+func primitiveValue(p internal.PrimitiveType, v reflect.Value) (ydb.Value, error) {
+	switch p {
+	case ydb.TypeBool:
+		return ydb.BoolValue(v.Bool()), nil
+
+	case ydb.TypeInt8:
+		x := v.Int()
+		if x < math.MinInt8 || x > math.MaxInt8 {
+			return nil, overflowError(x, p)
+		}
+		return ydb.Int8Value(int8(x)), nil
+	case ydb.TypeInt16:
+		x := v.Int()
+		if x < math.MinInt16 || x > math.MaxInt16 {
+			return nil, overflowError(x, p)
+		}
+		return ydb.Int16Value(int16(x)), nil
+	case ydb.TypeInt32:
+		x := v.Int()
+		if x < math.MinInt32 || x > math.MaxInt32 {
+			return nil, overflowError(x, p)
+		}
+		return ydb.Int32Value(int32(x)), nil
+	case ydb.TypeInt64:
+		return ydb.Int64Value(v.Int()), nil
+	case ydb.TypeInterval:
+		if v.Type() == typeDuration {
+			return ydb.IntervalValue(ydb.Duration(v.Int()).Interval()), nil
+		}
+		return ydb.IntervalValue(v.Int()), nil
+
+	case ydb.TypeUint8:
+		x := v.Uint()
+		if x > math.MaxUint8 {
+			return nil, overflowError(x, p)
+		}
+		return ydb.Uint8Value(uint8(x)), nil
+	case ydb.TypeUint16:
+		x := v.Uint()
+		if x > math.MaxUint16 {
+			return nil, overflowError(x, p)
+		}
+		return ydb.Uint16Value(uint16(x)), nil
+	case ydb.TypeUint32:
+		x := v.Uint()
+		if x > math.MaxUint32 {
+			return nil, overflowError(x, p)
+		}
+		return ydb.Uint32Value(uint32(x)), nil
+	case ydb.TypeUint64:
+		return ydb.Uint64Value(v.Uint()), nil
+
+	case ydb.TypeFloat:
+		return ydb.FloatValue(float32(v.Float())), nil
+	case ydb.TypeDouble:
+		return ydb.DoubleValue(v.Float()), nil
+
+	case ydb.TypeDate:
+		return ydb.DateValue(timeOf(v).Date()), nil
+	case ydb.TypeDatetime:
+		return ydb.DatetimeValue(timeOf(v).Datetime()), nil
+	case ydb.TypeTimestamp:
+		return ydb.TimestampValue(timeOf(v).Timestamp()), nil
+	case ydb.TypeTzDate:
+		return ydb.TzDateValue(timeOf(v).TzDate()), nil
+	case ydb.TypeTzDatetime:
+		return ydb.TzDatetimeValue(timeOf(v).TzDatetime()), nil
+	case ydb.TypeTzTimestamp:
+		return ydb.TzTimestampValue(timeOf(v).TzTimestamp()), nil
+
+	case ydb.TypeString:
+		return ydb.StringValue(bytesOf(v)), nil
+	case ydb.TypeUTF8:
+		return ydb.UTF8Value(string(bytesOf(v))), nil
+	case ydb.TypeYSON:
+		return ydb.YSONValue(string(bytesOf(v))), nil
+	case ydb.TypeJSON:
+		return ydb.JSONValue(string(bytesOf(v))), nil
+
+	case ydb.TypeUUID:
+		var x [16]byte
+		reflect.Copy(reflect.ValueOf(x[:]), v)
+		return ydb.UUIDValue(x), nil
+	}
+	return nil, fmt.Errorf("unsupported type: %s", p)
+}
+
+func timeOf(v reflect.Value) ydb.Time {
+	return ydb.Time(v.Interface().(time.Time))
+}
+
+func bytesOf(v reflect.Value) []byte {
+	if v.Kind() == reflect.String {
+		return []byte(v.String())
+	}
+	return v.Bytes()
+}
+
+func overflowError(x interface{}, p internal.PrimitiveType) error {
+	return fmt.Errorf("value %v overflows %s", x, p)
+}